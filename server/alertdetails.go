@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// Maximum number of alert detail entries shown on incident posts
+	maxAlertDetailEntries = 10
+
+	// Maximum length of alert details given as text
+	maxAlertDetailsLength = 500
+)
+
+// getAlertDetails gets the formatted details of an incident's first alert
+func (p *Plugin) getAlertDetails(incidentID string) string {
+	alerts, err := p.getPagerDutyClient().ListIncidentAlerts(incidentID)
+	if err != nil {
+		p.API.LogWarn("Failed to get incident alerts", "incident_id", incidentID, "error", err.Error())
+		return ""
+	}
+
+	if len(alerts) == 0 || alerts[0].Body == nil {
+		return ""
+	}
+
+	return formatAlertDetails(alerts[0].Body.Details)
+}
+
+// formatAlertDetails formats alert details as "key: value" lines, or as truncated text
+func formatAlertDetails(details interface{}) string {
+	switch details := details.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(details))
+		for key := range details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var lines []string
+		for i, key := range keys {
+			if i == maxAlertDetailEntries {
+				lines = append(lines, fmt.Sprintf("_and %d more_", len(keys)-maxAlertDetailEntries))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("**%s:** %v", key, details[key]))
+		}
+		return strings.Join(lines, "\n")
+
+	case string:
+		if runes := []rune(details); len(runes) > maxAlertDetailsLength {
+			return string(runes[:maxAlertDetailsLength]) + "…"
+		}
+		return details
+
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	// alertIngestSource is used when an ingested alert doesn't name its source
+	alertIngestSource = "mattermost-pagerduty-plugin"
+
+	// Default severity for ingested alerts without a recognizable severity
+	defaultAlertSeverity = "error"
+
+	// Maximum size of an ingested alert payload
+	maxAlertIngestBodySize = 1 << 20
+
+	// Outcomes of ingested alerts, reported for each alert of a payload
+	alertResultForwarded = "forwarded"
+	alertResultFailed    = "failed"
+)
+
+// ingestPayload covers the inbound alert formats accepted by the ingest endpoint:
+// a simple JSON object, an Alertmanager webhook, or a (legacy or unified) Grafana webhook.
+type ingestPayload struct {
+	// Simple format
+	Summary  string                 `json:"summary"`
+	Source   string                 `json:"source"`
+	Severity string                 `json:"severity"`
+	Status   string                 `json:"status"`
+	DedupKey string                 `json:"dedup_key"`
+	Details  map[string]interface{} `json:"details"`
+
+	// Alertmanager and Grafana unified alerting
+	Alerts      []alertmanagerAlert `json:"alerts"`
+	ExternalURL string              `json:"externalURL"`
+
+	// Legacy Grafana alerting
+	Title    string `json:"title"`
+	RuleName string `json:"ruleName"`
+	RuleURL  string `json:"ruleUrl"`
+	State    string `json:"state"`
+	Message  string `json:"message"`
+}
+
+// alertmanagerAlert is a single alert in an Alertmanager or Grafana webhook
+type alertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     string            `json:"startsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// alertIngestResult is the outcome of forwarding one alert of an ingested payload
+type alertIngestResult struct {
+	Status   string `json:"status"`
+	DedupKey string `json:"dedup_key,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// handleAlertIngest accepts alerts from external tools and forwards them to PagerDuty
+// via the Events API v2, posting a copy of each alert to Mattermost. The outcome of each alert
+// is reported, so a client retries only the alerts that failed instead of forwarding the
+// others again.
+func (p *Plugin) handleAlertIngest(w http.ResponseWriter, r *http.Request) {
+	config := p.getConfiguration()
+	if config.AlertIngestToken == "" || config.AlertIngestRoutingKey == "" {
+		http.Error(w, "Alert ingest is not configured", http.StatusForbidden)
+		return
+	}
+
+	if !p.verifyAlertIngestToken(r, config.AlertIngestToken) {
+		http.Error(w, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAlertIngestBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		p.API.LogError("Failed to read alert body", "error", err.Error())
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	events, err := parseIngestedAlerts(body)
+	if err != nil {
+		p.API.LogError("Failed to parse alert payload", "error", err.Error())
+		http.Error(w, "Invalid alert payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results := make([]alertIngestResult, 0, len(events))
+	failed := 0
+	for _, event := range events {
+		event.RoutingKey = config.AlertIngestRoutingKey
+
+		response, err := p.getPagerDutyClient().SendAlertEvent(event)
+		if err != nil {
+			p.API.LogError("Failed to forward alert to PagerDuty", "error", err.Error(), "dedup_key", event.DedupKey)
+			results = append(results, alertIngestResult{
+				Status:   alertResultFailed,
+				DedupKey: event.DedupKey,
+				Error:    "Failed to forward alert to PagerDuty",
+			})
+			failed++
+			continue
+		}
+
+		event.DedupKey = response.DedupKey
+		results = append(results, alertIngestResult{Status: alertResultForwarded, DedupKey: event.DedupKey})
+		if err := p.postIngestedAlert(event); err != nil {
+			// The alert reached PagerDuty, which is what matters most
+			p.API.LogWarn("Failed to post ingested alert", "error", err.Error(), "dedup_key", event.DedupKey)
+		}
+	}
+
+	// Only a payload none of whose alerts were forwarded is worth retrying as a whole
+	status := http.StatusAccepted
+	switch {
+	case failed == len(events):
+		status = http.StatusBadGateway
+	case failed > 0:
+		status = http.StatusMultiStatus
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"results": results}); err != nil {
+		p.API.LogWarn("Failed to write alert ingest results", "error", err.Error())
+	}
+}
+
+// verifyAlertIngestToken checks the token passed as a bearer token. Tokens in the query string
+// aren't accepted, since proxies and access logs record URLs.
+func (p *Plugin) verifyAlertIngestToken(r *http.Request, expected string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// parseIngestedAlerts converts an inbound alert payload into Events API v2 events
+func parseIngestedAlerts(body []byte) ([]*pagerduty.AlertEvent, error) {
+	var payload ingestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.Wrap(err, "failed to parse JSON")
+	}
+
+	var events []*pagerduty.AlertEvent
+
+	switch {
+	case len(payload.Alerts) > 0:
+		// Alertmanager or Grafana unified alerting
+		for _, alert := range payload.Alerts {
+			summary := alert.Annotations["summary"]
+			if summary == "" {
+				summary = alert.Labels["alertname"]
+			}
+
+			details := map[string]interface{}{}
+			for k, v := range alert.Labels {
+				details[k] = v
+			}
+			for k, v := range alert.Annotations {
+				details[k] = v
+			}
+
+			dedupKey := alert.Fingerprint
+			if dedupKey == "" {
+				dedupKey = alertLabelsKey(alert.Labels)
+			}
+
+			events = append(events, &pagerduty.AlertEvent{
+				EventAction: alertEventAction(alert.Status),
+				DedupKey:    dedupKey,
+				ClientURL:   firstNonEmpty(alert.GeneratorURL, payload.ExternalURL),
+				Payload: &pagerduty.AlertEventPayload{
+					Summary:       summary,
+					Source:        firstNonEmpty(alert.Labels["instance"], alert.Labels["job"], alertIngestSource),
+					Severity:      alertSeverity(alert.Labels["severity"]),
+					Timestamp:     alert.StartsAt,
+					CustomDetails: details,
+				},
+			})
+		}
+
+	case payload.RuleName != "":
+		// Legacy Grafana alerting
+		events = append(events, &pagerduty.AlertEvent{
+			EventAction: alertEventAction(payload.State),
+			DedupKey:    payload.RuleName,
+			ClientURL:   payload.RuleURL,
+			Payload: &pagerduty.AlertEventPayload{
+				Summary:  firstNonEmpty(payload.Title, payload.RuleName),
+				Source:   alertIngestSource,
+				Severity: defaultAlertSeverity,
+				CustomDetails: map[string]interface{}{
+					"message": payload.Message,
+				},
+			},
+		})
+
+	case payload.Summary != "":
+		// Simple format
+		events = append(events, &pagerduty.AlertEvent{
+			EventAction: alertEventAction(payload.Status),
+			DedupKey:    payload.DedupKey,
+			Payload: &pagerduty.AlertEventPayload{
+				Summary:       payload.Summary,
+				Source:        firstNonEmpty(payload.Source, alertIngestSource),
+				Severity:      alertSeverity(payload.Severity),
+				CustomDetails: payload.Details,
+			},
+		})
+
+	default:
+		return nil, errors.New("unrecognized alert format")
+	}
+
+	for _, event := range events {
+		if event.Payload.Summary == "" {
+			return nil, errors.New("alert summary is required")
+		}
+		if event.EventAction != client.EventActionTrigger && event.DedupKey == "" {
+			return nil, errors.New("dedup key is required to acknowledge or resolve an alert")
+		}
+	}
+
+	return events, nil
+}
+
+// alertEventAction maps an inbound alert status to an Events API v2 action
+func alertEventAction(status string) string {
+	switch strings.ToLower(status) {
+	case "resolved", "ok", "resolve":
+		return client.EventActionResolve
+	case "acknowledged", "acknowledge":
+		return client.EventActionAcknowledge
+	default:
+		return client.EventActionTrigger
+	}
+}
+
+// alertSeverity maps an inbound severity to one accepted by the Events API v2
+func alertSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "error", "warning", "info":
+		return strings.ToLower(severity)
+	case "page", "high", "fatal":
+		return "critical"
+	case "warn", "low":
+		return "warning"
+	default:
+		return defaultAlertSeverity
+	}
+}
+
+// alertLabelsKey builds a stable dedup key from a set of alert labels
+func alertLabelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+labels[k])
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// postIngestedAlert posts a copy of a forwarded alert to the default channel
+func (p *Plugin) postIngestedAlert(event *pagerduty.AlertEvent) error {
+	channelID, err := p.getChannelID()
+	if err != nil {
+		return errors.Wrap(err, "failed to get channel ID")
+	}
+
+	color := "#FFA500" // Orange for triggered alerts
+	if event.EventAction == client.EventActionResolve {
+		color = "#008000" // Green for resolved alerts
+	}
+
+	t := p.displayLanguage()
+	text := t("alert.forwarded", map[string]interface{}{"Action": event.EventAction})
+	if event.ClientURL != "" {
+		text += fmt.Sprintf(" · [%s](%s)", t("alert.field.source"), event.ClientURL)
+	}
+
+	attachment := &model.SlackAttachment{
+		Title: event.Payload.Summary,
+		Text:  text,
+		Color: color,
+		Fields: []*model.SlackAttachmentField{
+			{Title: t("alert.field.source"), Value: event.Payload.Source, Short: true},
+			{Title: t("alert.field.severity"), Value: event.Payload.Severity, Short: true},
+			{Title: t("alert.field.dedup_key"), Value: event.DedupKey, Short: false},
+		},
+	}
+
+	userID := p.botUserID
+	if userID == "" {
+		userID = "system"
+	}
+
+	post := &model.Post{
+		UserId:    userID,
+		ChannelId: channelID,
+		Props: model.StringInterface{
+			"attachments":  []*model.SlackAttachment{attachment},
+			"from_webhook": "true",
+		},
+	}
+
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		return errors.New("failed to create post: " + appErr.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client/mocks"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+func TestParseIngestedAlerts(t *testing.T) {
+	t.Run("simple format", func(t *testing.T) {
+		events, err := parseIngestedAlerts([]byte(`{"summary":"Disk full","source":"db-1","severity":"warn","dedup_key":"db-1-disk"}`))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+
+		assert.Equal(t, client.EventActionTrigger, events[0].EventAction)
+		assert.Equal(t, "db-1-disk", events[0].DedupKey)
+		assert.Equal(t, "Disk full", events[0].Payload.Summary)
+		assert.Equal(t, "db-1", events[0].Payload.Source)
+		assert.Equal(t, "warning", events[0].Payload.Severity)
+	})
+
+	t.Run("alertmanager format", func(t *testing.T) {
+		events, err := parseIngestedAlerts([]byte(`{
+			"status": "firing",
+			"externalURL": "http://alertmanager",
+			"alerts": [
+				{"status": "firing", "labels": {"alertname": "HighLatency", "severity": "critical", "instance": "api-1"}, "fingerprint": "abc"},
+				{"status": "resolved", "labels": {"alertname": "HighErrors"}, "annotations": {"summary": "Error rate high"}}
+			]
+		}`))
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		assert.Equal(t, client.EventActionTrigger, events[0].EventAction)
+		assert.Equal(t, "abc", events[0].DedupKey)
+		assert.Equal(t, "HighLatency", events[0].Payload.Summary)
+		assert.Equal(t, "api-1", events[0].Payload.Source)
+		assert.Equal(t, "critical", events[0].Payload.Severity)
+		assert.Equal(t, "http://alertmanager", events[0].ClientURL)
+
+		assert.Equal(t, client.EventActionResolve, events[1].EventAction)
+		assert.Equal(t, "alertname=HighErrors", events[1].DedupKey)
+		assert.Equal(t, "Error rate high", events[1].Payload.Summary)
+	})
+
+	t.Run("legacy grafana format", func(t *testing.T) {
+		events, err := parseIngestedAlerts([]byte(`{"title":"[Alerting] CPU","ruleName":"CPU","ruleUrl":"http://grafana/d/1","state":"ok","message":"CPU back to normal"}`))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+
+		assert.Equal(t, client.EventActionResolve, events[0].EventAction)
+		assert.Equal(t, "CPU", events[0].DedupKey)
+		assert.Equal(t, "[Alerting] CPU", events[0].Payload.Summary)
+	})
+
+	t.Run("resolve without dedup key", func(t *testing.T) {
+		_, err := parseIngestedAlerts([]byte(`{"summary":"Disk full","status":"resolved"}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("unrecognized format", func(t *testing.T) {
+		_, err := parseIngestedAlerts([]byte(`{"foo":"bar"}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestHandleAlertIngest(t *testing.T) {
+	setup := func(t *testing.T) (*Plugin, *mocks.MockPDClient) {
+		ctrl := gomock.NewController(t)
+		pdClient := mocks.NewMockPDClient(ctrl)
+
+		api := &plugintest.API{}
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+		p := &Plugin{pdClient: pdClient}
+		p.SetAPI(api)
+		p.setConfiguration(&configuration{AlertIngestToken: "token", AlertIngestRoutingKey: "routing-key"})
+		return p, pdClient
+	}
+
+	ingest := func(p *Plugin, body string) (*httptest.ResponseRecorder, []alertIngestResult) {
+		r := httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		p.handleAlertIngest(w, r)
+
+		var response struct {
+			Results []alertIngestResult `json:"results"`
+		}
+		_ = json.NewDecoder(w.Body).Decode(&response)
+		return w, response.Results
+	}
+
+	alerts := `{"alerts": [
+		{"status": "firing", "labels": {"alertname": "HighLatency"}, "fingerprint": "abc"},
+		{"status": "firing", "labels": {"alertname": "HighErrors"}, "fingerprint": "def"}
+	]}`
+
+	t.Run("reports the alerts that failed", func(t *testing.T) {
+		p, pdClient := setup(t)
+		pdClient.EXPECT().SendAlertEvent(gomock.Any()).Return(&pagerduty.AlertEventResponse{DedupKey: "abc"}, nil)
+		pdClient.EXPECT().SendAlertEvent(gomock.Any()).Return(nil, errors.New("unavailable"))
+
+		w, results := ingest(p, alerts)
+		assert.Equal(t, http.StatusMultiStatus, w.Code)
+		assert.Equal(t, []alertIngestResult{
+			{Status: alertResultForwarded, DedupKey: "abc"},
+			{Status: alertResultFailed, DedupKey: "def", Error: "Failed to forward alert to PagerDuty"},
+		}, results)
+	})
+
+	t.Run("fails when no alert is forwarded", func(t *testing.T) {
+		p, pdClient := setup(t)
+		pdClient.EXPECT().SendAlertEvent(gomock.Any()).Return(nil, errors.New("unavailable")).Times(2)
+
+		w, _ := ingest(p, alerts)
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+
+	t.Run("rejects payloads that are too large", func(t *testing.T) {
+		p, _ := setup(t)
+
+		w, _ := ingest(p, `{"summary": "`+strings.Repeat("x", maxAlertIngestBodySize)+`"}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("rejects tokens in the query string", func(t *testing.T) {
+		p, _ := setup(t)
+
+		r := httptest.NewRequest(http.MethodPost, "/alerts?token=token", strings.NewReader(alerts))
+		w := httptest.NewRecorder()
+		p.handleAlertIngest(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
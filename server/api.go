@@ -23,6 +23,49 @@ func (p *Plugin) ServeHTTP(c *plugin.Context, w http.ResponseWriter, r *http.Req
 	apiRouter.HandleFunc("/incidents/{incident_id}/acknowledge", p.handleAcknowledge).Methods(http.MethodPost)
 	apiRouter.HandleFunc("/incidents/{incident_id}/resolve", p.handleResolve).Methods(http.MethodPost)
 	apiRouter.HandleFunc("/incidents/{incident_id}/reassign", p.handleReassign).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/{incident_id}/payload", p.handleShowPayload).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/{incident_id}/snooze", p.handleSnooze).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/{incident_id}/note", p.handleAddNote).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/{incident_id}/responders", p.handleAddResponders).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/{incident_id}/status-update", p.handleAddStatusUpdate).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/{incident_id}/escalate", p.handleEscalate).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/{incident_id}/priority", p.handleSetPriority).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/{incident_id}/workflow", p.handleRunWorkflow).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/bulk", p.handleBulkUpdateDialog).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/trigger", p.handleTriggerDialog).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/notes", p.handleNoteDialog).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/responders", p.handleRespondersDialog).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/status-updates", p.handleStatusUpdateDialog).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/resolve", p.handleResolveDialog).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/reopen", p.handleReopenDialog).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/incidents/merge", p.handleMergeDialog).Methods(http.MethodPost)
+
+	// Handler for connecting users' PagerDuty accounts
+	apiRouter.HandleFunc("/connect", p.handleConnectDialog).Methods(http.MethodPost)
+
+	// Handler for shift swap answers
+	apiRouter.HandleFunc("/swaps/{swap_id}/{action}", p.handleSwapAction).Methods(http.MethodPost)
+
+	// Handler for confirming pages sent with the notify command
+	apiRouter.HandleFunc("/notify/{action}", p.handleNotifyAction).Methods(http.MethodPost)
+
+	// Handler for confirming the reset of all plugin data
+	apiRouter.HandleFunc("/admin/reset", p.handleResetDialog).Methods(http.MethodPost)
+
+	// Handlers for migrating plugin data between environments
+	apiRouter.HandleFunc("/admin/export", p.handleExport).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/admin/import", p.handleImport).Methods(http.MethodPost)
+
+	// Handler for the dialog creating overrides
+	apiRouter.HandleFunc("/schedules/overrides", p.handleOverrideDialog).Methods(http.MethodPost)
+
+	// Handler for canceling overrides
+	apiRouter.HandleFunc("/schedules/{schedule_id}/overrides/{override_id}/cancel", p.handleCancelOverride).Methods(http.MethodPost)
+
+	// Dynamic lists of the slash command autocomplete
+	apiRouter.HandleFunc("/autocomplete/services", p.handleAutocompleteServices).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/autocomplete/schedules", p.handleAutocompleteSchedules).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/autocomplete/incidents", p.handleAutocompleteIncidents).Methods(http.MethodGet)
 
 	// Endpoints for commands
 	apiRouter.HandleFunc("/incidents", p.handleListIncidents).Methods(http.MethodGet)
@@ -31,6 +74,9 @@ func (p *Plugin) ServeHTTP(c *plugin.Context, w http.ResponseWriter, r *http.Req
 	// PagerDuty webhook endpoint (not protected by authentication)
 	router.HandleFunc("/webhook", p.HandleWebhook).Methods(http.MethodPost)
 
+	// Alert ingest endpoint (authenticated with the alert ingest token)
+	router.HandleFunc("/alerts", p.handleAlertIngest).Methods(http.MethodPost)
+
 	router.ServeHTTP(w, r)
 }
 
@@ -90,12 +136,108 @@ func (p *Plugin) handleReassign(w http.ResponseWriter, r *http.Request) {
 	p.HandleIncidentAction(w, r, incidentID, ActionReassign)
 }
 
+// handleShowPayload handles attaching the raw alert payload to an incident's thread
+func (p *Plugin) handleShowPayload(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["incident_id"]
+	if incidentID == "" {
+		http.Error(w, "Missing incident ID", http.StatusBadRequest)
+		return
+	}
+
+	p.HandleIncidentAction(w, r, incidentID, ActionShowPayload)
+}
+
+// handleSnooze handles snoozing an incident for the duration picked on its post
+func (p *Plugin) handleSnooze(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["incident_id"]
+	if incidentID == "" {
+		http.Error(w, "Missing incident ID", http.StatusBadRequest)
+		return
+	}
+
+	p.HandleIncidentAction(w, r, incidentID, ActionSnooze)
+}
+
+// handleAddNote handles opening the dialog to add a note to an incident
+func (p *Plugin) handleAddNote(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["incident_id"]
+	if incidentID == "" {
+		http.Error(w, "Missing incident ID", http.StatusBadRequest)
+		return
+	}
+
+	p.HandleIncidentAction(w, r, incidentID, ActionAddNote)
+}
+
+// handleAddResponders handles opening the dialog to request additional responders for an incident
+func (p *Plugin) handleAddResponders(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["incident_id"]
+	if incidentID == "" {
+		http.Error(w, "Missing incident ID", http.StatusBadRequest)
+		return
+	}
+
+	p.HandleIncidentAction(w, r, incidentID, ActionResponders)
+}
+
+// handleAddStatusUpdate handles opening the dialog to publish a status update of an incident
+func (p *Plugin) handleAddStatusUpdate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["incident_id"]
+	if incidentID == "" {
+		http.Error(w, "Missing incident ID", http.StatusBadRequest)
+		return
+	}
+
+	p.HandleIncidentAction(w, r, incidentID, ActionStatusUpdate)
+}
+
+// handleEscalate handles escalating an incident to the next level of its escalation policy
+func (p *Plugin) handleEscalate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["incident_id"]
+	if incidentID == "" {
+		http.Error(w, "Missing incident ID", http.StatusBadRequest)
+		return
+	}
+
+	p.HandleIncidentAction(w, r, incidentID, ActionEscalate)
+}
+
+// handleSetPriority handles setting the priority picked on an incident's post
+func (p *Plugin) handleSetPriority(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["incident_id"]
+	if incidentID == "" {
+		http.Error(w, "Missing incident ID", http.StatusBadRequest)
+		return
+	}
+
+	p.HandleIncidentAction(w, r, incidentID, ActionSetPriority)
+}
+
+// handleRunWorkflow handles running the incident workflow picked on an incident's post
+func (p *Plugin) handleRunWorkflow(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["incident_id"]
+	if incidentID == "" {
+		http.Error(w, "Missing incident ID", http.StatusBadRequest)
+		return
+	}
+
+	p.HandleIncidentAction(w, r, incidentID, ActionRunWorkflow)
+}
+
 // handleListIncidents handles listing incidents (for slash command)
 func (p *Plugin) handleListIncidents(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 
 	// Get incidents from PagerDuty
-	incidents, err := p.pdClient.ListIncidents(query)
+	incidents, err := p.getPagerDutyClient().ListIncidents(query)
 	if err != nil {
 		p.API.LogError("Failed to list incidents", "error", err.Error())
 		http.Error(w, "Failed to list incidents: "+err.Error(), http.StatusInternalServerError)
@@ -134,7 +276,7 @@ func (p *Plugin) handleGetIncident(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get incident from PagerDuty
-	incident, err := p.pdClient.GetIncident(incidentID)
+	incident, err := p.getPagerDutyClient().GetIncident(incidentID)
 	if err != nil {
 		p.API.LogError("Failed to get incident", "error", err.Error())
 		http.Error(w, "Failed to get incident: "+err.Error(), http.StatusInternalServerError)
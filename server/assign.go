@@ -0,0 +1,165 @@
+package main
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// notifyAssignees sends the Mattermost users newly assigned to an incident a DM with the
+// incident card, so they are notified even if they don't watch the incident's channel.
+// Users who were already assigned before the event, or who assigned themselves, are skipped,
+// as are users who turned off these DMs in their settings. It returns the IDs of the users
+// who were notified.
+func (p *Plugin) notifyAssignees(message pagerduty.WebhookMessage, previous []pagerduty.Assignment, postID string) map[string]bool {
+	notified := map[string]bool{}
+
+	incident := message.Incident
+	if incident.Status == client.StatusResolved {
+		return notified
+	}
+
+	skipped := map[string]bool{}
+	for _, assignment := range previous {
+		skipped[assignment.Assignee.ID] = true
+	}
+	if message.Agent != nil {
+		skipped[message.Agent.ID] = true
+	}
+
+	escalated := message.Event == EventIncidentEscalated
+	introID := "dm.assigned"
+	if escalated {
+		introID = "dm.escalated"
+	}
+
+	options := p.newIncidentPostOptions(incident)
+	for _, assignment := range incident.Assignments {
+		if skipped[assignment.Assignee.ID] {
+			continue
+		}
+		skipped[assignment.Assignee.ID] = true
+
+		user, err := p.getMattermostUser(assignment.Assignee)
+		if err != nil {
+			p.API.LogWarn("Failed to find Mattermost user for assignee", "pd_user_id", assignment.Assignee.ID, "error", err.Error())
+			continue
+		}
+		if user == nil || user.IsBot {
+			continue
+		}
+
+		settings := p.getUserSettings(user.Id)
+		if (escalated && !settings.NotifyOnEscalation) || (!escalated && !settings.NotifyOnAssignment) {
+			continue
+		}
+
+		intro := i18n.ForUser(user)(introID, map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL})
+		if err := p.sendIncidentDM(user, intro, incident, options, postID); err != nil {
+			p.API.LogWarn("Failed to notify assignee", "incident_id", incident.ID, "user_id", user.Id, "error", err.Error())
+			continue
+		}
+		notified[user.Id] = true
+	}
+
+	return notified
+}
+
+// notifyTeamMembers sends the members of the PagerDuty teams owning an incident's service a
+// DM when a high-urgency incident triggers, if they opted in to these DMs in their settings.
+// Users in notified already got a DM about the incident and are skipped.
+func (p *Plugin) notifyTeamMembers(incident pagerduty.Incident, postID string, notified map[string]bool) {
+	if incident.Urgency != "high" {
+		return
+	}
+
+	service := p.getServiceInfo(incident.Service.ID)
+	if service == nil {
+		return
+	}
+
+	options := p.newIncidentPostOptions(incident)
+	for _, team := range service.Teams {
+		members, err := p.getPagerDutyClient().ListTeamMembers(team.ID)
+		if err != nil {
+			p.API.LogWarn("Failed to list team members", "team_id", team.ID, "error", err.Error())
+			continue
+		}
+
+		data := map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL, "Service": service.Name, "Team": team.Name}
+		for _, member := range members {
+			user, err := p.getMattermostUser(member.User)
+			if err != nil {
+				p.API.LogWarn("Failed to find Mattermost user for team member", "pd_user_id", member.User.ID, "error", err.Error())
+				continue
+			}
+			if user == nil || user.IsBot || notified[user.Id] {
+				continue
+			}
+			notified[user.Id] = true
+
+			if !p.getUserSettings(user.Id).NotifyOnHighUrgency {
+				continue
+			}
+
+			intro := i18n.ForUser(user)("dm.team_high_urgency", data)
+			if err := p.sendIncidentDM(user, intro, incident, options, postID); err != nil {
+				p.API.LogWarn("Failed to notify team member", "incident_id", incident.ID, "user_id", user.Id, "error", err.Error())
+			}
+		}
+	}
+}
+
+// sendIncidentDM sends a user a DM with the card of an incident, introduced by a line saying
+// why they got it
+func (p *Plugin) sendIncidentDM(user *model.User, intro string, incident pagerduty.Incident, options incidentPostOptions, postID string) error {
+	channel, appErr := p.API.GetDirectChannel(p.botUserID, user.Id)
+	if appErr != nil {
+		return errors.New("failed to get direct channel: " + appErr.Error())
+	}
+
+	if postID != "" {
+		intro += " " + i18n.ForUser(user)("dm.view_in_channel", map[string]interface{}{"URL": p.getPostPermalink(postID)})
+	}
+
+	post := p.createIncidentPost(incident, channel.Id, options)
+	if post.Message == "" {
+		post.Message = intro
+	} else {
+		post.Message = intro + "\n\n" + post.Message
+	}
+
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		return errors.New("failed to create post: " + appErr.Error())
+	}
+
+	return nil
+}
+
+// getUserSettings gets a user's settings, falling back to the defaults if they can't be read
+func (p *Plugin) getUserSettings(userID string) *kvstore.UserSettings {
+	settings, err := p.kvstore.GetUserSettings(userID)
+	if err != nil {
+		p.API.LogWarn("Failed to get user settings", "user_id", userID, "error", err.Error())
+		return kvstore.DefaultUserSettings()
+	}
+	return settings
+}
+
+// getIncidentPostID returns the ID of an incident's post, or an empty string if the incident
+// has no post
+func (p *Plugin) getIncidentPostID(incidentID string) string {
+	attachment, err := p.getIncidentAttachment(incidentID)
+	if err != nil {
+		p.API.LogWarn("Failed to get incident attachment", "incident_id", incidentID, "error", err.Error())
+		return ""
+	}
+	if attachment == nil || attachment.Suppressed {
+		return ""
+	}
+	return attachment.PostID
+}
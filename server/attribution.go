@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// recordIncidentAction records a user acknowledging or resolving an incident from Mattermost, so
+// the incident post shows who took the action
+func (p *Plugin) recordIncidentAction(incidentID, status string, user *model.User) {
+	_, err := p.updateIncidentAttachment(incidentID, func(attachment *pagerduty.PostAttachment) {
+		attachment.Attributions = append(attachment.Attributions, pagerduty.ActionAttribution{
+			Status:   status,
+			UserID:   user.Id,
+			Username: user.Username,
+			At:       time.Now(),
+		})
+	})
+	if err != nil {
+		p.API.LogWarn("Failed to record incident action", "incident_id", incidentID, "error", err.Error())
+	}
+}
+
+// formatAttributions formats who acted on an incident, one "Acknowledged by @jane at ..." line per action
+func formatAttributions(attributions []pagerduty.ActionAttribution, loc *time.Location) string {
+	lines := make([]string, 0, len(attributions))
+	for _, attribution := range attributions {
+		lines = append(lines, fmt.Sprintf("%s by @%s at %s",
+			cases.Title(language.English).String(attribution.Status),
+			attribution.Username,
+			timezone.Format(attribution.At, loc)))
+	}
+	return strings.Join(lines, "\n")
+}
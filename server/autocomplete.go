@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// Maximum number of open incidents suggested by the slash command autocomplete
+const maxAutocompleteIncidents = 100
+
+// handleAutocompleteServices lists the services for the slash command autocomplete
+func (p *Plugin) handleAutocompleteServices(w http.ResponseWriter, _ *http.Request) {
+	services, err := p.kvstore.GetCachedServices()
+	if err != nil {
+		p.API.LogWarn("Failed to get cached services", "error", err.Error())
+	}
+	if services == nil {
+		if services, err = p.getPagerDutyClient().ListServices(); err != nil {
+			p.API.LogError("Failed to list services", "error", err.Error())
+			http.Error(w, "Failed to list services", http.StatusInternalServerError)
+			return
+		}
+		if err := p.kvstore.SaveCachedServices(services); err != nil {
+			p.API.LogWarn("Failed to cache services", "error", err.Error())
+		}
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	items := make([]model.AutocompleteListItem, 0, len(services))
+	for _, service := range services {
+		items = append(items, model.AutocompleteListItem{
+			Item:     service.ID,
+			HelpText: service.DisplayName(),
+		})
+	}
+
+	p.writeAutocompleteItems(w, items)
+}
+
+// handleAutocompleteSchedules lists the schedules for the slash command autocomplete
+func (p *Plugin) handleAutocompleteSchedules(w http.ResponseWriter, _ *http.Request) {
+	schedules, err := p.kvstore.GetCachedSchedules()
+	if err != nil {
+		p.API.LogWarn("Failed to get cached schedules", "error", err.Error())
+	}
+	if schedules == nil {
+		if schedules, err = p.getPagerDutyClient().ListSchedules(nil); err != nil {
+			p.API.LogError("Failed to list schedules", "error", err.Error())
+			http.Error(w, "Failed to list schedules", http.StatusInternalServerError)
+			return
+		}
+		if err := p.kvstore.SaveCachedSchedules(schedules); err != nil {
+			p.API.LogWarn("Failed to cache schedules", "error", err.Error())
+		}
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Name < schedules[j].Name
+	})
+
+	items := make([]model.AutocompleteListItem, 0, len(schedules))
+	for _, schedule := range schedules {
+		items = append(items, model.AutocompleteListItem{
+			Item:     schedule.ID,
+			HelpText: schedule.Name,
+		})
+	}
+
+	p.writeAutocompleteItems(w, items)
+}
+
+// handleAutocompleteIncidents lists the open incidents for the slash command autocomplete, most
+// recent first
+func (p *Plugin) handleAutocompleteIncidents(w http.ResponseWriter, _ *http.Request) {
+	incidents, err := p.getOpenIncidents()
+	if err != nil {
+		p.API.LogError("Failed to list open incidents", "error", err.Error())
+		http.Error(w, "Failed to list incidents", http.StatusInternalServerError)
+		return
+	}
+
+	sort.Slice(incidents, func(i, j int) bool {
+		return incidents[i].IncidentNumber > incidents[j].IncidentNumber
+	})
+
+	items := make([]model.AutocompleteListItem, 0, len(incidents))
+	for _, incident := range incidents {
+		items = append(items, model.AutocompleteListItem{
+			Item:     incident.ID,
+			Hint:     fmt.Sprintf("#%d", incident.IncidentNumber),
+			HelpText: fmt.Sprintf("[%s] %s", incident.Status, incident.Title),
+		})
+	}
+
+	p.writeAutocompleteItems(w, items)
+}
+
+// getOpenIncidents gets the triggered and acknowledged incidents, cached briefly so typing a
+// command doesn't list them from PagerDuty on every keystroke
+func (p *Plugin) getOpenIncidents() ([]pagerduty.Incident, error) {
+	incidents, err := p.kvstore.GetCachedOpenIncidents()
+	if err != nil {
+		p.API.LogWarn("Failed to get cached open incidents", "error", err.Error())
+	}
+	if incidents != nil {
+		return incidents, nil
+	}
+
+	options := url.Values{}
+	options.Set("limit", fmt.Sprint(maxAutocompleteIncidents))
+	options.Add("statuses[]", client.StatusTriggered)
+	options.Add("statuses[]", client.StatusAcknowledged)
+
+	incidents, err = p.getPagerDutyClient().ListIncidents(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list incidents")
+	}
+
+	if err := p.kvstore.SaveCachedOpenIncidents(incidents); err != nil {
+		p.API.LogWarn("Failed to cache open incidents", "error", err.Error())
+	}
+
+	return incidents, nil
+}
+
+// writeAutocompleteItems writes the suggestions of a dynamic autocomplete argument
+func (p *Plugin) writeAutocompleteItems(w http.ResponseWriter, items []model.AutocompleteListItem) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(items); err != nil {
+		p.API.LogError("Failed to encode autocomplete items", "error", err.Error())
+	}
+}
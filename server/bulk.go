@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/command"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+)
+
+// handleBulkUpdateDialog handles the confirmation dialog of the ack-all and resolve-all commands
+func (p *Plugin) handleBulkUpdateDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var state command.BulkUpdateState
+	if err := json.Unmarshal([]byte(request.State), &state); err != nil {
+		http.Error(w, "Invalid dialog state", http.StatusBadRequest)
+		return
+	}
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	t := i18n.ForUser(user)
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	message := t("action.bulk.updated", map[string]interface{}{"Count": len(state.IncidentIDs), "Status": strings.ToLower(t("command.list.status." + state.Status))})
+	incidents, err := pdClient.ManageIncidents(state.IncidentIDs, state.Status, user.Email)
+	if err != nil {
+		p.API.LogError("Failed to bulk update incidents", "error", err.Error(), "status", state.Status)
+		message = t("action.bulk.error", map[string]interface{}{"Error": err.Error()})
+	} else {
+		p.API.LogInfo("Incidents bulk updated", "user_id", userID, "status", state.Status, "count", len(state.IncidentIDs))
+	}
+
+	for _, incident := range incidents {
+		p.recordIncidentAction(incident.ID, state.Status, user)
+		p.refreshIncidentPost(incident)
+	}
+
+	p.API.SendEphemeralPost(userID, &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: request.ChannelId,
+		Message:   message,
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
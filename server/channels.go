@@ -0,0 +1,63 @@
+package main
+
+// getCachedChannelID gets the cached ID of a channel configured by name, returning an empty
+// string if it isn't cached
+func (p *Plugin) getCachedChannelID(channelName string) string {
+	p.channelIDsLock.RLock()
+	channelID := p.channelIDs[channelName]
+	p.channelIDsLock.RUnlock()
+
+	if channelID != "" || p.kvstore == nil {
+		return channelID
+	}
+
+	channelID, err := p.kvstore.GetCachedChannelID(channelName)
+	if err != nil {
+		p.API.LogWarn("Failed to get cached channel ID", "channel", channelName, "error", err.Error())
+		return ""
+	}
+
+	if channelID != "" {
+		p.setCachedChannelID(channelName, channelID)
+	}
+	return channelID
+}
+
+// cacheChannelID caches the ID of a channel configured by name, so that it is found without
+// searching the channels of every team again
+func (p *Plugin) cacheChannelID(channelName, channelID string) {
+	p.setCachedChannelID(channelName, channelID)
+
+	if p.kvstore == nil {
+		return
+	}
+
+	if err := p.kvstore.SaveCachedChannelID(channelName, channelID); err != nil {
+		p.API.LogWarn("Failed to cache channel ID", "channel", channelName, "error", err.Error())
+	}
+}
+
+func (p *Plugin) setCachedChannelID(channelName, channelID string) {
+	p.channelIDsLock.Lock()
+	defer p.channelIDsLock.Unlock()
+
+	if p.channelIDs == nil {
+		p.channelIDs = make(map[string]string)
+	}
+	p.channelIDs[channelName] = channelID
+}
+
+// clearChannelIDCache forgets all cached channel IDs, as the configured channels may have changed
+func (p *Plugin) clearChannelIDCache() {
+	p.channelIDsLock.Lock()
+	p.channelIDs = nil
+	p.channelIDsLock.Unlock()
+
+	if p.kvstore == nil {
+		return
+	}
+
+	if err := p.kvstore.DeleteCachedChannelIDs(); err != nil {
+		p.API.LogWarn("Failed to clear cached channel IDs", "error", err.Error())
+	}
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// PDClient is the interface for interacting with the PagerDuty API
+type PDClient interface {
+	WithAPIKey(apiKey string) PDClient
+
+	SendAlertEvent(event *pagerduty.AlertEvent) (*pagerduty.AlertEventResponse, error)
+	TriggerAlert(routingKey, dedupKey string, payload *pagerduty.AlertEventPayload) (*pagerduty.AlertEventResponse, error)
+	AcknowledgeAlert(routingKey, dedupKey string) (*pagerduty.AlertEventResponse, error)
+	ResolveAlert(routingKey, dedupKey string) (*pagerduty.AlertEventResponse, error)
+	SendChangeEvent(event *pagerduty.ChangeEvent) error
+
+	GetIncident(incidentID string) (*pagerduty.Incident, error)
+	ListIncidents(params url.Values) ([]pagerduty.Incident, error)
+	ListIncidentAlerts(incidentID string) ([]pagerduty.Alert, error)
+	ListIncidentNotes(incidentID string) ([]pagerduty.Note, error)
+	ListIncidentStatusUpdates(incidentID string) ([]pagerduty.StatusUpdate, error)
+	CreateStatusUpdate(incidentID, message string, userEmail string) (*pagerduty.StatusUpdate, error)
+	AddNote(incidentID, content string, userEmail string) (*pagerduty.Note, error)
+	RequestResponders(incidentID, requesterID, message string, targets []pagerduty.ResponderTarget, userEmail string) (*pagerduty.ResponderRequest, error)
+	CreateIncident(serviceID, title, details, urgency string, assigneeIDs []string, userEmail string) (*pagerduty.Incident, error)
+	UpdateIncident(incidentID, status string, userEmail string, note string) (*pagerduty.Incident, error)
+	ReopenIncident(incident pagerduty.Incident, reason string, userEmail string) (*pagerduty.Incident, error)
+	MergeIncidents(targetID string, sourceIDs []string, userEmail string) (*pagerduty.Incident, error)
+	ManageIncidents(incidentIDs []string, status string, userEmail string) ([]pagerduty.Incident, error)
+	AssignIncident(incidentID string, userIDs []string, userEmail string) (*pagerduty.Incident, error)
+	SnoozeIncident(incidentID string, duration time.Duration, userEmail string) (*pagerduty.Incident, error)
+	EscalateIncident(incidentID string, level int, userEmail string) (*pagerduty.Incident, error)
+	SetIncidentPriority(incidentID, priorityID string, userEmail string) (*pagerduty.Incident, error)
+	ListPriorities() ([]pagerduty.Priority, error)
+
+	ListUsers() ([]pagerduty.User, error)
+	GetUser(userID string) (*pagerduty.User, error)
+	GetCurrentUser() (*pagerduty.User, error)
+	GetUserByEmail(email string) (*pagerduty.User, error)
+
+	ListServices() ([]pagerduty.Service, error)
+	GetService(serviceID string) (*pagerduty.Service, error)
+	CreateService(name, description, escalationPolicyID, urgency string) (*pagerduty.Service, error)
+
+	ListOnCalls(params url.Values) ([]pagerduty.OnCall, error)
+	ListSchedules(params url.Values) ([]pagerduty.Schedule, error)
+	GetSchedule(scheduleID string, since, until time.Time) (*pagerduty.Schedule, error)
+	ListOverrides(scheduleID string, since, until time.Time) ([]pagerduty.Override, error)
+	CreateOverride(scheduleID string, userID string, start, end time.Time) (*pagerduty.Override, error)
+	DeleteOverride(scheduleID, overrideID string) error
+
+	ListEscalationPolicies(params url.Values) ([]pagerduty.EscalationPolicy, error)
+	GetEscalationPolicy(policyID string) (*pagerduty.EscalationPolicy, error)
+
+	ListMaintenanceWindows(params url.Values) ([]pagerduty.MaintenanceWindow, error)
+	CreateMaintenanceWindow(serviceIDs []string, start, end time.Time, description string, userEmail string) (*pagerduty.MaintenanceWindow, error)
+
+	ListTeams(params url.Values) ([]pagerduty.Team, error)
+	GetTeam(teamID string) (*pagerduty.Team, error)
+
+	ListIncidentWorkflows(params url.Values) ([]pagerduty.IncidentWorkflow, error)
+	StartIncidentWorkflow(workflowID, incidentID string, userEmail string) (*pagerduty.IncidentWorkflowInstance, error)
+	ListTeamMembers(teamID string) ([]pagerduty.TeamMember, error)
+
+	ListTags(query string) ([]pagerduty.Tag, error)
+	ListTaggedEntityIDs(tagID, entityType string) ([]string, error)
+
+	ListAbilities() ([]string, error)
+}
+
+var _ PDClient = (*PagerDutyClient)(nil)
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	// Events API v2 URLs in each service region
+	usEventsURL = "https://events.pagerduty.com/v2"
+	euEventsURL = "https://events.eu.pagerduty.com/v2"
+
+	// Events API v2 event actions
+	EventActionTrigger     = "trigger"
+	EventActionAcknowledge = "acknowledge"
+	EventActionResolve     = "resolve"
+
+	// eventsClientName is the client shown in PagerDuty for alerts triggered from Mattermost
+	eventsClientName = "Mattermost"
+)
+
+// eventsURLForAPI returns the Events API URL in the service region of the REST API at baseURL.
+// Custom REST API URLs use the Events API of the US service region.
+func eventsURLForAPI(baseURL string) string {
+	if baseURL == EUAPIBaseURL {
+		return euEventsURL
+	}
+	return usEventsURL
+}
+
+// SendAlertEvent sends an event to the PagerDuty Events API v2. The Events API
+// authenticates with the routing key in the event, so the REST API key is not sent.
+func (c *PagerDutyClient) SendAlertEvent(event *pagerduty.AlertEvent) (*pagerduty.AlertEventResponse, error) {
+	if event.RoutingKey == "" {
+		return nil, errors.New("routing key is required")
+	}
+
+	jsonPayload, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.eventsURL+"/enqueue", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to send event: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response pagerduty.AlertEventResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response, nil
+}
+
+// TriggerAlert triggers an alert with the given routing key. Alerts with the same dedup key are
+// grouped into the same incident, and PagerDuty generates a dedup key if it is empty.
+func (c *PagerDutyClient) TriggerAlert(routingKey, dedupKey string, payload *pagerduty.AlertEventPayload) (*pagerduty.AlertEventResponse, error) {
+	return c.SendAlertEvent(&pagerduty.AlertEvent{
+		RoutingKey:  routingKey,
+		EventAction: EventActionTrigger,
+		DedupKey:    dedupKey,
+		Payload:     payload,
+		Client:      eventsClientName,
+	})
+}
+
+// AcknowledgeAlert acknowledges the alert with the given routing key and dedup key
+func (c *PagerDutyClient) AcknowledgeAlert(routingKey, dedupKey string) (*pagerduty.AlertEventResponse, error) {
+	if dedupKey == "" {
+		return nil, errors.New("dedup key is required")
+	}
+
+	return c.SendAlertEvent(&pagerduty.AlertEvent{
+		RoutingKey:  routingKey,
+		EventAction: EventActionAcknowledge,
+		DedupKey:    dedupKey,
+	})
+}
+
+// ResolveAlert resolves the alert with the given routing key and dedup key
+func (c *PagerDutyClient) ResolveAlert(routingKey, dedupKey string) (*pagerduty.AlertEventResponse, error) {
+	if dedupKey == "" {
+		return nil, errors.New("dedup key is required")
+	}
+
+	return c.SendAlertEvent(&pagerduty.AlertEvent{
+		RoutingKey:  routingKey,
+		EventAction: EventActionResolve,
+		DedupKey:    dedupKey,
+	})
+}
+
+// SendChangeEvent sends a change event, such as a deploy, to the PagerDuty Change Events API.
+// Change events are shown in the context of the incidents of the service of the routing key.
+func (c *PagerDutyClient) SendChangeEvent(event *pagerduty.ChangeEvent) error {
+	if event.RoutingKey == "" {
+		return errors.New("routing key is required")
+	}
+
+	jsonPayload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal change event")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.eventsURL+"/change/enqueue", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("failed to send change event: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	return nil
+}
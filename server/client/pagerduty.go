@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -15,12 +16,23 @@ import (
 )
 
 const (
-	pagerDutyAPIBaseURL = "https://api.pagerduty.com"
+	// Base URLs of the PagerDuty REST API in each service region
+	USAPIBaseURL = "https://api.pagerduty.com"
+	EUAPIBaseURL = "https://api.eu.pagerduty.com"
 
 	// PagerDuty API endpoints
-	incidentsEndpoint = "/incidents"
-	usersEndpoint     = "/users"
-	servicesEndpoint  = "/services"
+	incidentsEndpoint          = "/incidents"
+	usersEndpoint              = "/users"
+	servicesEndpoint           = "/services"
+	onCallsEndpoint            = "/oncalls"
+	schedulesEndpoint          = "/schedules"
+	escalationPoliciesEndpoint = "/escalation_policies"
+	maintenanceWindowsEndpoint = "/maintenance_windows"
+	tagsEndpoint               = "/tags"
+	abilitiesEndpoint          = "/abilities"
+	prioritiesEndpoint         = "/priorities"
+	teamsEndpoint              = "/teams"
+	incidentWorkflowsEndpoint  = "/incident_workflows"
 
 	// PagerDuty incident statuses
 	StatusTriggered    = "triggered"
@@ -28,25 +40,66 @@ const (
 	StatusResolved     = "resolved"
 )
 
+var (
+	// ErrUnauthorized is returned when PagerDuty rejects the API key
+	ErrUnauthorized = errors.New("the API key was rejected by PagerDuty")
+
+	// ErrNotAcknowledged is returned when snoozing an incident that isn't acknowledged
+	ErrNotAcknowledged = errors.New("the incident must be acknowledged before it can be snoozed")
+
+	// ErrIncidentNotFound is returned when getting an incident that doesn't exist or can't be
+	// seen with the API key
+	ErrIncidentNotFound = errors.New("the incident was not found in PagerDuty")
+
+	// ErrInvalidSnoozeDuration is returned when snoozing an incident for less than a second
+	ErrInvalidSnoozeDuration = errors.New("the snooze duration must be at least one second")
+)
+
 // PagerDutyClient is the client for interacting with the PagerDuty API
 type PagerDutyClient struct {
 	apiKey     string
+	baseURL    string
+	eventsURL  string
 	httpClient *http.Client
+
+	// retryBudget is how long a call may take before it stops retrying
+	retryBudget time.Duration
 }
 
-// NewPagerDutyClient creates a new PagerDuty API client
-func NewPagerDutyClient(apiKey string) *PagerDutyClient {
+// NewPagerDutyClient creates a new PagerDuty API client for the REST API at baseURL, which
+// defaults to the US service region when empty
+func NewPagerDutyClient(apiKey, baseURL string) *PagerDutyClient {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if baseURL == "" {
+		baseURL = USAPIBaseURL
+	}
+
 	return &PagerDutyClient{
-		apiKey: apiKey,
+		apiKey:    apiKey,
+		baseURL:   baseURL,
+		eventsURL: eventsURLForAPI(baseURL),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retryBudget: DefaultRetryBudget,
 	}
 }
 
+// WithRetryBudget returns a copy of the client whose calls retry for up to budget
+func (c *PagerDutyClient) WithRetryBudget(budget time.Duration) *PagerDutyClient {
+	clone := *c
+	clone.retryBudget = budget
+	return &clone
+}
+
+// WithAPIKey returns a client for the same PagerDuty API authenticating with another API key
+func (c *PagerDutyClient) WithAPIKey(apiKey string) PDClient {
+	return NewPagerDutyClient(apiKey, c.baseURL)
+}
+
 // GetIncident gets a single incident by ID
 func (c *PagerDutyClient) GetIncident(incidentID string) (*pagerduty.Incident, error) {
-	endpoint := fmt.Sprintf("%s%s/%s", pagerDutyAPIBaseURL, incidentsEndpoint, incidentID)
+	endpoint := fmt.Sprintf("%s%s/%s", c.baseURL, incidentsEndpoint, incidentID)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -55,12 +108,15 @@ func (c *PagerDutyClient) GetIncident(incidentID string) (*pagerduty.Incident, e
 
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to send request")
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden {
+		return nil, errors.Wrapf(ErrIncidentNotFound, "failed to get incident %s, status: %d", incidentID, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, errors.Errorf("failed to get incident: %s, status: %d", string(body), resp.StatusCode)
@@ -77,42 +133,255 @@ func (c *PagerDutyClient) GetIncident(incidentID string) (*pagerduty.Incident, e
 	return &response.Incident, nil
 }
 
+// ListIncidentAlerts lists the alerts of an incident
+func (c *PagerDutyClient) ListIncidentAlerts(incidentID string) ([]pagerduty.Alert, error) {
+	return listAll[pagerduty.Alert](c, fmt.Sprintf("%s/%s/alerts", incidentsEndpoint, incidentID), nil, "alerts", "incident alerts")
+}
+
+// ListIncidentNotes lists the notes of an incident, oldest first
+func (c *PagerDutyClient) ListIncidentNotes(incidentID string) ([]pagerduty.Note, error) {
+	return listAll[pagerduty.Note](c, fmt.Sprintf("%s/%s/notes", incidentsEndpoint, incidentID), nil, "notes", "incident notes")
+}
+
+// ListIncidentStatusUpdates lists the status updates of an incident, newest first
+func (c *PagerDutyClient) ListIncidentStatusUpdates(incidentID string) ([]pagerduty.StatusUpdate, error) {
+	return listAll[pagerduty.StatusUpdate](c, fmt.Sprintf("%s/%s/status_updates", incidentsEndpoint, incidentID), nil, "status_updates", "incident status updates")
+}
+
+// CreateStatusUpdate publishes a status update to the stakeholders of an incident
+func (c *PagerDutyClient) CreateStatusUpdate(incidentID, message string, userEmail string) (*pagerduty.StatusUpdate, error) {
+	endpoint := fmt.Sprintf("%s%s/%s/status_updates", c.baseURL, incidentsEndpoint, incidentID)
+
+	payload := map[string]interface{}{
+		"message": message,
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to create status update: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		StatusUpdate pagerduty.StatusUpdate `json:"status_update"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.StatusUpdate, nil
+}
+
+// AddNote adds a note to an incident on behalf of the user with the given email
+func (c *PagerDutyClient) AddNote(incidentID, content string, userEmail string) (*pagerduty.Note, error) {
+	endpoint := fmt.Sprintf("%s%s/%s/notes", c.baseURL, incidentsEndpoint, incidentID)
+
+	payload := map[string]interface{}{
+		"note": map[string]string{
+			"content": content,
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to add note: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Note pagerduty.Note `json:"note"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Note, nil
+}
+
+// RequestResponders asks users and escalation policies to help with an incident. The requester
+// is the PagerDuty user the request is made on behalf of.
+func (c *PagerDutyClient) RequestResponders(incidentID, requesterID, message string, targets []pagerduty.ResponderTarget, userEmail string) (*pagerduty.ResponderRequest, error) {
+	endpoint := fmt.Sprintf("%s%s/%s/responder_requests", c.baseURL, incidentsEndpoint, incidentID)
+
+	requestTargets := make([]pagerduty.ResponderRequestTarget, 0, len(targets))
+	for _, target := range targets {
+		requestTargets = append(requestTargets, pagerduty.ResponderRequestTarget{Target: target})
+	}
+
+	payload := map[string]interface{}{
+		"requester_id":              requesterID,
+		"message":                   message,
+		"responder_request_targets": requestTargets,
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to request responders: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		ResponderRequest pagerduty.ResponderRequest `json:"responder_request"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.ResponderRequest, nil
+}
+
 // ListIncidents lists incidents with optional filters
 func (c *PagerDutyClient) ListIncidents(params url.Values) ([]pagerduty.Incident, error) {
-	endpoint := fmt.Sprintf("%s%s?%s", pagerDutyAPIBaseURL, incidentsEndpoint, params.Encode())
+	return listAll[pagerduty.Incident](c, incidentsEndpoint, params, "incidents", "incidents")
+}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+// CreateIncident creates an incident on a service, assigned directly to the given users, or
+// following the service's escalation policy if there are none
+func (c *PagerDutyClient) CreateIncident(serviceID, title, details, urgency string, assigneeIDs []string, userEmail string) (*pagerduty.Incident, error) {
+	endpoint := fmt.Sprintf("%s%s", c.baseURL, incidentsEndpoint)
+
+	assignments := make([]map[string]interface{}, 0, len(assigneeIDs))
+	for _, id := range assigneeIDs {
+		assignments = append(assignments, map[string]interface{}{
+			"assignee": map[string]string{
+				"id":   id,
+				"type": "user_reference",
+			},
+		})
+	}
+
+	incident := map[string]interface{}{
+		"type":  "incident",
+		"title": title,
+		"service": map[string]string{
+			"id":   serviceID,
+			"type": "service_reference",
+		},
+		"urgency": urgency,
+		"body": map[string]string{
+			"type":    "incident_body",
+			"details": details,
+		},
+	}
+	if len(assignments) > 0 {
+		incident["assignments"] = assignments
+	}
+
+	payload := map[string]interface{}{
+		"incident": incident,
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create request")
 	}
 
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to send request")
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, errors.Errorf("failed to list incidents: %s, status: %d", string(body), resp.StatusCode)
+		return nil, errors.Errorf("failed to create incident: %s, status: %d", string(body), resp.StatusCode)
 	}
 
 	var response struct {
-		Incidents []pagerduty.Incident `json:"incidents"`
+		Incident pagerduty.Incident `json:"incident"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, errors.Wrap(err, "failed to decode response")
 	}
 
-	return response.Incidents, nil
+	return &response.Incident, nil
 }
 
 // UpdateIncident updates an incident status
 func (c *PagerDutyClient) UpdateIncident(incidentID, status string, userEmail string, note string) (*pagerduty.Incident, error) {
-	endpoint := fmt.Sprintf("%s%s/%s", pagerDutyAPIBaseURL, incidentsEndpoint, incidentID)
+	endpoint := fmt.Sprintf("%s%s/%s", c.baseURL, incidentsEndpoint, incidentID)
 
 	payload := map[string]interface{}{
 		"incident": map[string]interface{}{
@@ -145,7 +414,7 @@ func (c *PagerDutyClient) UpdateIncident(incidentID, status string, userEmail st
 		req.Header.Set("From", userEmail)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to send request")
 	}
@@ -167,28 +436,39 @@ func (c *PagerDutyClient) UpdateIncident(incidentID, status string, userEmail st
 	return &response.Incident, nil
 }
 
-// AssignIncident assigns an incident to a user
-func (c *PagerDutyClient) AssignIncident(incidentID string, userIDs []string, userEmail string) (*pagerduty.Incident, error) {
-	endpoint := fmt.Sprintf("%s%s/%s", pagerDutyAPIBaseURL, incidentsEndpoint, incidentID)
+// ReopenIncident reopens a resolved incident. The REST API only acknowledges or resolves
+// incidents, so a new incident is triggered on the same service with the same title and urgency,
+// linking back to the resolved one along with the reason, and pages the service's escalation
+// policy from the first level.
+func (c *PagerDutyClient) ReopenIncident(incident pagerduty.Incident, reason string, userEmail string) (*pagerduty.Incident, error) {
+	details := fmt.Sprintf("Reopened from incident #%d: %s", incident.IncidentNumber, incident.HTMLURL)
+	if reason != "" {
+		details += "\n\nReason: " + reason
+	}
 
-	assignments := make([]map[string]interface{}, len(userIDs))
-	for i, userID := range userIDs {
-		assignments[i] = map[string]interface{}{
-			"assignee": map[string]string{
-				"id":   userID,
-				"type": "user_reference",
-			},
-		}
+	reopened, err := c.CreateIncident(incident.Service.ID, incident.Title, details, incident.Urgency, nil, userEmail)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reopen incident")
 	}
+	return reopened, nil
+}
 
-	payload := map[string]interface{}{
-		"incident": map[string]interface{}{
-			"type":        "incident_reference",
-			"assignments": assignments,
-		},
+// MergeIncidents merges the source incidents into the target incident, which takes over their
+// alerts. The source incidents are resolved.
+func (c *PagerDutyClient) MergeIncidents(targetID string, sourceIDs []string, userEmail string) (*pagerduty.Incident, error) {
+	endpoint := fmt.Sprintf("%s%s/%s/merge", c.baseURL, incidentsEndpoint, targetID)
+
+	sources := make([]map[string]string, 0, len(sourceIDs))
+	for _, id := range sourceIDs {
+		sources = append(sources, map[string]string{
+			"id":   id,
+			"type": "incident_reference",
+		})
 	}
 
-	jsonPayload, err := json.Marshal(payload)
+	jsonPayload, err := json.Marshal(map[string]interface{}{
+		"source_incidents": sources,
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal payload")
 	}
@@ -205,7 +485,9 @@ func (c *PagerDutyClient) AssignIncident(incidentID string, userIDs []string, us
 		req.Header.Set("From", userEmail)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	// A merge that timed out may have gone through, and repeating it fails on the sources
+	// that were already merged
+	resp, err := c.do(notIdempotent(req))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to send request")
 	}
@@ -213,7 +495,7 @@ func (c *PagerDutyClient) AssignIncident(incidentID string, userIDs []string, us
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, errors.Errorf("failed to assign incident: %s, status: %d", string(body), resp.StatusCode)
+		return nil, errors.Errorf("failed to merge incidents: %s, status: %d", string(body), resp.StatusCode)
 	}
 
 	var response struct {
@@ -227,18 +509,39 @@ func (c *PagerDutyClient) AssignIncident(incidentID string, userIDs []string, us
 	return &response.Incident, nil
 }
 
-// ListUsers lists users in the PagerDuty account
-func (c *PagerDutyClient) ListUsers() ([]pagerduty.User, error) {
-	endpoint := fmt.Sprintf("%s%s", pagerDutyAPIBaseURL, usersEndpoint)
+// ManageIncidents updates the status of several incidents at once
+func (c *PagerDutyClient) ManageIncidents(incidentIDs []string, status string, userEmail string) ([]pagerduty.Incident, error) {
+	endpoint := fmt.Sprintf("%s%s", c.baseURL, incidentsEndpoint)
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	incidents := make([]map[string]string, 0, len(incidentIDs))
+	for _, id := range incidentIDs {
+		incidents = append(incidents, map[string]string{
+			"id":     id,
+			"type":   "incident_reference",
+			"status": status,
+		})
+	}
+
+	jsonPayload, err := json.Marshal(map[string]interface{}{
+		"incidents": incidents,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create request")
 	}
 
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to send request")
 	}
@@ -246,32 +549,59 @@ func (c *PagerDutyClient) ListUsers() ([]pagerduty.User, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, errors.Errorf("failed to list users: %s, status: %d", string(body), resp.StatusCode)
+		return nil, errors.Errorf("failed to update incidents: %s, status: %d", string(body), resp.StatusCode)
 	}
 
 	var response struct {
-		Users []pagerduty.User `json:"users"`
+		Incidents []pagerduty.Incident `json:"incidents"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, errors.Wrap(err, "failed to decode response")
 	}
 
-	return response.Users, nil
+	return response.Incidents, nil
 }
 
-// ListServices lists services in the PagerDuty account
-func (c *PagerDutyClient) ListServices() ([]pagerduty.Service, error) {
-	endpoint := fmt.Sprintf("%s%s", pagerDutyAPIBaseURL, servicesEndpoint)
+// AssignIncident assigns an incident to a user
+func (c *PagerDutyClient) AssignIncident(incidentID string, userIDs []string, userEmail string) (*pagerduty.Incident, error) {
+	endpoint := fmt.Sprintf("%s%s/%s", c.baseURL, incidentsEndpoint, incidentID)
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	assignments := make([]map[string]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		assignments[i] = map[string]interface{}{
+			"assignee": map[string]string{
+				"id":   userID,
+				"type": "user_reference",
+			},
+		}
+	}
+
+	payload := map[string]interface{}{
+		"incident": map[string]interface{}{
+			"type":        "incident_reference",
+			"assignments": assignments,
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create request")
 	}
 
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to send request")
 	}
@@ -279,23 +609,804 @@ func (c *PagerDutyClient) ListServices() ([]pagerduty.Service, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, errors.Errorf("failed to list services: %s, status: %d", string(body), resp.StatusCode)
+		return nil, errors.Errorf("failed to assign incident: %s, status: %d", string(body), resp.StatusCode)
 	}
 
 	var response struct {
-		Services []pagerduty.Service `json:"services"`
+		Incident pagerduty.Incident `json:"incident"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, errors.Wrap(err, "failed to decode response")
 	}
 
-	return response.Services, nil
+	return &response.Incident, nil
 }
 
-// setHeaders sets the required headers for PagerDuty API requests
-func (c *PagerDutyClient) setHeaders(req *http.Request) {
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
-	req.Header.Set("Authorization", "Token token="+c.apiKey)
+// SnoozeIncident snoozes an acknowledged incident for the given duration
+func (c *PagerDutyClient) SnoozeIncident(incidentID string, duration time.Duration, userEmail string) (*pagerduty.Incident, error) {
+	// PagerDuty takes the duration in whole seconds
+	if duration < time.Second {
+		return nil, ErrInvalidSnoozeDuration
+	}
+
+	endpoint := fmt.Sprintf("%s%s/%s/snooze", c.baseURL, incidentsEndpoint, incidentID)
+
+	payload := map[string]interface{}{
+		"duration": int(duration.Seconds()),
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		// Only acknowledged incidents can be snoozed
+		if resp.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(string(body)), "acknowledged") {
+			return nil, ErrNotAcknowledged
+		}
+		return nil, errors.Errorf("failed to snooze incident: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Incident pagerduty.Incident `json:"incident"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Incident, nil
+}
+
+// EscalateIncident escalates an incident to a level of its escalation policy
+func (c *PagerDutyClient) EscalateIncident(incidentID string, level int, userEmail string) (*pagerduty.Incident, error) {
+	endpoint := fmt.Sprintf("%s%s/%s", c.baseURL, incidentsEndpoint, incidentID)
+
+	payload := map[string]interface{}{
+		"incident": map[string]interface{}{
+			"type":             "incident_reference",
+			"escalation_level": level,
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to escalate incident: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Incident pagerduty.Incident `json:"incident"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Incident, nil
+}
+
+// SetIncidentPriority sets the priority of an incident
+func (c *PagerDutyClient) SetIncidentPriority(incidentID, priorityID string, userEmail string) (*pagerduty.Incident, error) {
+	endpoint := fmt.Sprintf("%s%s/%s", c.baseURL, incidentsEndpoint, incidentID)
+
+	payload := map[string]interface{}{
+		"incident": map[string]interface{}{
+			"type": "incident_reference",
+			"priority": map[string]string{
+				"id":   priorityID,
+				"type": "priority_reference",
+			},
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to set incident priority: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Incident pagerduty.Incident `json:"incident"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Incident, nil
+}
+
+// ListPriorities lists the incident priorities of the account, from highest to lowest. It is
+// empty if priorities are disabled for the account.
+func (c *PagerDutyClient) ListPriorities() ([]pagerduty.Priority, error) {
+	return listAll[pagerduty.Priority](c, prioritiesEndpoint, nil, "priorities", "priorities")
+}
+
+// ListUsers lists users in the PagerDuty account
+func (c *PagerDutyClient) ListUsers() ([]pagerduty.User, error) {
+	return listAll[pagerduty.User](c, usersEndpoint, nil, "users", "users")
+}
+
+// GetUser gets a single user by ID, including their contact methods and notification rules
+func (c *PagerDutyClient) GetUser(userID string) (*pagerduty.User, error) {
+	params := url.Values{}
+	params.Add("include[]", "contact_methods")
+	params.Add("include[]", "notification_rules")
+
+	endpoint := fmt.Sprintf("%s%s/%s?%s", c.baseURL, usersEndpoint, userID, params.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to get user: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		User pagerduty.User `json:"user"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.User, nil
+}
+
+// GetCurrentUser gets the user the client's API key belongs to. It only works with user API tokens.
+func (c *PagerDutyClient) GetCurrentUser() (*pagerduty.User, error) {
+	endpoint := fmt.Sprintf("%s%s/me", c.baseURL, usersEndpoint)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to get current user: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		User pagerduty.User `json:"user"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.User, nil
+}
+
+// GetUserByEmail finds a PagerDuty user by email, including their contact methods and teams.
+// It returns nil without an error if no user has that email.
+func (c *PagerDutyClient) GetUserByEmail(email string) (*pagerduty.User, error) {
+	params := url.Values{}
+	params.Set("query", email)
+	params.Add("include[]", "contact_methods")
+	params.Add("include[]", "teams")
+
+	endpoint := fmt.Sprintf("%s%s?%s", c.baseURL, usersEndpoint, params.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to find user: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Users []pagerduty.User `json:"users"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	// The query also matches names, so look for an exact email match
+	for i := range response.Users {
+		if strings.EqualFold(response.Users[i].Email, email) {
+			return &response.Users[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ListServices lists services in the PagerDuty account
+func (c *PagerDutyClient) ListServices() ([]pagerduty.Service, error) {
+	return listAll[pagerduty.Service](c, servicesEndpoint, nil, "services", "services")
+}
+
+// CreateService creates a service with a constant urgency rule (high, low or severity_based)
+func (c *PagerDutyClient) CreateService(name, description, escalationPolicyID, urgency string) (*pagerduty.Service, error) {
+	endpoint := fmt.Sprintf("%s%s", c.baseURL, servicesEndpoint)
+
+	payload := map[string]interface{}{
+		"service": map[string]interface{}{
+			"type":        "service",
+			"name":        name,
+			"description": description,
+			"escalation_policy": map[string]string{
+				"id":   escalationPolicyID,
+				"type": "escalation_policy_reference",
+			},
+			"incident_urgency_rule": map[string]string{
+				"type":    "constant",
+				"urgency": urgency,
+			},
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to create service: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Service pagerduty.Service `json:"service"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Service, nil
+}
+
+// ListOnCalls lists on-call entries with optional filters
+func (c *PagerDutyClient) ListOnCalls(params url.Values) ([]pagerduty.OnCall, error) {
+	return listAll[pagerduty.OnCall](c, onCallsEndpoint, params, "oncalls", "on-calls")
+}
+
+// ListSchedules lists the schedules of the account, filtered by params such as query and team_ids[]
+func (c *PagerDutyClient) ListSchedules(params url.Values) ([]pagerduty.Schedule, error) {
+	return listAll[pagerduty.Schedule](c, schedulesEndpoint, params, "schedules", "schedules")
+}
+
+// GetSchedule gets a schedule with its final schedule rendered between since and until
+func (c *PagerDutyClient) GetSchedule(scheduleID string, since, until time.Time) (*pagerduty.Schedule, error) {
+	params := url.Values{}
+	params.Set("since", since.UTC().Format(time.RFC3339))
+	params.Set("until", until.UTC().Format(time.RFC3339))
+
+	endpoint := fmt.Sprintf("%s%s/%s?%s", c.baseURL, schedulesEndpoint, scheduleID, params.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to get schedule: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Schedule pagerduty.Schedule `json:"schedule"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Schedule, nil
+}
+
+// CreateOverride creates an override on a schedule putting a user on call for a time window
+func (c *PagerDutyClient) CreateOverride(scheduleID string, userID string, start, end time.Time) (*pagerduty.Override, error) {
+	endpoint := fmt.Sprintf("%s%s/%s/overrides", c.baseURL, schedulesEndpoint, scheduleID)
+
+	payload := map[string]interface{}{
+		"override": map[string]interface{}{
+			"start": start.UTC().Format(time.RFC3339),
+			"end":   end.UTC().Format(time.RFC3339),
+			"user": map[string]string{
+				"id":   userID,
+				"type": "user_reference",
+			},
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to create override: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Override pagerduty.Override `json:"override"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Override, nil
+}
+
+// ListOverrides lists the overrides on a schedule within a time window
+func (c *PagerDutyClient) ListOverrides(scheduleID string, since, until time.Time) ([]pagerduty.Override, error) {
+	params := url.Values{}
+	params.Set("since", since.UTC().Format(time.RFC3339))
+	params.Set("until", until.UTC().Format(time.RFC3339))
+
+	return listAll[pagerduty.Override](c, fmt.Sprintf("%s/%s/overrides", schedulesEndpoint, scheduleID), params, "overrides", "overrides")
+}
+
+// DeleteOverride removes an override from a schedule. Overrides that are in progress are
+// truncated to end now rather than deleted.
+func (c *PagerDutyClient) DeleteOverride(scheduleID, overrideID string) error {
+	endpoint := fmt.Sprintf("%s%s/%s/overrides/%s", c.baseURL, schedulesEndpoint, scheduleID, overrideID)
+
+	req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("failed to delete override: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// GetService gets a single service by ID
+func (c *PagerDutyClient) GetService(serviceID string) (*pagerduty.Service, error) {
+	endpoint := fmt.Sprintf("%s%s/%s", c.baseURL, servicesEndpoint, serviceID)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to get service: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Service pagerduty.Service `json:"service"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Service, nil
+}
+
+// ListMaintenanceWindows lists maintenance windows with optional filters
+func (c *PagerDutyClient) ListMaintenanceWindows(params url.Values) ([]pagerduty.MaintenanceWindow, error) {
+	return listAll[pagerduty.MaintenanceWindow](c, maintenanceWindowsEndpoint, params, "maintenance_windows", "maintenance windows")
+}
+
+// CreateMaintenanceWindow creates a maintenance window on services, during which their incidents
+// don't page anyone
+func (c *PagerDutyClient) CreateMaintenanceWindow(serviceIDs []string, start, end time.Time, description string, userEmail string) (*pagerduty.MaintenanceWindow, error) {
+	endpoint := fmt.Sprintf("%s%s", c.baseURL, maintenanceWindowsEndpoint)
+
+	services := make([]map[string]string, 0, len(serviceIDs))
+	for _, id := range serviceIDs {
+		services = append(services, map[string]string{
+			"id":   id,
+			"type": "service_reference",
+		})
+	}
+
+	payload := map[string]interface{}{
+		"maintenance_window": map[string]interface{}{
+			"type":        "maintenance_window",
+			"start_time":  start.Format(time.RFC3339),
+			"end_time":    end.Format(time.RFC3339),
+			"description": description,
+			"services":    services,
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to create maintenance window: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		MaintenanceWindow pagerduty.MaintenanceWindow `json:"maintenance_window"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.MaintenanceWindow, nil
+}
+
+// ListEscalationPolicies lists the escalation policies of the account, filtered by params such
+// as query and team_ids[]
+func (c *PagerDutyClient) ListEscalationPolicies(params url.Values) ([]pagerduty.EscalationPolicy, error) {
+	return listAll[pagerduty.EscalationPolicy](c, escalationPoliciesEndpoint, params, "escalation_policies", "escalation policies")
+}
+
+// GetEscalationPolicy gets an escalation policy with its levels and their targets
+func (c *PagerDutyClient) GetEscalationPolicy(policyID string) (*pagerduty.EscalationPolicy, error) {
+	endpoint := fmt.Sprintf("%s%s/%s", c.baseURL, escalationPoliciesEndpoint, policyID)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to get escalation policy: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		EscalationPolicy pagerduty.EscalationPolicy `json:"escalation_policy"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.EscalationPolicy, nil
+}
+
+// ListTeams lists the teams of the account, filtered by params such as query
+func (c *PagerDutyClient) ListTeams(params url.Values) ([]pagerduty.Team, error) {
+	return listAll[pagerduty.Team](c, teamsEndpoint, params, "teams", "teams")
+}
+
+// GetTeam gets a team
+func (c *PagerDutyClient) GetTeam(teamID string) (*pagerduty.Team, error) {
+	endpoint := fmt.Sprintf("%s%s/%s", c.baseURL, teamsEndpoint, teamID)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to get team: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Team pagerduty.Team `json:"team"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Team, nil
+}
+
+// ListTeamMembers lists the members of a team
+func (c *PagerDutyClient) ListTeamMembers(teamID string) ([]pagerduty.TeamMember, error) {
+	return listAll[pagerduty.TeamMember](c, fmt.Sprintf("%s/%s/members", teamsEndpoint, teamID), nil, "members", "team members")
+}
+
+// ListTags lists the tags whose label matches a query
+func (c *PagerDutyClient) ListTags(query string) ([]pagerduty.Tag, error) {
+	params := url.Values{}
+	params.Set("query", query)
+
+	return listAll[pagerduty.Tag](c, tagsEndpoint, params, "tags", "tags")
+}
+
+// ListTaggedEntityIDs lists the IDs of the entities of a type (users, teams or escalation_policies)
+// that carry a tag
+func (c *PagerDutyClient) ListTaggedEntityIDs(tagID, entityType string) ([]string, error) {
+	// The entities are listed under a key named after their type
+	entities, err := listAll[struct {
+		ID string `json:"id"`
+	}](c, fmt.Sprintf("%s/%s/%s", tagsEndpoint, tagID, entityType), nil, entityType, "tagged "+entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		ids = append(ids, entity.ID)
+	}
+
+	return ids, nil
+}
+
+// setHeaders sets the required headers for PagerDuty API requests
+func (c *PagerDutyClient) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", "Token token="+c.apiKey)
+}
+
+// ListAbilities lists the abilities of the PagerDuty account. Being one of the cheapest API
+// calls that works with any API key, it is used to check that a key is valid.
+func (c *PagerDutyClient) ListAbilities() ([]string, error) {
+	endpoint := fmt.Sprintf("%s%s", c.baseURL, abilitiesEndpoint)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to list abilities: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Abilities []string `json:"abilities"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return response.Abilities, nil
+}
+
+// ListIncidentWorkflows lists the incident workflows of the account, filtered by params such as query
+func (c *PagerDutyClient) ListIncidentWorkflows(params url.Values) ([]pagerduty.IncidentWorkflow, error) {
+	return listAllByCursor[pagerduty.IncidentWorkflow](c, incidentWorkflowsEndpoint, params, "incident_workflows", "incident workflows")
+}
+
+// StartIncidentWorkflow runs an incident workflow on an incident on behalf of the user with the
+// given email
+func (c *PagerDutyClient) StartIncidentWorkflow(workflowID, incidentID string, userEmail string) (*pagerduty.IncidentWorkflowInstance, error) {
+	endpoint := fmt.Sprintf("%s%s/%s/instances", c.baseURL, incidentWorkflowsEndpoint, workflowID)
+
+	payload := map[string]interface{}{
+		"incident_workflow_instance": map[string]interface{}{
+			"incident": map[string]string{
+				"id":   incidentID,
+				"type": "incident_reference",
+			},
+		},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	// Add From header with user email
+	if userEmail != "" {
+		req.Header.Set("From", userEmail)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to start incident workflow: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var response struct {
+		Instance pagerduty.IncidentWorkflowInstance `json:"incident_workflow_instance"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &response.Instance, nil
 }
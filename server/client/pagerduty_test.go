@@ -0,0 +1,138 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+func TestSnoozeIncident(t *testing.T) {
+	// newClient returns a client whose requests get the given status and body
+	newClient := func(status int, body string) *PagerDutyClient {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/incidents/PABC123/snooze", r.URL.Path)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(server.Close)
+		return NewPagerDutyClient("key", server.URL)
+	}
+
+	t.Run("snoozes acknowledged incidents", func(t *testing.T) {
+		c := newClient(http.StatusCreated, `{"incident": {"id": "PABC123", "status": "acknowledged"}}`)
+
+		incident, err := c.SnoozeIncident("PABC123", time.Hour, "")
+		require.NoError(t, err)
+		assert.Equal(t, "PABC123", incident.ID)
+	})
+
+	t.Run("rejects durations under a second", func(t *testing.T) {
+		c := newClient(http.StatusCreated, `{}`)
+
+		_, err := c.SnoozeIncident("PABC123", 500*time.Millisecond, "")
+		assert.ErrorIs(t, err, ErrInvalidSnoozeDuration)
+	})
+
+	t.Run("reports incidents that aren't acknowledged", func(t *testing.T) {
+		c := newClient(http.StatusBadRequest, `{"error": {"message": "Invalid Input Provided", "errors": ["Incident must be acknowledged to snooze"]}}`)
+
+		_, err := c.SnoozeIncident("PABC123", time.Hour, "")
+		assert.ErrorIs(t, err, ErrNotAcknowledged)
+	})
+
+	t.Run("reports other failures", func(t *testing.T) {
+		c := newClient(http.StatusNotFound, `{"error": {"message": "Not Found"}}`)
+
+		_, err := c.SnoozeIncident("PABC123", time.Hour, "")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotAcknowledged)
+	})
+}
+
+func TestGetIncident(t *testing.T) {
+	// newClient returns a client whose requests get the given status and body
+	newClient := func(status int, body string) *PagerDutyClient {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/incidents/PABC123", r.URL.Path)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(server.Close)
+		return NewPagerDutyClient("key", server.URL)
+	}
+
+	t.Run("reports missing incidents", func(t *testing.T) {
+		c := newClient(http.StatusNotFound, `{"error": {"message": "Not Found"}}`)
+
+		_, err := c.GetIncident("PABC123")
+		assert.ErrorIs(t, err, ErrIncidentNotFound)
+	})
+
+	t.Run("reports other failures", func(t *testing.T) {
+		c := newClient(http.StatusBadRequest, `{"error": {"message": "Invalid Input Provided"}}`)
+
+		_, err := c.GetIncident("PABC123")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrIncidentNotFound)
+	})
+}
+
+func TestReopenIncident(t *testing.T) {
+	resolved := pagerduty.Incident{
+		ID:             "PABC123",
+		IncidentNumber: 42,
+		Title:          "Disk full",
+		Urgency:        "high",
+		Status:         StatusResolved,
+		HTMLURL:        "https://acme.pagerduty.com/incidents/PABC123",
+		Service:        pagerduty.Service{ID: "PSVC1"},
+	}
+
+	t.Run("triggers a linked incident on the same service", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/incidents", r.URL.Path)
+			assert.Equal(t, "oncall@acme.com", r.Header.Get("From"))
+
+			var payload map[string]map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, map[string]interface{}{
+				"type":    "incident",
+				"title":   "Disk full",
+				"urgency": "high",
+				"service": map[string]interface{}{"id": "PSVC1", "type": "service_reference"},
+				"body": map[string]interface{}{
+					"type":    "incident_body",
+					"details": "Reopened from incident #42: https://acme.pagerduty.com/incidents/PABC123\n\nReason: Disk filled up again",
+				},
+			}, payload["incident"])
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"incident": {"id": "PDEF456", "incident_number": 43, "status": "triggered"}}`))
+		}))
+		t.Cleanup(server.Close)
+
+		incident, err := NewPagerDutyClient("key", server.URL).ReopenIncident(resolved, "Disk filled up again", "oncall@acme.com")
+		require.NoError(t, err)
+		assert.Equal(t, "PDEF456", incident.ID)
+		assert.Equal(t, StatusTriggered, incident.Status)
+	})
+
+	t.Run("reports failures", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"message": "Invalid Input Provided"}}`))
+		}))
+		t.Cleanup(server.Close)
+
+		_, err := NewPagerDutyClient("key", server.URL).ReopenIncident(resolved, "", "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Number of results requested per page from list endpoints
+	listPageSize = 100
+
+	// Maximum number of results fetched from a list endpoint, as a safety cap
+	maxListResults = 1000
+)
+
+// listAll fetches the results of a list endpoint, following its offset pagination until all
+// results are fetched. A limit in params caps the number of results, which otherwise stop at
+// maxListResults. key names the results in the response, and what describes them in errors.
+func listAll[T any](c *PagerDutyClient, path string, params url.Values, key, what string) ([]T, error) {
+	query := url.Values{}
+	for name, values := range params {
+		query[name] = append([]string(nil), values...)
+	}
+
+	maxResults := maxListResults
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		maxResults = min(limit, maxListResults)
+	}
+
+	var results []T
+	for len(results) < maxResults {
+		query.Set("limit", strconv.Itoa(min(listPageSize, maxResults-len(results))))
+		query.Set("offset", strconv.Itoa(len(results)))
+
+		page, more, err := listPage[T](c, path, query, key, what)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, page...)
+		if !more || len(page) == 0 {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// listAllByCursor fetches the results of a list endpoint with cursor pagination, such as incident
+// workflows, following next_cursor until all results are fetched. Like listAll, a limit in params
+// caps the number of results, which otherwise stop at maxListResults.
+func listAllByCursor[T any](c *PagerDutyClient, path string, params url.Values, key, what string) ([]T, error) {
+	query := url.Values{}
+	for name, values := range params {
+		query[name] = append([]string(nil), values...)
+	}
+
+	maxResults := maxListResults
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		maxResults = min(limit, maxListResults)
+	}
+
+	var results []T
+	for len(results) < maxResults {
+		query.Set("limit", strconv.Itoa(min(listPageSize, maxResults-len(results))))
+
+		response, err := getPage(c, path, query, what)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []T
+		if err := decodePageField(response, key, &page); err != nil {
+			return nil, err
+		}
+		var cursor string
+		if err := decodePageField(response, "next_cursor", &cursor); err != nil {
+			return nil, err
+		}
+
+		results = append(results, page...)
+		if cursor == "" || len(page) == 0 {
+			break
+		}
+		query.Set("cursor", cursor)
+	}
+
+	return results, nil
+}
+
+// listPage fetches a single page of a list endpoint, returning whether there are more pages
+func listPage[T any](c *PagerDutyClient, path string, query url.Values, key, what string) ([]T, bool, error) {
+	response, err := getPage(c, path, query, what)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var page []T
+	if err := decodePageField(response, key, &page); err != nil {
+		return nil, false, err
+	}
+
+	var more bool
+	if err := decodePageField(response, "more", &more); err != nil {
+		return nil, false, err
+	}
+
+	return page, more, nil
+}
+
+// getPage fetches a single page of a list endpoint, returning the fields of the response
+func getPage(c *PagerDutyClient, path string, query url.Values, what string) (map[string]json.RawMessage, error) {
+	endpoint := fmt.Sprintf("%s%s?%s", c.baseURL, path, query.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to list %s: %s, status: %d", what, string(body), resp.StatusCode)
+	}
+
+	// The results are listed under a key named after their type
+	var response map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return response, nil
+}
+
+// decodePageField decodes a field of a page into value, leaving value as is if the field is
+// missing or null
+func decodePageField(response map[string]json.RawMessage, field string, value interface{}) error {
+	raw, ok := response[field]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return errors.Wrap(err, "failed to decode response")
+	}
+	return nil
+}
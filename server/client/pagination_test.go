@@ -0,0 +1,192 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAll(t *testing.T) {
+	type item struct {
+		ID string `json:"id"`
+	}
+
+	// newClient returns a client listing total items, recording the limit and offset of each request
+	newClient := func(total int) (*PagerDutyClient, *[][2]int) {
+		var requests [][2]int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/items", r.URL.Path)
+			assert.Equal(t, "open", r.URL.Query().Get("status"))
+
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			requests = append(requests, [2]int{limit, offset})
+
+			items := []item{}
+			for i := offset; i < min(offset+limit, total); i++ {
+				items = append(items, item{ID: strconv.Itoa(i)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items":  items,
+				"limit":  limit,
+				"offset": offset,
+				"more":   offset+limit < total,
+			})
+		}))
+		t.Cleanup(server.Close)
+		return NewPagerDutyClient("key", server.URL), &requests
+	}
+
+	params := url.Values{"status": {"open"}}
+
+	t.Run("follows the pages while there are more", func(t *testing.T) {
+		c, requests := newClient(150)
+
+		items, err := listAll[item](c, "/items", params, "items", "items")
+		require.NoError(t, err)
+
+		require.Len(t, items, 150)
+		assert.Equal(t, "0", items[0].ID)
+		assert.Equal(t, "100", items[100].ID)
+		assert.Equal(t, "149", items[149].ID)
+		assert.Equal(t, [][2]int{{100, 0}, {100, 100}}, *requests)
+	})
+
+	t.Run("stops at the limit", func(t *testing.T) {
+		c, requests := newClient(500)
+
+		limited := url.Values{"status": {"open"}, "limit": {"120"}}
+		items, err := listAll[item](c, "/items", limited, "items", "items")
+		require.NoError(t, err)
+
+		assert.Len(t, items, 120)
+		assert.Equal(t, [][2]int{{100, 0}, {20, 100}}, *requests)
+		assert.Equal(t, "120", limited.Get("limit"), "params are left unchanged")
+	})
+
+	t.Run("stops at the page cap", func(t *testing.T) {
+		c, requests := newClient(5000)
+
+		items, err := listAll[item](c, "/items", params, "items", "items")
+		require.NoError(t, err)
+
+		assert.Len(t, items, maxListResults)
+		assert.Len(t, *requests, maxListResults/listPageSize)
+	})
+
+	t.Run("reports failed pages", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"message": "Access Denied"}}`))
+		}))
+		t.Cleanup(server.Close)
+
+		_, err := listAll[item](NewPagerDutyClient("key", server.URL), "/items", params, "items", "items")
+		assert.ErrorContains(t, err, "failed to list items")
+	})
+}
+
+func TestListMethodsFollowPages(t *testing.T) {
+	// newClient returns a client whose path lists total results under key, one page per request
+	newClient := func(path, key string, total int) *PagerDutyClient {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, path, r.URL.Path)
+
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+			results := []map[string]string{}
+			for i := offset; i < min(offset+limit, total); i++ {
+				results = append(results, map[string]string{"id": strconv.Itoa(i)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				key:      results,
+				"limit":  limit,
+				"offset": offset,
+				"more":   offset+limit < total,
+			})
+		}))
+		t.Cleanup(server.Close)
+		return NewPagerDutyClient("key", server.URL)
+	}
+
+	t.Run("lists every override", func(t *testing.T) {
+		c := newClient("/schedules/PSCHED1/overrides", "overrides", 150)
+
+		overrides, err := c.ListOverrides("PSCHED1", time.Now(), time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, overrides, 150)
+		assert.Equal(t, "149", overrides[149].ID)
+	})
+
+	t.Run("lists every status update", func(t *testing.T) {
+		c := newClient("/incidents/PABC123/status_updates", "status_updates", 150)
+
+		updates, err := c.ListIncidentStatusUpdates("PABC123")
+		require.NoError(t, err)
+		require.Len(t, updates, 150)
+		assert.Equal(t, "149", updates[149].ID)
+	})
+}
+
+func TestListAllByCursor(t *testing.T) {
+	// newClient returns a client listing total incident workflows, with the position of the next
+	// page as the cursor, and recording the cursor of each request
+	newClient := func(total int) (*PagerDutyClient, *[]string) {
+		var cursors []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/incident_workflows", r.URL.Path)
+			assert.Empty(t, r.URL.Query().Get("offset"))
+
+			cursor := r.URL.Query().Get("cursor")
+			cursors = append(cursors, cursor)
+
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			start, _ := strconv.Atoi(cursor)
+
+			workflows := []map[string]string{}
+			for i := start; i < min(start+limit, total); i++ {
+				workflows = append(workflows, map[string]string{"id": strconv.Itoa(i)})
+			}
+			var next interface{}
+			if start+limit < total {
+				next = strconv.Itoa(start + limit)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"incident_workflows": workflows,
+				"limit":              limit,
+				"next_cursor":        next,
+			})
+		}))
+		t.Cleanup(server.Close)
+		return NewPagerDutyClient("key", server.URL), &cursors
+	}
+
+	t.Run("follows the cursors until the last page", func(t *testing.T) {
+		c, cursors := newClient(250)
+
+		workflows, err := c.ListIncidentWorkflows(url.Values{})
+		require.NoError(t, err)
+
+		require.Len(t, workflows, 250)
+		assert.Equal(t, "249", workflows[249].ID)
+		assert.Equal(t, []string{"", "100", "200"}, *cursors)
+	})
+
+	t.Run("stops at the limit", func(t *testing.T) {
+		c, cursors := newClient(250)
+
+		workflows, err := c.ListIncidentWorkflows(url.Values{"limit": {"150"}})
+		require.NoError(t, err)
+
+		assert.Len(t, workflows, 150)
+		assert.Equal(t, []string{"", "100"}, *cursors)
+	})
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Number of times a throttled or failed request is retried
+	maxRetries = 3
+
+	// Longest wait before a retry, however long PagerDuty asks us to wait
+	maxRetryDelay = 30 * time.Second
+
+	// DefaultRetryBudget is how long a call may take, including its retries, before it stops
+	// retrying. It is short enough for webhooks, post actions and dialogs to respond before
+	// Mattermost or the webhook sender time out.
+	DefaultRetryBudget = 10 * time.Second
+
+	// BackgroundRetryBudget is the retry budget of calls from the background job, which can
+	// afford to wait out PagerDuty's rate limits
+	BackgroundRetryBudget = 2 * time.Minute
+)
+
+// notIdempotentKey marks requests that mustn't be repeated even though their method is idempotent
+type notIdempotentKey struct{}
+
+// notIdempotent marks a request that isn't idempotent despite its method, like merging incidents
+// with a PUT, so it's only retried when it was throttled
+func notIdempotent(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), notIdempotentKey{}, true))
+}
+
+// retryBaseDelay is the wait before the first retry, doubled for each further retry
+var retryBaseDelay = 500 * time.Millisecond
+
+// do sends a request, retrying it when PagerDuty throttles it (429) and, for idempotent methods,
+// on transient server errors (5xx) and network errors. Throttled requests wait as long as the
+// Retry-After or ratelimit-reset header asks; others back off exponentially. A request isn't
+// retried if the wait would take it past the client's retry budget.
+func (c *PagerDutyClient) do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to rewind request body")
+			}
+			req.Body = body
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if attempt == maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := retryDelay(resp, attempt)
+		if time.Since(start)+delay > c.retryBudget {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// shouldRetry checks whether a request is worth retrying after its response or error
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	// Throttled requests were not processed, so any method can be retried
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	// Anything else may have been processed, so only idempotent requests are retried
+	if req.Context().Value(notIdempotentKey{}) != nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay gets how long to wait before retrying, as asked by a throttled response or with
+// exponential backoff
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		for _, header := range []string{"Retry-After", "ratelimit-reset"} {
+			if seconds, err := strconv.Atoi(resp.Header.Get(header)); err == nil && seconds >= 0 {
+				delay = time.Duration(seconds) * time.Second
+				break
+			}
+		}
+	}
+
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+
+	// newServer responds with the given statuses in turn, then with 200
+	newServer := func(statuses ...int) (*httptest.Server, *int32) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, r.Header.Get("X-Body"), string(body))
+
+			call := int(atomic.AddInt32(&calls, 1))
+			if call <= len(statuses) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(statuses[call-1])
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+		return server, &calls
+	}
+
+	c := NewPagerDutyClient("key", "")
+
+	t.Run("retries throttled requests with their body", func(t *testing.T) {
+		server, calls := newServer(http.StatusTooManyRequests, http.StatusTooManyRequests)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString("payload"))
+		require.NoError(t, err)
+		req.Header.Set("X-Body", "payload")
+
+		resp, err := c.do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 3, atomic.LoadInt32(calls))
+	})
+
+	t.Run("retries server errors of idempotent requests", func(t *testing.T) {
+		server, calls := newServer(http.StatusBadGateway)
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := c.do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+	})
+
+	t.Run("does not retry server errors of other requests", func(t *testing.T) {
+		server, calls := newServer(http.StatusBadGateway)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := c.do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+	})
+
+	t.Run("only retries throttled requests marked as not idempotent", func(t *testing.T) {
+		server, calls := newServer(http.StatusTooManyRequests, http.StatusBadGateway)
+
+		req, err := http.NewRequest(http.MethodPut, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := c.do(notIdempotent(req))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+	})
+
+	t.Run("gives up after the maximum number of retries", func(t *testing.T) {
+		server, calls := newServer(http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := c.do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.EqualValues(t, maxRetries+1, atomic.LoadInt32(calls))
+	})
+
+	t.Run("stops retrying past the retry budget", func(t *testing.T) {
+		server, calls := newServer(http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := c.WithRetryBudget(0).do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+	})
+}
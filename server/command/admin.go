@@ -0,0 +1,118 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// webhookStatsWindows are the time windows webhook delivery statistics are shown for, with the
+// message ID of their name
+var webhookStatsWindows = []struct {
+	NameID   string
+	Duration time.Duration
+}{
+	{"command.admin.webhooks.last_hour", time.Hour},
+	{"command.admin.webhooks.last_day", 24 * time.Hour},
+	{"command.admin.webhooks.last_week", 7 * 24 * time.Hour},
+}
+
+// adminCommand handles the admin subcommands. Only system admins can run them.
+func (h *Handler) adminCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if !h.client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.admin.error.not_admin"),
+		}
+	}
+
+	if len(params) < 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.admin.usage"),
+		}
+	}
+
+	switch strings.ToLower(params[0]) {
+	case "webhooks":
+		return h.webhookStatsCommand(t)
+	case "export":
+		return h.exportCommand(t)
+	case "reset":
+		return h.resetCommand(args, params[1:], t)
+	default:
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.admin.error.unknown", map[string]interface{}{"Command": params[0]}) + " " + t("command.admin.usage"),
+		}
+	}
+}
+
+// webhookStatsCommand shows webhook delivery statistics over recent time windows
+func (h *Handler) webhookStatsCommand(t i18n.TranslateFunc) *model.CommandResponse {
+	text := "### " + t("command.admin.webhooks.title") + "\n\n"
+	text += fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+		t("command.admin.webhooks.column.window"),
+		t("command.admin.webhooks.column.received"),
+		t("command.admin.webhooks.column.processed"),
+		t("command.admin.webhooks.column.deduplicated"),
+		t("command.admin.webhooks.column.failed"),
+		t("command.admin.webhooks.column.dead_lettered"),
+	)
+	text += "| --- | --- | --- | --- | --- | --- |\n"
+
+	now := time.Now()
+	for _, window := range webhookStatsWindows {
+		stats, err := h.kvstore.GetWebhookStats(now.Add(-window.Duration))
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.admin.webhooks.error.get_stats", map[string]interface{}{"Error": err.Error()}),
+			}
+		}
+
+		text += fmt.Sprintf("| %s | %d | %d | %d | %d | %d |\n",
+			t(window.NameID),
+			stats[kvstore.WebhookStatReceived],
+			stats[kvstore.WebhookStatProcessed],
+			stats[kvstore.WebhookStatDeduplicated],
+			stats[kvstore.WebhookStatFailed],
+			stats[kvstore.WebhookStatDeadLettered],
+		)
+	}
+
+	text += "\n_" + t("command.admin.webhooks.hourly") + "_"
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// exportCommand links to the export of the plugin data and explains how to import it in another
+// environment
+func (h *Handler) exportCommand(t i18n.TranslateFunc) *model.CommandResponse {
+	siteURL := ""
+	if config := h.client.Configuration.GetConfig(); config != nil && config.ServiceSettings.SiteURL != nil {
+		siteURL = strings.TrimSuffix(*config.ServiceSettings.SiteURL, "/")
+	}
+	apiURL := siteURL + h.pluginURLPath + "/api/v1/admin"
+
+	text := "### " + t("command.admin.export.title") + "\n\n"
+	text += t("command.admin.export.download", map[string]interface{}{"URL": apiURL + "/export"}) + "\n\n"
+	text += t("command.admin.export.import") + "\n"
+	text += fmt.Sprintf("```\ncurl -X POST -H 'Authorization: Bearer <token>' -H 'X-Requested-With: XMLHttpRequest' --data-binary @export.json %s/import\n```\n", apiURL)
+	text += t("command.admin.export.matching")
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
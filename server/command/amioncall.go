@@ -0,0 +1,89 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// amIOnCallCommand tells the invoking user whether they are on call right now, for which
+// escalation policies and schedules, and until when
+func (h *Handler) amIOnCallCommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	pdUser, err := h.getOwnPagerDutyUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_own_account", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.no_account_for_email"),
+		}
+	}
+
+	// Without a time range, PagerDuty returns the on-calls of right now
+	options := url.Values{}
+	options.Add("user_ids[]", pdUser.ID)
+
+	onCalls, err := h.pdClient().ListOnCalls(options)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_oncalls", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	loc := h.userTimezone(args.UserId)
+	if len(onCalls) == 0 {
+		// Tell the user when they are up next instead
+		now := time.Now()
+		options.Set("since", now.UTC().Format(time.RFC3339))
+		options.Set("until", now.Add(onCallLookahead).UTC().Format(time.RFC3339))
+
+		text := t("command.am_i_oncall.no")
+		if upcoming, err := h.pdClient().ListOnCalls(options); err == nil {
+			text += fmt.Sprintf(" %s.", formatNearestShift(upcoming, loc, t))
+		}
+
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         text,
+		}
+	}
+
+	sort.SliceStable(onCalls, func(i, j int) bool {
+		if onCalls[i].EscalationPolicy.Name != onCalls[j].EscalationPolicy.Name {
+			return onCalls[i].EscalationPolicy.Name < onCalls[j].EscalationPolicy.Name
+		}
+		return onCalls[i].EscalationLevel < onCalls[j].EscalationLevel
+	})
+
+	text := t("command.am_i_oncall.yes") + "\n\n"
+	for _, onCall := range onCalls {
+		line := fmt.Sprintf("- **%s** (%s)", onCall.EscalationPolicy.Name, t("command.oncall.level", map[string]interface{}{"Level": onCall.EscalationLevel}))
+		if onCall.Schedule != nil && onCall.Schedule.Name != "" {
+			line += " " + t("command.am_i_oncall.through", map[string]interface{}{"Schedule": onCall.Schedule.Name, "URL": onCall.Schedule.HTMLURL})
+		}
+		if onCall.End != nil {
+			line += ", " + t("command.am_i_oncall.until", map[string]interface{}{"End": timezone.Format(*onCall.End, loc), "Remaining": time.Until(*onCall.End).Round(time.Minute)})
+		} else {
+			line += ", " + t("command.am_i_oncall.permanently")
+		}
+		text += line + "\n"
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
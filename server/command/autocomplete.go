@@ -0,0 +1,231 @@
+package command
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+)
+
+// Dynamic lists of the autocomplete, served by the plugin's API
+const (
+	autocompleteServicesURL  = "/api/v1/autocomplete/services"
+	autocompleteSchedulesURL = "/api/v1/autocomplete/schedules"
+	autocompleteIncidentsURL = "/api/v1/autocomplete/incidents"
+)
+
+// getAutocompleteData builds the autocomplete tree of the slash command, so users can discover
+// the subcommands and their arguments while typing. The command is registered once for all
+// users, so its help is in the configured display language.
+func getAutocompleteData(t i18n.TranslateFunc) *model.AutocompleteData {
+	pagerduty := model.NewAutocompleteData(CommandPagerDuty, "[command]", t("autocomplete.pagerduty"))
+
+	// Incidents
+	list := model.NewAutocompleteData(SubCommandList, "[status=triggered|acknowledged|resolved] [urgency=high|low] [priority=P1,P2] [tag=<tag>] [team=<team>] [group=service|day|priority] [limit=5] [ephemeral=true|false]", t("autocomplete.list"))
+	pagerduty.AddCommand(list)
+
+	get := model.NewAutocompleteData(SubCommandGet, "<incident_id_number_or_url> [ephemeral=true|false]", t("autocomplete.get"))
+	get.AddDynamicListArgument(t("autocomplete.arg.incident_id_number_url"), autocompleteIncidentsURL, true)
+	pagerduty.AddCommand(get)
+
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandTrigger, "", t("autocomplete.trigger")))
+
+	ackAll := model.NewAutocompleteData(SubCommandAckAll, "service=<id_or_name> [urgency=high|low]", t("autocomplete.ack_all"))
+	ackAll.AddTextArgument(t("autocomplete.arg.ack_all_service"), "service=<id_or_name>", "")
+	pagerduty.AddCommand(ackAll)
+
+	resolveAll := model.NewAutocompleteData(SubCommandResolveAll, "service=<id_or_name> [urgency=high|low]", t("autocomplete.resolve_all"))
+	resolveAll.AddTextArgument(t("autocomplete.arg.resolve_all_service"), "service=<id_or_name>", "")
+	pagerduty.AddCommand(resolveAll)
+
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandBoard, "", t("autocomplete.board")))
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandOpen, "", t("autocomplete.open")))
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandMe, "", t("autocomplete.me")))
+
+	snooze := model.NewAutocompleteData(SubCommandSnooze, "<incident_id> <duration>", t("autocomplete.snooze"))
+	snooze.AddDynamicListArgument(t("autocomplete.arg.incident_id"), autocompleteIncidentsURL, true)
+	snooze.AddTextArgument(t("autocomplete.arg.snooze_duration"), "<duration>", "")
+	pagerduty.AddCommand(snooze)
+
+	note := model.NewAutocompleteData(SubCommandNote, "<incident_id_or_url> <text>", t("autocomplete.note"))
+	note.AddDynamicListArgument(t("autocomplete.arg.incident_id_url"), autocompleteIncidentsURL, true)
+	note.AddTextArgument(t("autocomplete.arg.note_text"), "<text>", "")
+	pagerduty.AddCommand(note)
+
+	escalate := model.NewAutocompleteData(SubCommandEscalate, "<incident_id_or_url> [level]", t("autocomplete.escalate"))
+	escalate.AddDynamicListArgument(t("autocomplete.arg.incident_id_url"), autocompleteIncidentsURL, true)
+	pagerduty.AddCommand(escalate)
+
+	reopen := model.NewAutocompleteData(SubCommandReopen, "<incident_id_or_url>", t("autocomplete.reopen"))
+	reopen.AddDynamicListArgument(t("autocomplete.arg.incident_id_url"), autocompleteIncidentsURL, true)
+	pagerduty.AddCommand(reopen)
+
+	merge := model.NewAutocompleteData(SubCommandMerge, "<target> <source...>", t("autocomplete.merge"))
+	merge.AddDynamicListArgument(t("autocomplete.arg.merge_target"), autocompleteIncidentsURL, true)
+	merge.AddTextArgument(t("autocomplete.arg.merge_sources"), "<source...>", "")
+	pagerduty.AddCommand(merge)
+
+	responders := model.NewAutocompleteData(SubCommandResponders, "add", t("autocomplete.responders"))
+	respondersAdd := model.NewAutocompleteData("add", "<incident> <user|policy> [message]", t("autocomplete.responders_add"))
+	respondersAdd.AddDynamicListArgument(t("autocomplete.arg.incident_id_url"), autocompleteIncidentsURL, true)
+	respondersAdd.AddTextArgument(t("autocomplete.arg.responder"), "<user|policy>", "")
+	responders.AddCommand(respondersAdd)
+	pagerduty.AddCommand(responders)
+
+	statusUpdate := model.NewAutocompleteData(SubCommandStatusUpdate, "<incident> <message>", t("autocomplete.status_update"))
+	statusUpdate.AddDynamicListArgument(t("autocomplete.arg.incident_id_url"), autocompleteIncidentsURL, true)
+	statusUpdate.AddTextArgument(t("autocomplete.arg.status_update"), "<message>", "")
+	pagerduty.AddCommand(statusUpdate)
+
+	workflow := model.NewAutocompleteData(SubCommandWorkflow, "run", t("autocomplete.workflow"))
+	workflowRun := model.NewAutocompleteData("run", "<incident> <workflow>", t("autocomplete.workflow_run"))
+	workflowRun.AddDynamicListArgument(t("autocomplete.arg.incident_id_url"), autocompleteIncidentsURL, true)
+	workflowRun.AddTextArgument(t("autocomplete.arg.workflow"), "<workflow>", "")
+	workflow.AddCommand(workflowRun)
+	pagerduty.AddCommand(workflow)
+
+	// Services
+	change := model.NewAutocompleteData(SubCommandChange, "<service> <summary>", t("autocomplete.change"))
+	change.AddDynamicListArgument(t("autocomplete.arg.service_id_name"), autocompleteServicesURL, true)
+	change.AddTextArgument(t("autocomplete.arg.change_summary"), "<summary>", "")
+	pagerduty.AddCommand(change)
+
+	maintenance := model.NewAutocompleteData(SubCommandMaintenance, "list|create", t("autocomplete.maintenance"))
+	maintenanceList := model.NewAutocompleteData("list", "[service]", t("autocomplete.maintenance_list"))
+	maintenanceList.AddDynamicListArgument(t("autocomplete.arg.service_id_name"), autocompleteServicesURL, false)
+	maintenance.AddCommand(maintenanceList)
+	maintenanceCreate := model.NewAutocompleteData("create", "<service> <duration> [description]", t("autocomplete.maintenance_create"))
+	maintenanceCreate.AddDynamicListArgument(t("autocomplete.arg.service_id_name"), autocompleteServicesURL, true)
+	maintenanceCreate.AddTextArgument(t("autocomplete.arg.maintenance_duration"), "<duration>", "")
+	maintenance.AddCommand(maintenanceCreate)
+	pagerduty.AddCommand(maintenance)
+
+	// On-call
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandOnCall, "", t("autocomplete.oncall")))
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandAmIOnCall, "", t("autocomplete.am_i_oncall")))
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandSchedules, "[team=<team_id_or_name>] [query]", t("autocomplete.schedules")))
+
+	schedule := model.NewAutocompleteData(SubCommandSchedule, "<schedule_id|name> [shifts]", t("autocomplete.schedule"))
+	schedule.AddDynamicListArgument(t("autocomplete.arg.schedule_id_name"), autocompleteSchedulesURL, true)
+	pagerduty.AddCommand(schedule)
+
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandShifts, "[weeks]", t("autocomplete.shifts")))
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandShiftReport, "[channel]", t("autocomplete.shift_report")))
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandCalendar, "", t("autocomplete.calendar")))
+
+	swap := model.NewAutocompleteData(SubCommandSwap, "<schedule_id> @teammate <your_shift_date> <their_shift_date>", t("autocomplete.swap"))
+	swap.AddDynamicListArgument(t("autocomplete.arg.schedule_id"), autocompleteSchedulesURL, true)
+	swap.AddTextArgument(t("autocomplete.arg.swap_teammate"), "@teammate", "")
+	swap.AddTextArgument(t("autocomplete.arg.swap_your_shift"), "<your_shift_date>", "")
+	swap.AddTextArgument(t("autocomplete.arg.swap_their_shift"), "<their_shift_date>", "")
+	pagerduty.AddCommand(swap)
+
+	take := model.NewAutocompleteData(SubCommandTake, "<schedule_id> [duration]", t("autocomplete.take"))
+	take.AddDynamicListArgument(t("autocomplete.arg.schedule_id"), autocompleteSchedulesURL, true)
+	pagerduty.AddCommand(take)
+
+	overrides := model.NewAutocompleteData(SubCommandOverrides, "<schedule_id>|create|cancel", t("autocomplete.overrides"))
+	overrides.AddCommand(model.NewAutocompleteData("create", "", t("autocomplete.overrides_create")))
+	overridesCancel := model.NewAutocompleteData("cancel", "<schedule_id> <override_id>", t("autocomplete.overrides_cancel"))
+	overridesCancel.AddDynamicListArgument(t("autocomplete.arg.schedule_id"), autocompleteSchedulesURL, true)
+	overridesCancel.AddTextArgument(t("autocomplete.arg.override_id"), "<override_id>", "")
+	overrides.AddCommand(overridesCancel)
+	pagerduty.AddCommand(overrides)
+
+	// People
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandWhoAmI, "", t("autocomplete.whoami")))
+
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandConnect, "", t("autocomplete.connect")))
+
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandDisconnect, "", t("autocomplete.disconnect")))
+
+	contact := model.NewAutocompleteData(SubCommandContact, "<@user|email>", t("autocomplete.contact"))
+	contact.AddTextArgument(t("autocomplete.arg.contact"), "<@user|email>", "")
+	pagerduty.AddCommand(contact)
+
+	whois := model.NewAutocompleteData(SubCommandWhois, "<name|email>", t("autocomplete.whois"))
+	whois.AddTextArgument(t("autocomplete.arg.whois"), "<name|email>", "")
+	pagerduty.AddCommand(whois)
+
+	notify := model.NewAutocompleteData(SubCommandNotify, "<@user> <message>", t("autocomplete.notify"))
+	notify.AddTextArgument(t("autocomplete.arg.notify_user"), "<@user>", "")
+	notify.AddTextArgument(t("autocomplete.arg.notify_message"), "<message>", "")
+	pagerduty.AddCommand(notify)
+
+	settings := model.NewAutocompleteData(SubCommandSettings, "[<name> on|off]", t("autocomplete.settings"))
+	settings.AddStaticListArgument(t("autocomplete.arg.setting"), false, []model.AutocompleteListItem{
+		{Item: "ephemeral", HelpText: t("autocomplete.setting.ephemeral")},
+		{Item: "assigned", HelpText: t("autocomplete.setting.assigned")},
+		{Item: "high-urgency", HelpText: t("autocomplete.setting.high_urgency")},
+		{Item: "escalated", HelpText: t("autocomplete.setting.escalated")},
+	})
+	settings.AddStaticListArgument(t("autocomplete.arg.setting_value"), false, []model.AutocompleteListItem{
+		{Item: "on"},
+		{Item: "off"},
+	})
+	pagerduty.AddCommand(settings)
+
+	// Channel subscriptions
+	subscribe := model.NewAutocompleteData(SubCommandSubscribe, "<service_id>|tag=<tag> [label=<prefix>] [fields=<field,...>|default] [maintenance=normal|mute|suppress|quiet]", t("autocomplete.subscribe"))
+	subscribe.AddDynamicListArgument(t("autocomplete.arg.subscribe"), autocompleteServicesURL, true)
+	pagerduty.AddCommand(subscribe)
+
+	unsubscribe := model.NewAutocompleteData(SubCommandUnsubscribe, "<service_id>", t("autocomplete.unsubscribe"))
+	unsubscribe.AddDynamicListArgument(t("autocomplete.arg.service_id"), autocompleteServicesURL, true)
+	pagerduty.AddCommand(unsubscribe)
+
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandSubscriptions, "", t("autocomplete.subscriptions")))
+
+	// System admins only
+	createService := model.NewAutocompleteData(SubCommandCreateService, "<escalation_policy_id> <name> [urgency=high|low|severity_based] [description=<text>]", t("autocomplete.create_service"))
+	createService.AddTextArgument(t("autocomplete.arg.escalation_policy_id"), "<escalation_policy_id>", "")
+	createService.AddTextArgument(t("autocomplete.arg.service_name"), "<name>", "")
+	createService.RoleID = model.SystemAdminRoleId
+	pagerduty.AddCommand(createService)
+
+	route := model.NewAutocompleteData(SubCommandRoute, "add|remove|list", t("autocomplete.route"))
+	routeAdd := model.NewAutocompleteData("add", "<service_id> [~channel]", t("autocomplete.route_add"))
+	routeAdd.AddDynamicListArgument(t("autocomplete.arg.service_id"), autocompleteServicesURL, true)
+	routeAdd.AddTextArgument(t("autocomplete.arg.channel"), "[~channel]", "")
+	route.AddCommand(routeAdd)
+	routeRemove := model.NewAutocompleteData("remove", "<service_id>", t("autocomplete.route_remove"))
+	routeRemove.AddDynamicListArgument(t("autocomplete.arg.service_id"), autocompleteServicesURL, true)
+	route.AddCommand(routeRemove)
+	route.AddCommand(model.NewAutocompleteData("list", "", t("autocomplete.route_list")))
+	route.RoleID = model.SystemAdminRoleId
+	pagerduty.AddCommand(route)
+
+	mapUser := model.NewAutocompleteData(SubCommandMapUser, "<@user> <pagerduty_user_id>", t("autocomplete.map_user"))
+	mapUser.AddTextArgument(t("autocomplete.arg.map_user"), "<@user>", "")
+	mapUser.AddTextArgument(t("autocomplete.arg.pagerduty_user_id"), "<pagerduty_user_id>", "")
+	mapUser.RoleID = model.SystemAdminRoleId
+	pagerduty.AddCommand(mapUser)
+
+	unmapUser := model.NewAutocompleteData(SubCommandUnmapUser, "<@user>", t("autocomplete.unmap_user"))
+	unmapUser.AddTextArgument(t("autocomplete.arg.unmap_user"), "<@user>", "")
+	unmapUser.RoleID = model.SystemAdminRoleId
+	pagerduty.AddCommand(unmapUser)
+
+	userMappings := model.NewAutocompleteData(SubCommandUserMappings, "", t("autocomplete.user_mappings"))
+	userMappings.RoleID = model.SystemAdminRoleId
+	pagerduty.AddCommand(userMappings)
+
+	cleanup := model.NewAutocompleteData(SubCommandCleanup, "<days> [delete|collapse]", t("autocomplete.cleanup"))
+	cleanup.AddTextArgument(t("autocomplete.arg.cleanup_days"), "<days>", "[0-9]+")
+	cleanup.AddStaticListArgument(t("autocomplete.arg.cleanup_mode"), false, []model.AutocompleteListItem{
+		{Item: "delete", HelpText: t("autocomplete.cleanup.delete")},
+		{Item: "collapse", HelpText: t("autocomplete.cleanup.collapse")},
+	})
+	cleanup.RoleID = model.SystemAdminRoleId
+	pagerduty.AddCommand(cleanup)
+
+	admin := model.NewAutocompleteData(SubCommandAdmin, "webhooks|export|reset", t("autocomplete.admin"))
+	admin.AddCommand(model.NewAutocompleteData("webhooks", "", t("autocomplete.admin_webhooks")))
+	admin.AddCommand(model.NewAutocompleteData("export", "", t("autocomplete.admin_export")))
+	admin.AddCommand(model.NewAutocompleteData("reset", "[posts] [dry-run]", t("autocomplete.admin_reset")))
+	admin.RoleID = model.SystemAdminRoleId
+	pagerduty.AddCommand(admin)
+
+	pagerduty.AddCommand(model.NewAutocompleteData(SubCommandHelp, "", t("autocomplete.help")))
+
+	return pagerduty
+}
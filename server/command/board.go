@@ -0,0 +1,92 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// Maximum number of open incidents shown on the board
+const maxBoardIncidents = 100
+
+// boardCommand posts a snapshot of all open incidents grouped by service and sorted by priority
+func (h *Handler) boardCommand(args *model.CommandArgs) *model.CommandResponse {
+	options := url.Values{}
+	options.Set("limit", fmt.Sprint(maxBoardIncidents))
+	options.Add("statuses[]", client.StatusTriggered)
+	options.Add("statuses[]", client.StatusAcknowledged)
+
+	incidents, err := h.pdClient().ListIncidents(options)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         h.translate(args.UserId)("command.error.get_incidents", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	// The board is posted in the channel
+	t := h.displayLanguage()
+
+	now := time.Now()
+	text := "### " + t("command.board.title") + "\n"
+	text += "_" + t("command.board.snapshot", map[string]interface{}{"Count": len(incidents), "Time": timezone.Format(now, h.displayTimezone())}) + "_\n"
+	if len(incidents) == 0 {
+		text += "\n" + t("command.board.empty")
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeInChannel,
+			Text:         text,
+		}
+	}
+
+	// Group incidents by service
+	byService := map[string][]pagerduty.Incident{}
+	for _, incident := range incidents {
+		name := incident.Service.DisplayName()
+		byService[name] = append(byService[name], incident)
+	}
+
+	services := make([]string, 0, len(byService))
+	for name := range byService {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	for _, name := range services {
+		serviceIncidents := byService[name]
+		sortIncidentsByPriority(serviceIncidents)
+
+		text += fmt.Sprintf("\n#### %s (%d)\n", name, len(serviceIncidents))
+		for _, incident := range serviceIncidents {
+			var assignees []string
+			for _, assignment := range incident.Assignments {
+				assignees = append(assignees, assignment.Assignee.DisplayName())
+			}
+			if len(assignees) == 0 {
+				assignees = append(assignees, t("command.list.unassigned"))
+			}
+
+			text += fmt.Sprintf("- %s [#%d](%s) %s · %s · %s · %s\n",
+				formatPriority(incident.Priority),
+				incident.IncidentNumber,
+				incident.HTMLURL,
+				incident.Title,
+				t("command.list.status."+incident.Status),
+				strings.Join(assignees, ", "),
+				t("command.board.open_for", map[string]interface{}{"Duration": now.Sub(incident.CreatedAt).Round(time.Minute)}),
+			)
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeInChannel,
+		Text:         text,
+	}
+}
@@ -0,0 +1,153 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// Maximum number of incidents updated by a bulk command
+const maxBulkIncidents = 100
+
+// BulkUpdateState is the state of the bulk update confirmation dialog
+type BulkUpdateState struct {
+	Status      string   `json:"status"`
+	IncidentIDs []string `json:"incident_ids"`
+}
+
+// bulkUpdateCommand finds the open incidents of a service matching a filter and asks for
+// confirmation in a dialog before acknowledging or resolving them all
+func (h *Handler) bulkUpdateCommand(args *model.CommandArgs, status string, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	subcommand := SubCommandAckAll
+	if status == client.StatusResolved {
+		subcommand = SubCommandResolveAll
+	}
+	usage := t("command.bulk.usage", map[string]interface{}{"Subcommand": subcommand})
+
+	var serviceFilter, urgency string
+	for _, param := range params {
+		key, value, _ := strings.Cut(param, "=")
+		switch strings.ToLower(key) {
+		case "service":
+			serviceFilter = value
+		case "urgency":
+			urgency = strings.ToLower(value)
+		default:
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.unknown_option", map[string]interface{}{"Option": param}) + " " + usage,
+			}
+		}
+	}
+
+	if serviceFilter == "" {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         usage,
+		}
+	}
+
+	service, err := h.findService(serviceFilter)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.find_service", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if service == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.service_not_found", map[string]interface{}{"Service": serviceFilter}),
+		}
+	}
+
+	options := url.Values{}
+	options.Set("limit", fmt.Sprint(maxBulkIncidents))
+	options.Add("service_ids[]", service.ID)
+	options.Add("statuses[]", client.StatusTriggered)
+	if status == client.StatusResolved {
+		options.Add("statuses[]", client.StatusAcknowledged)
+	}
+	if urgency != "" {
+		options.Add("urgencies[]", urgency)
+	}
+
+	incidents, err := h.pdClient().ListIncidents(options)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_incidents", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if len(incidents) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.bulk.empty", map[string]interface{}{"Service": service.DisplayName()}),
+		}
+	}
+
+	state := BulkUpdateState{Status: status}
+	var lines []string
+	for _, incident := range incidents {
+		state.IncidentIDs = append(state.IncidentIDs, incident.ID)
+		lines = append(lines, fmt.Sprintf("* #%d %s", incident.IncidentNumber, incident.Title))
+	}
+
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.prepare_confirmation", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	action := "acknowledge"
+	if status == client.StatusResolved {
+		action = "resolve"
+	}
+	data := map[string]interface{}{"Count": len(incidents), "Service": service.DisplayName()}
+
+	if err := h.client.Frontend.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: args.TriggerId,
+		URL:       fmt.Sprintf("%s/api/v1/incidents/bulk", h.pluginURLPath),
+		Dialog: model.Dialog{
+			Title:            t("command.bulk.dialog.title."+action, data),
+			IntroductionText: t("command.bulk.dialog.introduction."+action, data) + "\n\n" + strings.Join(lines, "\n"),
+			SubmitLabel:      t("incident.action." + action),
+			State:            string(stateJSON),
+		},
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.open_confirmation", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{}
+}
+
+// findService finds a service by ID or by name, ignoring case. It returns nil if no service matches.
+func (h *Handler) findService(idOrName string) (*pagerduty.Service, error) {
+	services, err := h.pdClient().ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, service := range services {
+		if service.ID == idOrName || strings.EqualFold(service.Name, idOrName) {
+			return &services[i], nil
+		}
+	}
+
+	return nil, nil
+}
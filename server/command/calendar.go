@@ -0,0 +1,120 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// icsTimeFormat is the UTC date-time format of iCalendar files
+const icsTimeFormat = "20060102T150405Z"
+
+// calendarCommand sends the invoking user an iCalendar file of their upcoming on-call shifts as a DM
+func (h *Handler) calendarCommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	pdUser, err := h.getPagerDutyUser(args.UserId)
+	if err != nil || pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.own_account_not_found"),
+		}
+	}
+
+	now := time.Now()
+	onCallParams := url.Values{}
+	onCallParams.Add("user_ids[]", pdUser.ID)
+	onCallParams.Set("since", now.UTC().Format(time.RFC3339))
+	onCallParams.Set("until", now.AddDate(0, 0, 7*maxShiftsWeeks).UTC().Format(time.RFC3339))
+
+	onCalls, err := h.pdClient().ListOnCalls(onCallParams)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_shifts", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	channel, err := h.client.Channel.GetDirect(args.UserId, h.botUserID)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_direct_channel", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	calendar := buildShiftsCalendar(uniqueShifts(onCalls), now)
+	fileInfo, err := h.client.File.Upload(bytes.NewReader(calendar), "pagerduty-shifts.ics", channel.Id)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.calendar.error.upload", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if err := h.client.Post.CreatePost(&model.Post{
+		UserId:    h.botUserID,
+		ChannelId: channel.Id,
+		Message:   t("command.calendar.message", map[string]interface{}{"Weeks": maxShiftsWeeks}),
+		FileIds:   []string{fileInfo.Id},
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.calendar.error.send", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.calendar.done"),
+	}
+}
+
+// buildShiftsCalendar builds an iCalendar file with an event per on-call shift.
+// Permanent on-calls without a start or end are left out.
+func buildShiftsCalendar(shifts []pagerduty.OnCall, now time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//mattermost-pagerduty-plugin//On-Call Shifts//EN\r\n")
+	b.WriteString("CALSCALE:GREGORIAN\r\n")
+
+	for _, shift := range shifts {
+		if shift.Start == nil || shift.End == nil {
+			continue
+		}
+
+		source := shift.EscalationPolicy.Name
+		scheduleID := shift.EscalationPolicy.ID
+		if shift.Schedule != nil {
+			source = shift.Schedule.Name
+			scheduleID = shift.Schedule.ID
+		}
+
+		b.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&b, "UID:%s-%d@pagerduty\r\n", scheduleID, shift.Start.Unix())
+		fmt.Fprintf(&b, "DTSTAMP:%s\r\n", now.UTC().Format(icsTimeFormat))
+		fmt.Fprintf(&b, "DTSTART:%s\r\n", shift.Start.UTC().Format(icsTimeFormat))
+		fmt.Fprintf(&b, "DTEND:%s\r\n", shift.End.UTC().Format(icsTimeFormat))
+		fmt.Fprintf(&b, "SUMMARY:%s\r\n", escapeICSText("On call: "+source))
+		fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", escapeICSText(fmt.Sprintf("Escalation policy %s, level %d", shift.EscalationPolicy.Name, shift.EscalationLevel)))
+		if shift.Schedule != nil && shift.Schedule.HTMLURL != "" {
+			fmt.Fprintf(&b, "URL:%s\r\n", shift.Schedule.HTMLURL)
+		}
+		b.WriteString("END:VEVENT\r\n")
+	}
+
+	b.WriteString("END:VCALENDAR\r\n")
+	return []byte(b.String())
+}
+
+// escapeICSText escapes a value for an iCalendar text property
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// maxChangeSummaryLength is the longest summary the Change Events API accepts
+const maxChangeSummaryLength = 1024
+
+// changeCommand records a change, such as a deploy, on a service in PagerDuty through the Change
+// Events API and posts it in the channel, so it shows up in the context of the service's incidents
+func (h *Handler) changeCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.change.usage"),
+		}
+	}
+
+	summary := strings.Join(params[1:], " ")
+	if len(summary) > maxChangeSummaryLength {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.change.error.too_long", map[string]interface{}{"Max": maxChangeSummaryLength}),
+		}
+	}
+
+	service, err := h.findService(params[0])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.find_service", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if service == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.service_not_found", map[string]interface{}{"Service": params[0]}),
+		}
+	}
+
+	routingKey, ok := h.serviceRoutingKey(service.ID)
+	if !ok {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.no_routing_key", map[string]interface{}{"Service": service.DisplayName(), "ServiceID": service.ID}),
+		}
+	}
+
+	user, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	// Post the change first, so the change event can link to it
+	post := &model.Post{
+		UserId:    h.botUserID,
+		ChannelId: args.ChannelId,
+		Message:   h.displayLanguage()("command.change.post", map[string]interface{}{"User": "@" + user.Username, "Service": service.DisplayName(), "Summary": summary}),
+	}
+	if err := h.client.Post.CreatePost(post); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.change.error.post", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if err := h.pdClient().SendChangeEvent(&pagerduty.ChangeEvent{
+		RoutingKey: routingKey,
+		Payload: pagerduty.ChangeEventPayload{
+			Summary:   summary,
+			Source:    "Mattermost",
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			CustomDetails: map[string]interface{}{
+				"recorded_by": user.Username,
+			},
+		},
+		Links: []pagerduty.ChangeEventLink{
+			{Href: h.getPostPermalink(post.Id), Text: h.displayLanguage()("command.change.link")},
+		},
+	}); err != nil {
+		if err := h.client.Post.DeletePost(post.Id); err != nil {
+			h.client.Log.Warn("Failed to delete change post", "post_id", post.Id, "error", err.Error())
+		}
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.change.error.send", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{}
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// cleanupCommand deletes or collapses the posts of incidents in the current channel that were
+// resolved more than the given number of days ago, and forgets the incidents. Only system admins
+// can clean up posts.
+func (h *Handler) cleanupCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if !h.client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.cleanup.error.not_admin"),
+		}
+	}
+
+	if len(params) < 1 || len(params) > 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.cleanup.usage"),
+		}
+	}
+
+	days, err := strconv.Atoi(params[0])
+	if err != nil || days < 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.cleanup.error.invalid_days", map[string]interface{}{"Days": params[0]}) + " " + t("command.cleanup.usage"),
+		}
+	}
+
+	collapse := false
+	if len(params) == 2 {
+		switch strings.ToLower(params[1]) {
+		case "delete":
+		case "collapse":
+			collapse = true
+		default:
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.cleanup.error.invalid_mode", map[string]interface{}{"Mode": params[1]}) + " " + t("command.cleanup.usage"),
+			}
+		}
+	}
+
+	attachments, err := h.kvstore.GetIncidentAttachments()
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.cleanup.error.get_posts", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	cleaned, failed := 0, 0
+	for _, attachment := range attachments {
+		incident := attachment.Incident
+		resolvedAt := incident.LastStatusChangeAt
+		if resolvedAt.IsZero() {
+			resolvedAt = incident.CreatedAt
+		}
+
+		if attachment.ChannelID != args.ChannelId || incident.Status != client.StatusResolved || resolvedAt.After(cutoff) {
+			continue
+		}
+
+		// The post of a reopened incident belongs to the incident reopening it
+		if attachment.PostID != "" && attachment.ReopenedAsID == "" {
+			if err := h.cleanupIncidentPost(attachment, collapse); err != nil {
+				h.client.Log.Warn("Failed to clean up incident post", "post_id", attachment.PostID, "error", err.Error())
+				failed++
+				continue
+			}
+		}
+
+		if err := h.kvstore.DeleteIncidentAttachment(attachment.ID); err != nil {
+			h.client.Log.Warn("Failed to delete incident attachment", "incident_id", attachment.ID, "error", err.Error())
+			failed++
+			continue
+		}
+
+		cleaned++
+	}
+
+	data := map[string]interface{}{"Count": cleaned, "Days": days, "Failed": failed}
+	text := t("command.cleanup.deleted", data)
+	if collapse {
+		text = t("command.cleanup.collapsed", data)
+	}
+	if failed > 0 {
+		text += " " + t("command.cleanup.failed", data)
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// cleanupIncidentPost deletes an incident post, or collapses it to a single line
+func (h *Handler) cleanupIncidentPost(attachment *pagerduty.PostAttachment, collapse bool) error {
+	if !collapse {
+		return h.client.Post.DeletePost(attachment.PostID)
+	}
+
+	post, err := h.client.Post.GetPost(attachment.PostID)
+	if err != nil {
+		return err
+	}
+
+	incident := attachment.Incident
+	post.Message = h.displayLanguage()("command.cleanup.collapsed_post", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL, "Title": incident.Title})
+	post.Props = model.StringInterface{"from_webhook": "true"}
+
+	return h.client.Post.UpdatePost(post)
+}
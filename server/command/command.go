@@ -3,6 +3,8 @@ package command
 import (
 	"fmt"
 	"net/url"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,24 +16,85 @@ import (
 	"github.com/mattermost/mattermost/server/public/pluginapi"
 
 	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
 	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
 )
 
 // Constants for slash commands
 const (
-	CommandPagerDuty = "pagerduty"
-	SubCommandList   = "list"
-	SubCommandOnCall = "oncall"
-	SubCommandGet    = "get"
-	SubCommandHelp   = "help"
+	CommandPagerDuty        = "pagerduty"
+	SubCommandList          = "list"
+	SubCommandOnCall        = "oncall"
+	SubCommandSchedules     = "schedules"
+	SubCommandSchedule      = "schedule"
+	SubCommandGet           = "get"
+	SubCommandTrigger       = "trigger"
+	SubCommandAckAll        = "ack-all"
+	SubCommandResolveAll    = "resolve-all"
+	SubCommandBoard         = "board"
+	SubCommandOpen          = "open"
+	SubCommandSettings      = "settings"
+	SubCommandWhoAmI        = "whoami"
+	SubCommandMe            = "me"
+	SubCommandAmIOnCall     = "am-i-oncall"
+	SubCommandConnect       = "connect"
+	SubCommandDisconnect    = "disconnect"
+	SubCommandContact       = "contact"
+	SubCommandWhois         = "whois"
+	SubCommandNotify        = "notify"
+	SubCommandShiftReport   = "shift-report"
+	SubCommandShifts        = "shifts"
+	SubCommandCalendar      = "calendar"
+	SubCommandSwap          = "swap"
+	SubCommandTake          = "take"
+	SubCommandSnooze        = "snooze"
+	SubCommandNote          = "note"
+	SubCommandEscalate      = "escalate"
+	SubCommandReopen        = "reopen"
+	SubCommandMerge         = "merge"
+	SubCommandResponders    = "responders"
+	SubCommandStatusUpdate  = "status-update"
+	SubCommandWorkflow      = "workflow"
+	SubCommandChange        = "change"
+	SubCommandMaintenance   = "maintenance"
+	SubCommandOverrides     = "overrides"
+	SubCommandSubscribe     = "subscribe"
+	SubCommandUnsubscribe   = "unsubscribe"
+	SubCommandSubscriptions = "subscriptions"
+	SubCommandRoute         = "route"
+	SubCommandCreateService = "create-service"
+	SubCommandMapUser       = "map-user"
+	SubCommandUnmapUser     = "unmap-user"
+	SubCommandUserMappings  = "user-mappings"
+	SubCommandCleanup       = "cleanup"
+	SubCommandAdmin         = "admin"
+	SubCommandHelp          = "help"
 )
 
 // Handler handles PagerDuty slash commands
 type Handler struct {
 	client        *pluginapi.Client
-	pdClient      *client.PagerDutyClient
+	kvstore       kvstore.KVStore
 	botUserID     string
 	pluginURLPath string
+
+	// pdClient is the PagerDuty client of the current configuration, which is replaced when the
+	// API key or service region change
+	pdClient func() client.PDClient
+
+	// displayTimezone is the timezone of timestamps in channel posts
+	displayTimezone func() *time.Location
+
+	// displayLanguage is the translate function of the language of channel posts
+	displayLanguage func() i18n.TranslateFunc
+
+	// serviceRoutingKey is the Events API v2 routing key of a service, if one is configured
+	serviceRoutingKey func(serviceID string) (string, bool)
+
+	// userToken is the decrypted PagerDuty user API token a user connected, if any
+	userToken func(userID string) (string, error)
 }
 
 // Command is the interface for slash command handling
@@ -41,25 +104,33 @@ type Command interface {
 }
 
 // NewCommandHandler creates a new command handler
-func NewCommandHandler(client *pluginapi.Client, pdClient *client.PagerDutyClient, botUserID string, pluginID string) Command {
+func NewCommandHandler(client *pluginapi.Client, pdClient func() client.PDClient, kvstore kvstore.KVStore, botUserID string, pluginID string, displayTimezone func() *time.Location, displayLanguage func() i18n.TranslateFunc, serviceRoutingKey func(serviceID string) (string, bool), userToken func(userID string) (string, error)) Command {
 	return &Handler{
-		client:        client,
-		pdClient:      pdClient,
-		botUserID:     botUserID,
-		pluginURLPath: fmt.Sprintf("/plugins/%s", pluginID),
+		client:            client,
+		pdClient:          pdClient,
+		kvstore:           kvstore,
+		botUserID:         botUserID,
+		pluginURLPath:     fmt.Sprintf("/plugins/%s", pluginID),
+		displayTimezone:   displayTimezone,
+		displayLanguage:   displayLanguage,
+		serviceRoutingKey: serviceRoutingKey,
+		userToken:         userToken,
 	}
 }
 
-// Register registers the slash commands
+// Register registers the slash commands, again whenever the display language changes
 func (h *Handler) Register() error {
+	t := h.displayLanguage()
+
 	// Register the main command
 	if err := h.client.SlashCommand.Register(&model.Command{
 		Trigger:          CommandPagerDuty,
 		AutoComplete:     true,
-		AutoCompleteDesc: "Interact with PagerDuty",
+		AutoCompleteDesc: t("autocomplete.pagerduty"),
 		AutoCompleteHint: "[command]",
+		AutocompleteData: getAutocompleteData(t),
 		DisplayName:      "PagerDuty",
-		Description:      "Integration with PagerDuty",
+		Description:      t("autocomplete.description"),
 	}); err != nil {
 		return err
 	}
@@ -87,20 +158,104 @@ func (h *Handler) Handle(args *model.CommandArgs) (*model.CommandResponse, error
 		return h.listIncidentsCommand(args, additionalArgs), nil
 	case SubCommandOnCall:
 		return h.onCallCommand(args), nil
+	case SubCommandSchedules:
+		return h.schedulesCommand(args, fields[2:]), nil
+	case SubCommandSchedule:
+		return h.scheduleCommand(args, fields[2:]), nil
 	case SubCommandGet:
 		if len(fields) < 3 {
 			return &model.CommandResponse{
 				ResponseType: model.CommandResponseTypeEphemeral,
-				Text:         "Please provide an incident ID or number",
+				Text:         h.translate(args.UserId)("command.error.missing_incident"),
 			}, nil
 		}
-		return h.getIncidentCommand(args, fields[2]), nil
+		return h.getIncidentCommand(args, fields[2], fields[3:]), nil
+	case SubCommandTrigger:
+		return h.triggerCommand(args), nil
+	case SubCommandAckAll:
+		return h.bulkUpdateCommand(args, client.StatusAcknowledged, fields[2:]), nil
+	case SubCommandResolveAll:
+		return h.bulkUpdateCommand(args, client.StatusResolved, fields[2:]), nil
+	case SubCommandBoard:
+		return h.boardCommand(args), nil
+	case SubCommandOpen:
+		return h.openCommand(args), nil
+	case SubCommandSettings:
+		return h.settingsCommand(args, fields[2:]), nil
+	case SubCommandWhoAmI:
+		return h.whoAmICommand(args), nil
+	case SubCommandMe:
+		return h.meCommand(args), nil
+	case SubCommandAmIOnCall:
+		return h.amIOnCallCommand(args), nil
+	case SubCommandConnect:
+		return h.connectCommand(args, fields[2:]), nil
+	case SubCommandDisconnect:
+		return h.disconnectCommand(args), nil
+	case SubCommandContact:
+		return h.contactCommand(args, fields[2:]), nil
+	case SubCommandWhois:
+		return h.whoisCommand(args, fields[2:]), nil
+	case SubCommandNotify:
+		return h.notifyCommand(args, fields[2:]), nil
+	case SubCommandShiftReport:
+		return h.shiftReportCommand(args, fields[2:]), nil
+	case SubCommandShifts:
+		return h.shiftsCommand(args, fields[2:]), nil
+	case SubCommandCalendar:
+		return h.calendarCommand(args), nil
+	case SubCommandSwap:
+		return h.swapCommand(args, fields[2:]), nil
+	case SubCommandTake:
+		return h.takeCommand(args, fields[2:]), nil
+	case SubCommandSnooze:
+		return h.snoozeCommand(args, fields[2:]), nil
+	case SubCommandNote:
+		return h.noteCommand(args, fields[2:]), nil
+	case SubCommandEscalate:
+		return h.escalateCommand(args, fields[2:]), nil
+	case SubCommandReopen:
+		return h.reopenCommand(args, fields[2:]), nil
+	case SubCommandMerge:
+		return h.mergeCommand(args, fields[2:]), nil
+	case SubCommandResponders:
+		return h.respondersCommand(args, fields[2:]), nil
+	case SubCommandStatusUpdate:
+		return h.statusUpdateCommand(args, fields[2:]), nil
+	case SubCommandWorkflow:
+		return h.workflowCommand(args, fields[2:]), nil
+	case SubCommandChange:
+		return h.changeCommand(args, fields[2:]), nil
+	case SubCommandMaintenance:
+		return h.maintenanceCommand(args, fields[2:]), nil
+	case SubCommandOverrides:
+		return h.overridesCommand(args, fields[2:]), nil
+	case SubCommandSubscribe:
+		return h.subscribeCommand(args, fields[2:]), nil
+	case SubCommandUnsubscribe:
+		return h.unsubscribeCommand(args, fields[2:]), nil
+	case SubCommandSubscriptions:
+		return h.subscriptionsCommand(args), nil
+	case SubCommandRoute:
+		return h.routeCommand(args, fields[2:]), nil
+	case SubCommandCreateService:
+		return h.createServiceCommand(args, fields[2:]), nil
+	case SubCommandMapUser:
+		return h.mapUserCommand(args, fields[2:]), nil
+	case SubCommandUnmapUser:
+		return h.unmapUserCommand(args, fields[2:]), nil
+	case SubCommandUserMappings:
+		return h.userMappingsCommand(args), nil
+	case SubCommandCleanup:
+		return h.cleanupCommand(args, fields[2:]), nil
+	case SubCommandAdmin:
+		return h.adminCommand(args, fields[2:]), nil
 	case SubCommandHelp:
 		return h.helpCommand(args), nil
 	default:
 		return &model.CommandResponse{
 			ResponseType: model.CommandResponseTypeEphemeral,
-			Text:         fmt.Sprintf("Unknown subcommand: %s. Try `/pagerduty help` for available commands.", subcommand),
+			Text:         h.translate(args.UserId)("command.error.unknown_subcommand", map[string]interface{}{"Subcommand": subcommand}),
 		}, nil
 	}
 }
@@ -109,10 +264,10 @@ func (h *Handler) Handle(args *model.CommandArgs) (*model.CommandResponse, error
 func (h *Handler) listIncidentsCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
 	// Parse options
 	options := url.Values{}
-	options.Set("limit", "10") // Default limit
+	limit := 10 // Default limit
 
 	// Parse additional parameters
-	var status, service, urgency string
+	var status, service, urgency, priority, tag, group, ephemeral string
 
 	for _, param := range params {
 		parts := strings.SplitN(param, "=", 2)
@@ -125,8 +280,8 @@ func (h *Handler) listIncidentsCommand(args *model.CommandArgs, params []string)
 
 		switch key {
 		case "limit":
-			if limit, err := strconv.Atoi(value); err == nil && limit > 0 && limit <= 25 {
-				options.Set("limit", value)
+			if value, err := strconv.Atoi(value); err == nil && value > 0 && value <= 25 {
+				limit = value
 			}
 		case "status":
 			status = value
@@ -137,15 +292,73 @@ func (h *Handler) listIncidentsCommand(args *model.CommandArgs, params []string)
 		case "urgency":
 			urgency = value
 			options.Set("urgencies[]", value)
+		case "priority":
+			priority = value
+		case "tag":
+			tag = value
+		case "team":
+			teamID, err := h.findTeamID(value)
+			if err != nil {
+				return &model.CommandResponse{
+					ResponseType: model.CommandResponseTypeEphemeral,
+					Text:         findTeamErrorText(h.translate(args.UserId), value, err),
+				}
+			}
+			options.Add("team_ids[]", teamID)
+		case "group":
+			group = strings.ToLower(value)
+		case "ephemeral":
+			ephemeral = value
+		}
+	}
+
+	switch group {
+	case "", listGroupService, listGroupDay, listGroupPriority:
+	default:
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         h.translate(args.UserId)("command.list.error.invalid_group", map[string]interface{}{"Group": group}),
 		}
 	}
 
+	// Resolve the tag to the services it covers
+	var tagServiceIDs []string
+	if tag != "" {
+		services, err := h.findTaggedServices(tag)
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         findTagErrorText(h.translate(args.UserId), tag, err),
+			}
+		}
+
+		if len(services) == 0 {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         h.translate(args.UserId)("command.error.no_tagged_services", map[string]interface{}{"Tag": tag}),
+			}
+		}
+
+		for _, taggedService := range services {
+			tagServiceIDs = append(tagServiceIDs, taggedService.ID)
+			if service == "" {
+				options.Add("service_ids[]", taggedService.ID)
+			}
+		}
+	}
+
+	// PagerDuty can't filter by priority, so the limit is applied after filtering. Otherwise a
+	// page of lower priority incidents would hide the matching ones.
+	if priority == "" {
+		options.Set("limit", strconv.Itoa(limit))
+	}
+
 	// Get incidents from PagerDuty
-	incidents, err := h.pdClient.ListIncidents(options)
+	incidents, err := h.pdClient().ListIncidents(options)
 	if err != nil {
 		return &model.CommandResponse{
 			ResponseType: model.CommandResponseTypeEphemeral,
-			Text:         fmt.Sprintf("Error getting incidents: %s", err.Error()),
+			Text:         h.translate(args.UserId)("command.error.get_incidents", map[string]interface{}{"Error": err.Error()}),
 		}
 	}
 
@@ -154,60 +367,138 @@ func (h *Handler) listIncidentsCommand(args *model.CommandArgs, params []string)
 	for _, incident := range incidents {
 		if (status == "" || incident.Status == status) &&
 			(service == "" || incident.Service.ID == service) &&
-			(urgency == "" || incident.Urgency == urgency) {
+			(tag == "" || slices.Contains(tagServiceIDs, incident.Service.ID)) &&
+			(urgency == "" || incident.Urgency == urgency) &&
+			(priority == "" || matchesPriority(incident, priority)) {
 			filteredIncidents = append(filteredIncidents, incident)
 		}
 	}
 
-	// Format response
-	text := "### PagerDuty Incidents\n\n"
-	if len(filteredIncidents) == 0 {
-		text += "No incidents found matching your criteria."
-	} else {
-		text += "| # | Status | Service | Title | Assigned To |\n"
-		text += "| --- | --- | --- | --- | --- |\n"
-
-		for _, incident := range filteredIncidents {
-			// Format assignees
-			assignees := "Unassigned"
-			if len(incident.Assignments) > 0 {
-				var names []string
-				for _, assignment := range incident.Assignments {
-					names = append(names, assignment.Assignee.Name)
-				}
-				assignees = strings.Join(names, ", ")
-			}
+	sortIncidentsByPriority(filteredIncidents)
+	if len(filteredIncidents) > limit {
+		filteredIncidents = filteredIncidents[:limit]
+	}
 
-			// Format status
-			status := cases.Title(language.English).String(incident.Status)
-
-			// Format service
-			service := incident.Service.Name
-
-			// Add row
-			text += fmt.Sprintf("| [#%d](%s) | %s | %s | %s | %s |\n",
-				incident.IncidentNumber,
-				incident.HTMLURL,
-				status,
-				service,
-				incident.Title,
-				assignees,
-			)
+	responseType := h.responseType(args.UserId, ephemeral)
+
+	// Format response
+	t := h.outputLanguage(args.UserId, responseType)
+	text := "### " + t("command.list.title") + "\n\n"
+	switch {
+	case len(filteredIncidents) == 0:
+		text += t("command.list.empty")
+	case group != "":
+		loc := h.outputTimezone(args.UserId, responseType)
+		for _, incidentGroup := range groupIncidents(filteredIncidents, group, loc, t) {
+			text += fmt.Sprintf("#### %s (%d)\n\n", incidentGroup.Name, len(incidentGroup.Incidents))
+			text += formatIncidentTable(incidentGroup.Incidents, t) + "\n"
 		}
+	default:
+		text += formatIncidentTable(filteredIncidents, t)
 	}
 
 	return &model.CommandResponse{
-		ResponseType: model.CommandResponseTypeInChannel,
+		ResponseType: responseType,
 		Text:         text,
 	}
 }
 
-// onCallCommand handles getting on-call information
+// formatIncidentTable formats incidents as a markdown table, in the language of a translate function
+func formatIncidentTable(incidents []pagerduty.Incident, t i18n.TranslateFunc) string {
+	text := fmt.Sprintf("| # | %s | %s | %s | %s | %s |\n",
+		t("command.list.column.priority"),
+		t("command.list.column.status"),
+		t("command.list.column.service"),
+		t("command.list.column.title"),
+		t("command.list.column.assignees"),
+	)
+	text += "| --- | --- | --- | --- | --- | --- |\n"
+
+	for _, incident := range incidents {
+		// Format assignees
+		assignees := t("command.list.unassigned")
+		if len(incident.Assignments) > 0 {
+			var names []string
+			for _, assignment := range incident.Assignments {
+				names = append(names, assignment.Assignee.Name)
+			}
+			assignees = strings.Join(names, ", ")
+		}
+
+		// Format status
+		status := t("command.list.status." + incident.Status)
+
+		// Format service
+		service := incident.Service.Name
+
+		// Add row
+		text += fmt.Sprintf("| [#%d](%s) | %s | %s | %s | %s | %s |\n",
+			incident.IncidentNumber,
+			incident.HTMLURL,
+			formatPriority(incident.Priority),
+			status,
+			service,
+			incident.Title,
+			assignees,
+		)
+	}
+
+	return text
+}
+
+// onCallCommand lists who is currently on call, grouped by escalation policy and by schedule
+// within each policy, with their escalation level and shift end time
 func (h *Handler) onCallCommand(args *model.CommandArgs) *model.CommandResponse {
-	// Here we would fetch on-call schedule from PagerDuty
-	// For now, respond with a placeholder message
-	text := "### PagerDuty On-Call Information\n\n"
-	text += "On-call schedule not implemented yet. Please check the PagerDuty web interface for on-call information."
+	t := h.translate(args.UserId)
+
+	options := url.Values{}
+	options.Set("limit", "100")
+
+	onCalls, err := h.pdClient().ListOnCalls(options)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_oncalls", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	text := "### " + t("command.oncall.title") + "\n\n"
+	if len(onCalls) == 0 {
+		text += t("command.oncall.empty")
+	}
+
+	// Sort by escalation policy, then by level and schedule
+	sort.SliceStable(onCalls, func(i, j int) bool {
+		a, b := onCalls[i], onCalls[j]
+		if a.EscalationPolicy.Name != b.EscalationPolicy.Name {
+			return a.EscalationPolicy.Name < b.EscalationPolicy.Name
+		}
+		if a.EscalationLevel != b.EscalationLevel {
+			return a.EscalationLevel < b.EscalationLevel
+		}
+		return onCallScheduleName(a, t) < onCallScheduleName(b, t)
+	})
+
+	loc := h.userTimezone(args.UserId)
+	policyID := ""
+	for _, onCall := range onCalls {
+		if onCall.EscalationPolicy.ID != policyID {
+			policyID = onCall.EscalationPolicy.ID
+			text += fmt.Sprintf("#### [%s](%s)\n", onCall.EscalationPolicy.Name, onCall.EscalationPolicy.HTMLURL)
+		}
+
+		until := t("command.oncall.indefinitely")
+		if onCall.End != nil {
+			until = t("command.oncall.until", map[string]interface{}{"End": timezone.Format(*onCall.End, loc)})
+		}
+
+		text += fmt.Sprintf("* %s · %s: **%s**, %s\n",
+			t("command.oncall.level_title", map[string]interface{}{"Level": onCall.EscalationLevel}),
+			onCallScheduleName(onCall, t),
+			onCall.User.DisplayName(),
+			until,
+		)
+	}
 
 	return &model.CommandResponse{
 		ResponseType: model.CommandResponseTypeEphemeral,
@@ -215,50 +506,73 @@ func (h *Handler) onCallCommand(args *model.CommandArgs) *model.CommandResponse
 	}
 }
 
+// onCallScheduleName gets the name of the schedule an on-call comes from, if any
+func onCallScheduleName(onCall pagerduty.OnCall, t i18n.TranslateFunc) string {
+	if onCall.Schedule == nil {
+		return t("command.oncall.direct_assignment")
+	}
+	return onCall.Schedule.Name
+}
+
 // getIncidentCommand handles getting a single incident
-func (h *Handler) getIncidentCommand(args *model.CommandArgs, incidentIdentifier string) *model.CommandResponse {
+func (h *Handler) getIncidentCommand(args *model.CommandArgs, incidentIdentifier string, params []string) *model.CommandResponse {
+	// Parse additional parameters
+	var ephemeral string
+	for _, param := range params {
+		if value, ok := strings.CutPrefix(strings.ToLower(param), "ephemeral="); ok {
+			ephemeral = value
+		}
+	}
+
 	// Get incident from PagerDuty
 	var incident *pagerduty.Incident
 	var err error
 
+	// Accept a pasted incident URL
+	if incidentID, ok := incidentIDFromURL(incidentIdentifier); ok {
+		incidentIdentifier = incidentID
+	}
+
 	// Check if incident identifier is a number (incident number) or string (incident ID)
 	if incidentNumber, numErr := strconv.Atoi(incidentIdentifier); numErr == nil {
 		// It's an incident number, get all incidents and filter
 		options := url.Values{}
 		options.Set("incident_number", strconv.Itoa(incidentNumber))
 
-		incidents, listErr := h.pdClient.ListIncidents(options)
+		incidents, listErr := h.pdClient().ListIncidents(options)
 		if listErr != nil {
 			return &model.CommandResponse{
 				ResponseType: model.CommandResponseTypeEphemeral,
-				Text:         fmt.Sprintf("Error getting incident: %s", listErr.Error()),
+				Text:         h.translate(args.UserId)("command.error.get_incident", map[string]interface{}{"Error": listErr.Error()}),
 			}
 		}
 
 		if len(incidents) == 0 {
 			return &model.CommandResponse{
 				ResponseType: model.CommandResponseTypeEphemeral,
-				Text:         fmt.Sprintf("No incident found with number: %d", incidentNumber),
+				Text:         h.translate(args.UserId)("command.get.not_found", map[string]interface{}{"Number": incidentNumber}),
 			}
 		}
 
 		incident = &incidents[0]
 	} else {
 		// It's an incident ID
-		incident, err = h.pdClient.GetIncident(incidentIdentifier)
+		incident, err = h.pdClient().GetIncident(incidentIdentifier)
 		if err != nil {
 			return &model.CommandResponse{
 				ResponseType: model.CommandResponseTypeEphemeral,
-				Text:         fmt.Sprintf("Error getting incident: %s", err.Error()),
+				Text:         h.translate(args.UserId)("command.error.get_incident", map[string]interface{}{"Error": err.Error()}),
 			}
 		}
 	}
 
 	// Format response
-	text := fmt.Sprintf("### PagerDuty Incident #%d: %s\n\n", incident.IncidentNumber, incident.Title)
-	text += fmt.Sprintf("**Status:** %s\n", cases.Title(language.English).String(incident.Status))
-	text += fmt.Sprintf("**Urgency:** %s\n", cases.Title(language.English).String(incident.Urgency))
-	text += fmt.Sprintf("**Service:** %s\n", incident.Service.Name)
+	responseType := h.responseType(args.UserId, ephemeral)
+	t := h.outputLanguage(args.UserId, responseType)
+	text := "### " + t("command.get.title", map[string]interface{}{"Number": incident.IncidentNumber, "Title": incident.Title}) + "\n\n"
+	text += fmt.Sprintf("**%s:** %s\n", t("command.list.column.status"), t("command.list.status."+incident.Status))
+	text += fmt.Sprintf("**%s:** %s\n", t("incident.field.urgency"), cases.Title(language.English).String(incident.Urgency))
+	text += fmt.Sprintf("**%s:** %s\n", t("incident.field.service"), incident.Service.Name)
 
 	// Format assignees
 	if len(incident.Assignments) > 0 {
@@ -266,37 +580,110 @@ func (h *Handler) getIncidentCommand(args *model.CommandArgs, incidentIdentifier
 		for _, assignment := range incident.Assignments {
 			names = append(names, assignment.Assignee.Name)
 		}
-		text += fmt.Sprintf("**Assigned To:** %s\n", strings.Join(names, ", "))
+		text += fmt.Sprintf("**%s:** %s\n", t("incident.field.assignees"), strings.Join(names, ", "))
 	} else {
-		text += "**Assigned To:** Unassigned\n"
+		text += fmt.Sprintf("**%s:** %s\n", t("incident.field.assignees"), t("command.list.unassigned"))
 	}
 
 	// Format dates
-	text += fmt.Sprintf("**Created:** %s\n", incident.CreatedAt.Format(time.RFC3339))
+	loc := h.outputTimezone(args.UserId, responseType)
+	text += fmt.Sprintf("**%s:** %s\n", t("incident.field.created"), timezone.Format(incident.CreatedAt, loc))
 	if !incident.LastStatusChangeAt.IsZero() {
-		text += fmt.Sprintf("**Last Status Change:** %s\n", incident.LastStatusChangeAt.Format(time.RFC3339))
+		text += fmt.Sprintf("**%s:** %s\n", t("command.get.last_status_change"), timezone.Format(incident.LastStatusChangeAt, loc))
 	}
 
 	// Add description
-	text += "\n**Description:**\n"
+	text += "\n**" + t("command.get.description") + ":**\n"
 	text += incident.Description
 
+	// Add the current investigation state
+	text += h.formatIncidentActivity(incident.ID, loc, t)
+
 	// Add link
-	text += fmt.Sprintf("\n\n[View in PagerDuty](%s)", incident.HTMLURL)
+	text += fmt.Sprintf("\n\n[%s](%s)", t("incident.link.view"), incident.HTMLURL)
 
 	return &model.CommandResponse{
-		ResponseType: model.CommandResponseTypeInChannel,
+		ResponseType: responseType,
 		Text:         text,
 	}
 }
 
+// responseType returns the response type for list/get output. An explicit ephemeral=
+// option takes precedence over the user's saved default.
+func (h *Handler) responseType(userID string, ephemeral string) string {
+	if isEphemeral, err := strconv.ParseBool(ephemeral); err == nil {
+		if isEphemeral {
+			return model.CommandResponseTypeEphemeral
+		}
+		return model.CommandResponseTypeInChannel
+	}
+
+	settings, err := h.kvstore.GetUserSettings(userID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get user settings", "user_id", userID, "error", err.Error())
+		return model.CommandResponseTypeInChannel
+	}
+
+	if settings.EphemeralResponses {
+		return model.CommandResponseTypeEphemeral
+	}
+	return model.CommandResponseTypeInChannel
+}
+
 // helpCommand shows the help information
 func (h *Handler) helpCommand(args *model.CommandArgs) *model.CommandResponse {
-	text := "### PagerDuty Command Help\n\n"
-	text += "* `/pagerduty list [status=triggered|acknowledged|resolved] [urgency=high|low] [limit=5]` - List incidents\n"
-	text += "* `/pagerduty get <incident_id_or_number>` - Get details for a specific incident\n"
-	text += "* `/pagerduty oncall` - Show who is currently on call\n"
-	text += "* `/pagerduty help` - Show this help message\n"
+	t := h.translate(args.UserId)
+	text := "### " + t("command.help.title") + "\n\n"
+	text += "* `/pagerduty list [status=triggered|acknowledged|resolved] [urgency=high|low] [priority=P1,P2] [tag=<tag>] [team=<team_id_or_name>] [group=service|day|priority] [limit=5] [ephemeral=true|false]` - " + t("command.help.list") + "\n"
+	text += "* `/pagerduty get <incident_id_number_or_url> [ephemeral=true|false]` - " + t("command.help.get") + "\n"
+	text += "* `/pagerduty trigger` - " + t("command.help.trigger") + "\n"
+	text += "* `/pagerduty ack-all service=<id_or_name> [urgency=high|low]` - " + t("command.help.ack_all") + "\n"
+	text += "* `/pagerduty resolve-all service=<id_or_name> [urgency=high|low]` - " + t("command.help.resolve_all") + "\n"
+	text += "* `/pagerduty board` - " + t("command.help.board") + "\n"
+	text += "* `/pagerduty open` - " + t("command.help.open") + "\n"
+	text += "* `/pagerduty oncall` - " + t("command.help.oncall") + "\n"
+	text += "* `/pagerduty schedules [team=<team_id_or_name>] [query]` - " + t("command.help.schedules") + "\n"
+	text += "* `/pagerduty schedule <schedule_id|name> [shifts]` - " + t("command.help.schedule") + "\n"
+	text += "* `/pagerduty whoami` - " + t("command.help.whoami") + "\n"
+	text += "* `/pagerduty me` - " + t("command.help.me") + "\n"
+	text += "* `/pagerduty am-i-oncall` - " + t("command.help.am_i_oncall") + "\n"
+	text += "* `/pagerduty connect` - " + t("command.help.connect") + "\n"
+	text += "* `/pagerduty disconnect` - " + t("command.help.disconnect") + "\n"
+	text += "* `/pagerduty contact <@user|email>` - " + t("command.help.contact") + "\n"
+	text += "* `/pagerduty whois <name|email>` - " + t("command.help.whois") + "\n"
+	text += "* `/pagerduty notify <@user> <message>` - " + t("command.help.notify") + "\n"
+	text += "* `/pagerduty shift-report [channel]` - " + t("command.help.shift_report") + "\n"
+	text += "* `/pagerduty shifts [weeks]` - " + t("command.help.shifts") + "\n"
+	text += "* `/pagerduty calendar` - " + t("command.help.calendar") + "\n"
+	text += "* `/pagerduty swap <schedule_id> @teammate <your_shift_date> <their_shift_date>` - " + t("command.help.swap") + "\n"
+	text += "* `/pagerduty take <schedule_id> [duration]` - " + t("command.help.take") + "\n"
+	text += "* `/pagerduty snooze <incident_id> <duration>` - " + t("command.help.snooze") + "\n"
+	text += "* `/pagerduty note <incident_id_or_url> <text>` - " + t("command.help.note") + "\n"
+	text += "* `/pagerduty escalate <incident_id_or_url> [level]` - " + t("command.help.escalate") + "\n"
+	text += "* `/pagerduty reopen <incident_id_or_url>` - " + t("command.help.reopen") + "\n"
+	text += "* `/pagerduty merge <target> <source...>` - " + t("command.help.merge") + "\n"
+	text += "* `/pagerduty responders add <incident> <user|policy> [message]` - " + t("command.help.responders_add") + "\n"
+	text += "* `/pagerduty status-update <incident> <message>` - " + t("command.help.status_update") + "\n"
+	text += "* `/pagerduty workflow run <incident> <workflow>` - " + t("command.help.workflow_run") + "\n"
+	text += "* `/pagerduty change <service> <summary>` - " + t("command.help.change") + "\n"
+	text += "* `/pagerduty maintenance list|create` - " + t("command.help.maintenance") + "\n"
+	text += "* `/pagerduty overrides <schedule_id>` - " + t("command.help.overrides") + "\n"
+	text += "* `/pagerduty overrides create` - " + t("command.help.overrides_create") + "\n"
+	text += "* `/pagerduty overrides cancel <schedule_id> <override_id>` - " + t("command.help.overrides_cancel") + "\n"
+	text += "* `/pagerduty subscribe <service_id>|tag=<tag> [label=<prefix>] [fields=<field,...>|default] [maintenance=normal|mute|suppress|quiet]` - " + t("command.help.subscribe") + "\n"
+	text += "* `/pagerduty unsubscribe <service_id>` - " + t("command.help.unsubscribe") + "\n"
+	text += "* `/pagerduty subscriptions` - " + t("command.help.subscriptions") + "\n"
+	text += "* `/pagerduty route add <service_id> [~channel]|remove <service_id>|list` - " + t("command.help.route") + "\n"
+	text += "* `/pagerduty create-service <escalation_policy_id> <name> [urgency=high|low|severity_based] [description=<text>]` - " + t("command.help.create_service") + "\n"
+	text += "* `/pagerduty map-user <@user> <pagerduty_user_id>` - " + t("command.help.map_user") + "\n"
+	text += "* `/pagerduty unmap-user <@user>` - " + t("command.help.unmap_user") + "\n"
+	text += "* `/pagerduty user-mappings` - " + t("command.help.user_mappings") + "\n"
+	text += "* `/pagerduty cleanup <days> [delete|collapse]` - " + t("command.help.cleanup") + "\n"
+	text += "* `/pagerduty admin webhooks` - " + t("command.help.admin_webhooks") + "\n"
+	text += "* `/pagerduty admin export` - " + t("command.help.admin_export") + "\n"
+	text += "* `/pagerduty admin reset [posts] [dry-run]` - " + t("command.help.admin_reset") + "\n"
+	text += "* `/pagerduty settings [<name> on|off]` - " + t("command.help.settings") + "\n"
+	text += "* `/pagerduty help` - " + t("command.help.help") + "\n"
 
 	return &model.CommandResponse{
 		ResponseType: model.CommandResponseTypeEphemeral,
@@ -0,0 +1,387 @@
+package command
+
+import (
+	"encoding/json"
+	"maps"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client/mocks"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// testStore keeps user mappings, subscriptions and incident attachments in memory for the tests
+type testStore struct {
+	kvstore.KVStore
+	mappings      map[string]*kvstore.UserMapping
+	subscriptions []*kvstore.Subscription
+	attachments   map[string]*pagerduty.PostAttachment
+	keys          []string
+}
+
+func (s *testStore) GetUserSettings(userID string) (*kvstore.UserSettings, error) {
+	return &kvstore.UserSettings{}, nil
+}
+
+func (s *testStore) GetUserMappingByMattermostID(userID string) (*kvstore.UserMapping, error) {
+	return s.mappings[userID], nil
+}
+
+func (s *testStore) GetSubscriptions() ([]*kvstore.Subscription, error) {
+	return s.subscriptions, nil
+}
+
+func (s *testStore) GetIncidentAttachments() ([]*pagerduty.PostAttachment, error) {
+	attachments := make([]*pagerduty.PostAttachment, 0, len(s.attachments))
+	for _, attachment := range s.attachments {
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+func (s *testStore) DeleteIncidentAttachment(incidentID string) error {
+	delete(s.attachments, incidentID)
+	return nil
+}
+
+func (s *testStore) ListKeys() ([]string, error) {
+	return s.keys, nil
+}
+
+// setupHandler returns a handler for the commands of user1, whose PagerDuty user is P1 and whose
+// connected user API token is token, if any
+func setupHandler(t *testing.T, token string) (*Handler, *plugintest.API, *mocks.MockPDClient) {
+	ctrl := gomock.NewController(t)
+	pdClient := mocks.NewMockPDClient(ctrl)
+
+	api := &plugintest.API{}
+	api.On("GetUser", "user1").Return(&model.User{Id: "user1", Email: "user1@example.com", Locale: "en"}, nil)
+	for _, args := range [][]interface{}{{mock.Anything}, {mock.Anything, mock.Anything, mock.Anything}, {mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}, {mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}} {
+		api.On("LogInfo", args...).Maybe()
+		api.On("LogWarn", args...).Maybe()
+	}
+	t.Cleanup(func() { api.AssertExpectations(t) })
+
+	store := &testStore{mappings: map[string]*kvstore.UserMapping{
+		"user1": {MattermostUserID: "user1", PagerDutyUser: pagerduty.User{ID: "P1"}, Manual: true},
+	}}
+
+	h := &Handler{
+		client:          pluginapi.NewClient(api, nil),
+		kvstore:         store,
+		pluginURLPath:   "/plugins/pagerduty",
+		pdClient:        func() client.PDClient { return pdClient },
+		displayTimezone: func() *time.Location { return time.UTC },
+		displayLanguage: func() i18n.TranslateFunc { return i18n.Load(i18n.DefaultLocale) },
+		userToken:       func(userID string) (string, error) { return token, nil },
+	}
+	return h, api, pdClient
+}
+
+// run runs a command as user1
+func run(t *testing.T, h *Handler, command string) *model.CommandResponse {
+	response, err := h.Handle(&model.CommandArgs{UserId: "user1", ChannelId: "channel1", TriggerId: "trigger1", Command: command})
+	require.NoError(t, err)
+	return response
+}
+
+func TestListIncidentsCommand(t *testing.T) {
+	t.Run("lists the incidents", func(t *testing.T) {
+		h, _, pdClient := setupHandler(t, "")
+		pdClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(params url.Values) ([]pagerduty.Incident, error) {
+			assert.Equal(t, client.StatusTriggered, params.Get("statuses[]"))
+			return []pagerduty.Incident{
+				{ID: "I1", IncidentNumber: 1, Title: "API is down", Status: client.StatusTriggered},
+				{ID: "I2", IncidentNumber: 2, Title: "Disk is full", Status: client.StatusTriggered},
+			}, nil
+		})
+
+		response := run(t, h, "/pagerduty list status=triggered ephemeral=true")
+		assert.Equal(t, model.CommandResponseTypeEphemeral, response.ResponseType)
+		assert.Contains(t, response.Text, "API is down")
+		assert.Contains(t, response.Text, "Disk is full")
+	})
+
+	t.Run("limits incidents after filtering by priority", func(t *testing.T) {
+		h, _, pdClient := setupHandler(t, "")
+		pdClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(params url.Values) ([]pagerduty.Incident, error) {
+			assert.Empty(t, params.Get("limit"))
+			return []pagerduty.Incident{
+				{ID: "I1", IncidentNumber: 1, Title: "Disk is full", Priority: &pagerduty.Priority{Name: "P3"}},
+				{ID: "I2", IncidentNumber: 2, Title: "API is down", Priority: &pagerduty.Priority{Name: "P1"}},
+				{ID: "I3", IncidentNumber: 3, Title: "Database is down", Priority: &pagerduty.Priority{Name: "P1"}},
+			}, nil
+		})
+
+		response := run(t, h, "/pagerduty list priority=P1 limit=1 ephemeral=true")
+		assert.Contains(t, response.Text, "API is down")
+		assert.NotContains(t, response.Text, "Database is down")
+		assert.NotContains(t, response.Text, "Disk is full")
+	})
+
+	t.Run("reports failures", func(t *testing.T) {
+		h, _, pdClient := setupHandler(t, "")
+		pdClient.EXPECT().ListIncidents(gomock.Any()).Return(nil, errors.New("unavailable"))
+
+		response := run(t, h, "/pagerduty list")
+		assert.Equal(t, model.CommandResponseTypeEphemeral, response.ResponseType)
+		assert.Contains(t, response.Text, "unavailable")
+	})
+}
+
+func TestBulkUpdateCommand(t *testing.T) {
+	services := []pagerduty.Service{{ID: "S1", Name: "API"}}
+
+	t.Run("asks to confirm resolving the open incidents of the service", func(t *testing.T) {
+		h, api, pdClient := setupHandler(t, "")
+		pdClient.EXPECT().ListServices().Return(services, nil)
+		pdClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(params url.Values) ([]pagerduty.Incident, error) {
+			assert.Equal(t, []string{"S1"}, params["service_ids[]"])
+			assert.Equal(t, []string{client.StatusTriggered, client.StatusAcknowledged}, params["statuses[]"])
+			return []pagerduty.Incident{{ID: "I1", IncidentNumber: 1}, {ID: "I2", IncidentNumber: 2}}, nil
+		})
+
+		var dialog model.OpenDialogRequest
+		api.On("OpenInteractiveDialog", mock.Anything).Run(func(args mock.Arguments) {
+			dialog = args.Get(0).(model.OpenDialogRequest)
+		}).Return(nil)
+
+		response := run(t, h, "/pagerduty resolve-all service=api")
+		assert.Empty(t, response.Text)
+
+		assert.Equal(t, "/plugins/pagerduty/api/v1/incidents/bulk", dialog.URL)
+		var state BulkUpdateState
+		require.NoError(t, json.Unmarshal([]byte(dialog.Dialog.State), &state))
+		assert.Equal(t, BulkUpdateState{Status: client.StatusResolved, IncidentIDs: []string{"I1", "I2"}}, state)
+	})
+
+	t.Run("only acknowledges triggered incidents", func(t *testing.T) {
+		h, api, pdClient := setupHandler(t, "")
+		pdClient.EXPECT().ListServices().Return(services, nil)
+		pdClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(params url.Values) ([]pagerduty.Incident, error) {
+			assert.Equal(t, []string{client.StatusTriggered}, params["statuses[]"])
+			return []pagerduty.Incident{{ID: "I1", IncidentNumber: 1}}, nil
+		})
+		api.On("OpenInteractiveDialog", mock.Anything).Return(nil)
+
+		run(t, h, "/pagerduty ack-all service=S1")
+	})
+
+	t.Run("reports services without open incidents", func(t *testing.T) {
+		h, _, pdClient := setupHandler(t, "")
+		pdClient.EXPECT().ListServices().Return(services, nil)
+		pdClient.EXPECT().ListIncidents(gomock.Any()).Return(nil, nil)
+
+		response := run(t, h, "/pagerduty ack-all service=S1")
+		assert.Equal(t, model.CommandResponseTypeEphemeral, response.ResponseType)
+		assert.Contains(t, response.Text, "API")
+	})
+}
+
+func TestCancelOverrideCommand(t *testing.T) {
+	t.Run("lets admins cancel any override", func(t *testing.T) {
+		h, api, pdClient := setupHandler(t, "")
+		api.On("HasPermissionTo", "user1", model.PermissionManageSystem).Return(true)
+		pdClient.EXPECT().DeleteOverride("SCHED1", "O1").Return(nil)
+
+		response := run(t, h, "/pagerduty overrides cancel SCHED1 O1")
+		assert.Contains(t, response.Text, "canceled")
+	})
+
+	t.Run("lets users cancel their own overrides", func(t *testing.T) {
+		h, api, pdClient := setupHandler(t, "")
+		api.On("HasPermissionTo", "user1", model.PermissionManageSystem).Return(false)
+		pdClient.EXPECT().ListOverrides("SCHED1", gomock.Any(), gomock.Any()).Return([]pagerduty.Override{
+			{ID: "O1", User: pagerduty.User{ID: "P1"}},
+		}, nil)
+		pdClient.EXPECT().DeleteOverride("SCHED1", "O1").Return(nil)
+
+		response := run(t, h, "/pagerduty overrides cancel SCHED1 O1")
+		assert.Contains(t, response.Text, "canceled")
+	})
+
+	t.Run("refuses to cancel the overrides of others", func(t *testing.T) {
+		h, api, pdClient := setupHandler(t, "")
+		api.On("HasPermissionTo", "user1", model.PermissionManageSystem).Return(false)
+		pdClient.EXPECT().ListOverrides("SCHED1", gomock.Any(), gomock.Any()).Return([]pagerduty.Override{
+			{ID: "O1", User: pagerduty.User{ID: "P2"}},
+		}, nil)
+
+		response := run(t, h, "/pagerduty overrides cancel SCHED1 O1")
+		assert.Contains(t, response.Text, "Only the user")
+	})
+
+	t.Run("cancels as the connected user", func(t *testing.T) {
+		h, api, pdClient := setupHandler(t, "token")
+		api.On("HasPermissionTo", "user1", model.PermissionManageSystem).Return(true)
+
+		userClient := mocks.NewMockPDClient(gomock.NewController(t))
+		pdClient.EXPECT().WithAPIKey("token").Return(userClient)
+		userClient.EXPECT().DeleteOverride("SCHED1", "O1").Return(nil)
+
+		response := run(t, h, "/pagerduty overrides cancel SCHED1 O1")
+		assert.Contains(t, response.Text, "canceled")
+	})
+}
+
+func TestResetCommand(t *testing.T) {
+	// setupReset returns a handler whose store has a subscription and two incidents, one with a post
+	setupReset := func(t *testing.T, admin bool) (*Handler, *plugintest.API) {
+		h, api, _ := setupHandler(t, "")
+		api.On("HasPermissionTo", "user1", model.PermissionManageSystem).Return(admin)
+
+		store := h.kvstore.(*testStore)
+		store.subscriptions = []*kvstore.Subscription{{ServiceID: "S1", ChannelID: "channel1"}}
+		store.attachments = map[string]*pagerduty.PostAttachment{
+			"I1": {ID: "I1", PostID: "post1"},
+			"I2": {ID: "I2", Suppressed: true},
+		}
+		store.keys = []string{"subscription-S1", "incident-I1", "incident-I2", "settings-user1"}
+		return h, api
+	}
+
+	t.Run("refuses users who aren't system admins", func(t *testing.T) {
+		h, api := setupReset(t, false)
+
+		response := run(t, h, "/pagerduty admin reset")
+		assert.Contains(t, response.Text, "system admin")
+		api.AssertNotCalled(t, "OpenInteractiveDialog", mock.Anything)
+	})
+
+	t.Run("only reports what a dry run would delete", func(t *testing.T) {
+		h, api := setupReset(t, true)
+
+		response := run(t, h, "/pagerduty admin reset posts dry-run")
+		assert.Equal(t, model.CommandResponseTypeEphemeral, response.ResponseType)
+		assert.Contains(t, response.Text, "Dry Run")
+		assert.Contains(t, response.Text, "1 subscription(s)")
+		assert.Contains(t, response.Text, "2 tracked incident(s)")
+		assert.Contains(t, response.Text, "4 stored record(s)")
+		assert.Contains(t, response.Text, "1 incident post(s)")
+		api.AssertNotCalled(t, "OpenInteractiveDialog", mock.Anything)
+		api.AssertNotCalled(t, "DeletePost", mock.Anything)
+		assert.Len(t, h.kvstore.(*testStore).attachments, 2)
+	})
+
+	t.Run("asks to confirm deleting the posts too", func(t *testing.T) {
+		h, api := setupReset(t, true)
+
+		var dialog model.OpenDialogRequest
+		api.On("OpenInteractiveDialog", mock.Anything).Run(func(args mock.Arguments) {
+			dialog = args.Get(0).(model.OpenDialogRequest)
+		}).Return(nil)
+
+		response := run(t, h, "/pagerduty admin reset posts")
+		assert.Empty(t, response.Text)
+
+		assert.Equal(t, "/plugins/pagerduty/api/v1/admin/reset", dialog.URL)
+		assert.Contains(t, dialog.Dialog.IntroductionText, "1 incident post(s)")
+		var state ResetState
+		require.NoError(t, json.Unmarshal([]byte(dialog.Dialog.State), &state))
+		assert.True(t, state.DeletePosts)
+	})
+
+	t.Run("keeps the posts by default", func(t *testing.T) {
+		h, api := setupReset(t, true)
+
+		var dialog model.OpenDialogRequest
+		api.On("OpenInteractiveDialog", mock.Anything).Run(func(args mock.Arguments) {
+			dialog = args.Get(0).(model.OpenDialogRequest)
+		}).Return(nil)
+
+		run(t, h, "/pagerduty admin reset")
+
+		assert.Contains(t, dialog.Dialog.IntroductionText, "No posts")
+		var state ResetState
+		require.NoError(t, json.Unmarshal([]byte(dialog.Dialog.State), &state))
+		assert.False(t, state.DeletePosts)
+	})
+}
+
+func TestCleanupCommand(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -40)
+
+	// setupCleanup returns a handler whose store tracks incidents of this channel resolved long
+	// ago, recently or reopened, a long-resolved incident of another channel and an open one
+	setupCleanup := func(t *testing.T, admin bool) (*Handler, *plugintest.API, *testStore) {
+		h, api, _ := setupHandler(t, "")
+		api.On("HasPermissionTo", "user1", model.PermissionManageSystem).Return(admin)
+
+		store := h.kvstore.(*testStore)
+		store.attachments = map[string]*pagerduty.PostAttachment{
+			"I1": {ID: "I1", PostID: "post1", ChannelID: "channel1", Incident: pagerduty.Incident{ID: "I1", IncidentNumber: 1, Title: "API is down", Status: client.StatusResolved, LastStatusChangeAt: old}},
+			"I2": {ID: "I2", PostID: "post2", ChannelID: "channel1", Incident: pagerduty.Incident{ID: "I2", Status: client.StatusResolved, LastStatusChangeAt: time.Now()}},
+			"I3": {ID: "I3", PostID: "post3", ChannelID: "channel1", ReopenedAsID: "I5", Incident: pagerduty.Incident{ID: "I3", Status: client.StatusResolved, LastStatusChangeAt: old}},
+			"I4": {ID: "I4", PostID: "post4", ChannelID: "channel2", Incident: pagerduty.Incident{ID: "I4", Status: client.StatusResolved, LastStatusChangeAt: old}},
+			"I5": {ID: "I5", PostID: "post3", ChannelID: "channel1", Incident: pagerduty.Incident{ID: "I5", Status: client.StatusTriggered, CreatedAt: old}},
+		}
+		return h, api, store
+	}
+
+	t.Run("deletes the posts of incidents resolved long ago in this channel", func(t *testing.T) {
+		h, api, store := setupCleanup(t, true)
+		api.On("DeletePost", "post1").Return(nil).Once()
+
+		response := run(t, h, "/pagerduty cleanup 30")
+		assert.Contains(t, response.Text, "Deleted the posts of 2 incident(s)")
+
+		api.AssertNumberOfCalls(t, "DeletePost", 1)
+		assert.NotContains(t, store.attachments, "I1")
+		assert.NotContains(t, store.attachments, "I3", "reopened incidents are forgotten, keeping the post of the reopening incident")
+		assert.ElementsMatch(t, []string{"I2", "I4", "I5"}, slices.Collect(maps.Keys(store.attachments)))
+	})
+
+	t.Run("collapses the posts", func(t *testing.T) {
+		h, api, store := setupCleanup(t, true)
+		api.On("GetPost", "post1").Return(&model.Post{Id: "post1", Message: "API is down"}, nil)
+		api.On("UpdatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.Id == "post1" && assert.Contains(t, post.Message, "Resolved incident [#1]")
+		})).Return(&model.Post{}, nil).Once()
+
+		response := run(t, h, "/pagerduty cleanup 30 collapse")
+		assert.Contains(t, response.Text, "Collapsed the posts of 2 incident(s)")
+
+		api.AssertNotCalled(t, "DeletePost", mock.Anything)
+		assert.ElementsMatch(t, []string{"I2", "I4", "I5"}, slices.Collect(maps.Keys(store.attachments)))
+	})
+
+	t.Run("keeps incidents whose post could not be cleaned up", func(t *testing.T) {
+		h, api, store := setupCleanup(t, true)
+		api.On("DeletePost", "post1").Return(&model.AppError{Message: "unavailable"})
+
+		response := run(t, h, "/pagerduty cleanup 30")
+		assert.Contains(t, response.Text, "1 incident(s) could not be cleaned up")
+		assert.Contains(t, store.attachments, "I1")
+	})
+
+	t.Run("refuses users who aren't system admins", func(t *testing.T) {
+		h, api, store := setupCleanup(t, false)
+
+		response := run(t, h, "/pagerduty cleanup 30")
+		assert.Contains(t, response.Text, "Only system admins")
+		api.AssertNotCalled(t, "DeletePost", mock.Anything)
+		assert.Len(t, store.attachments, 5)
+	})
+
+	t.Run("rejects invalid days", func(t *testing.T) {
+		h, _, store := setupCleanup(t, true)
+
+		response := run(t, h, "/pagerduty cleanup 0")
+		assert.Contains(t, response.Text, "Invalid number of days")
+		assert.Len(t, store.attachments, 5)
+	})
+}
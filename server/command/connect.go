@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+)
+
+// Name of the token element of the connect dialog
+const ConnectDialogToken = "token"
+
+// pdClientForUser gets a PagerDuty client acting as the invoking user if they connected their
+// PagerDuty account, so that PagerDuty records them as the actor of writes, or else the client of
+// the configured API key. A connected token that can't be read is an error rather than a fallback.
+func (h *Handler) pdClientForUser(userID string) (client.PDClient, error) {
+	token, err := h.userToken(userID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get user token", "user_id", userID, "error", err.Error())
+		return nil, errors.Wrap(err, "failed to get user token")
+	}
+
+	if token == "" {
+		return h.pdClient(), nil
+	}
+
+	return h.pdClient().WithAPIKey(token), nil
+}
+
+// connectCommand opens a dialog in which a user enters their PagerDuty user API token. Tokens are
+// never taken as command arguments, which Mattermost may log or keep in the command history.
+func (h *Handler) connectCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) != 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.connect.no_arguments"),
+		}
+	}
+
+	if err := h.client.Frontend.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: args.TriggerId,
+		URL:       fmt.Sprintf("%s/api/v1/connect", h.pluginURLPath),
+		Dialog: model.Dialog{
+			Title:            t("command.connect.dialog.title"),
+			IntroductionText: t("command.connect.dialog.introduction"),
+			SubmitLabel:      t("command.connect.dialog.submit"),
+			Elements: []model.DialogElement{
+				{
+					DisplayName: t("command.connect.dialog.token"),
+					Name:        ConnectDialogToken,
+					Type:        "text",
+					SubType:     "password",
+					HelpText:    t("command.connect.dialog.token_help"),
+				},
+			},
+		},
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.open_dialog", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{}
+}
+
+// disconnectCommand forgets a user's PagerDuty user API token
+func (h *Handler) disconnectCommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if err := h.kvstore.DeleteUserToken(args.UserId); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.disconnect.error.delete_token", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.disconnect.done"),
+	}
+}
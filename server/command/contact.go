@@ -0,0 +1,53 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// contactCommand shows a teammate's PagerDuty account and on-call status, looked up
+// by @username or email address
+func (h *Handler) contactCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) != 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.contact.usage"),
+		}
+	}
+
+	target := params[0]
+
+	var pdUser *pagerduty.User
+	var err error
+	if strings.Contains(strings.TrimPrefix(target, "@"), "@") {
+		pdUser, err = h.pdClient().GetUserByEmail(target)
+	} else {
+		_, pdUser, err = h.getPagerDutyUserByMention(target)
+	}
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.look_up", map[string]interface{}{"Query": target, "Error": err.Error()}),
+		}
+	}
+
+	if pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.no_pagerduty_account", map[string]interface{}{"User": target}),
+		}
+	}
+
+	text := "### " + t("command.contact.title", map[string]interface{}{"User": target}) + "\n\n"
+	text += h.formatPagerDutyAccount(pdUser, h.userTimezone(args.UserId), t)
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// escalateCommand escalates an incident to the given level of its escalation policy, or to the
+// level after its current one
+func (h *Handler) escalateCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 1 || len(params) > 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.escalate.usage"),
+		}
+	}
+
+	incidentID := params[0]
+	if id, ok := incidentIDFromURL(incidentID); ok {
+		incidentID = id
+	}
+
+	var level int
+	if len(params) == 2 {
+		var err error
+		level, err = strconv.Atoi(params[1])
+		if err != nil || level < 1 {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.escalate.error.invalid_level", map[string]interface{}{"Level": params[1]}),
+			}
+		}
+	} else {
+		incident, err := h.pdClient().GetIncident(incidentID)
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.get_incident", map[string]interface{}{"Error": err.Error()}),
+			}
+		}
+
+		options := url.Values{}
+		options.Add("escalation_policy_ids[]", incident.EscalationPolicy.ID)
+
+		onCalls, err := h.pdClient().ListOnCalls(options)
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.get_oncalls", map[string]interface{}{"Error": err.Error()}),
+			}
+		}
+
+		var ok bool
+		if level, ok = incident.NextEscalationLevel(onCalls); !ok {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.escalate.error.last_level"),
+			}
+		}
+	}
+
+	user, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	incident, err := pdClient.EscalateIncident(incidentID, level, user.Email)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.escalate.error.escalate", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.escalate.done", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL, "Level": level}),
+	}
+}
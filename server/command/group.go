@@ -0,0 +1,73 @@
+package command
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// Ways the incident list can be grouped
+const (
+	listGroupService  = "service"
+	listGroupDay      = "day"
+	listGroupPriority = "priority"
+)
+
+// incidentGroup is a section of a grouped incident list
+type incidentGroup struct {
+	Name      string
+	Incidents []pagerduty.Incident
+}
+
+// groupIncidents splits incidents into groups by service, creation day in a timezone, or priority,
+// keeping the order of the incidents within each group. Services are ordered by name, days newest
+// first and priorities by rank.
+func groupIncidents(incidents []pagerduty.Incident, by string, loc *time.Location, t i18n.TranslateFunc) []incidentGroup {
+	var groups []incidentGroup
+	index := map[string]int{}
+	sortKeys := map[string]time.Time{}
+	ranks := map[string]int{}
+
+	for _, incident := range incidents {
+		var name string
+		switch by {
+		case listGroupService:
+			name = incident.Service.DisplayName()
+		case listGroupDay:
+			day := incident.CreatedAt.In(loc)
+			name = day.Format("Monday, January 2, 2006")
+			sortKeys[name] = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+		case listGroupPriority:
+			name = t("command.list.no_priority")
+			if incident.Priority != nil {
+				name = incident.Priority.Name
+			}
+			ranks[name] = priorityRank(incident.Priority)
+		}
+
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, incidentGroup{Name: name})
+		}
+		groups[i].Incidents = append(groups[i].Incidents, incident)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Name, groups[j].Name
+		switch by {
+		case listGroupDay:
+			return sortKeys[a].After(sortKeys[b])
+		case listGroupPriority:
+			if ranks[a] != ranks[b] {
+				return ranks[a] < ranks[b]
+			}
+		}
+		return a < b
+	})
+
+	return groups
+}
@@ -0,0 +1,30 @@
+package command
+
+import (
+	"net/url"
+	"strings"
+)
+
+// incidentIDFromURL parses the incident ID out of a PagerDuty incident URL like
+// https://acme.pagerduty.com/incidents/Q1ABCDEF2GHIJK. It returns false if the text is not one.
+func incidentIDFromURL(text string) (string, bool) {
+	u, err := url.Parse(strings.Trim(text, "<>"))
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "pagerduty.com" && !strings.HasSuffix(host, ".pagerduty.com") {
+		return "", false
+	}
+
+	// The ID follows /incidents/, possibly followed by a tab like /timeline
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "incidents" && segments[i+1] != "" {
+			return segments[i+1], true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,184 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// maintenanceCommand handles the maintenance subcommands
+func (h *Handler) maintenanceCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	if len(params) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         h.translate(args.UserId)("command.maintenance.usage"),
+		}
+	}
+
+	switch params[0] {
+	case "list":
+		return h.listMaintenanceCommand(args, params[1:])
+	case "create":
+		return h.createMaintenanceCommand(args, params[1:])
+	default:
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         h.translate(args.UserId)("command.maintenance.usage"),
+		}
+	}
+}
+
+// listMaintenanceCommand lists the ongoing and upcoming maintenance windows, optionally of a
+// single service
+func (h *Handler) listMaintenanceCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	options := url.Values{}
+	options.Set("filter", "open")
+	if len(params) > 0 {
+		service, err := h.findService(strings.Join(params, " "))
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.find_service", map[string]interface{}{"Error": err.Error()}),
+			}
+		}
+		if service == nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.service_not_found", map[string]interface{}{"Service": strings.Join(params, " ")}),
+			}
+		}
+		options.Add("service_ids[]", service.ID)
+	}
+
+	windows, err := h.pdClient().ListMaintenanceWindows(options)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.maintenance.error.get_windows", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if len(windows) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.maintenance.empty"),
+		}
+	}
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].StartTime.Before(windows[j].StartTime)
+	})
+
+	loc := h.userTimezone(args.UserId)
+	now := time.Now()
+	text := "### " + t("command.maintenance.title") + "\n\n"
+	text += fmt.Sprintf("| %s | %s | %s | %s |\n",
+		t("command.maintenance.column.services"),
+		t("command.shifts.column.start"),
+		t("command.shifts.column.end"),
+		t("command.get.description"),
+	)
+	text += "|:---------|:------|:----|:------------|\n"
+	for _, window := range windows {
+		names := make([]string, 0, len(window.Services))
+		for _, service := range window.Services {
+			names = append(names, service.DisplayName())
+		}
+		sort.Strings(names)
+
+		start := timezone.Format(window.StartTime, loc)
+		if !window.StartTime.After(now) {
+			start = t("command.maintenance.ongoing")
+		}
+
+		text += fmt.Sprintf("| [%s](%s) | %s | %s | %s |\n", strings.Join(names, ", "), window.HTMLURL, start, timezone.Format(window.EndTime, loc), strings.ReplaceAll(flattenActivityText(window.Description), "|", "\\|"))
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// createMaintenanceCommand starts a maintenance window on a service now, lasting for the given
+// duration
+func (h *Handler) createMaintenanceCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.maintenance.usage"),
+		}
+	}
+
+	duration, err := time.ParseDuration(params[1])
+	if err != nil || duration < time.Minute {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.maintenance.error.invalid_duration", map[string]interface{}{"Duration": params[1]}),
+		}
+	}
+
+	service, err := h.findService(params[0])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.find_service", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+	if service == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.service_not_found", map[string]interface{}{"Service": params[0]}),
+		}
+	}
+
+	user, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	description := strings.Join(params[2:], " ")
+	if description == "" {
+		description = fmt.Sprintf("Started by @%s from Mattermost", user.Username)
+	}
+
+	start := time.Now()
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	window, err := pdClient.CreateMaintenanceWindow([]string{service.ID}, start, start.Add(duration), description, user.Email)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.maintenance.error.create", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	// Post incidents of the service in maintenance mode right away
+	if err := h.kvstore.DeleteCachedMaintenanceWindows(service.ID); err != nil {
+		h.client.Log.Warn("Failed to delete cached maintenance windows", "service_id", service.ID, "error", err.Error())
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.maintenance.created", map[string]interface{}{"URL": window.HTMLURL, "Service": service.DisplayName(), "End": timezone.Format(window.EndTime, h.userTimezone(args.UserId))}),
+	}
+}
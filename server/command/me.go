@@ -0,0 +1,128 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// meCommand lists the open incidents assigned to the invoking user, with buttons to acknowledge
+// or resolve each of them
+func (h *Handler) meCommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	pdUser, err := h.getOwnPagerDutyUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_own_account", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.me.no_account"),
+		}
+	}
+
+	options := url.Values{}
+	options.Add("user_ids[]", pdUser.ID)
+	options.Add("statuses[]", client.StatusTriggered)
+	options.Add("statuses[]", client.StatusAcknowledged)
+
+	incidents, err := h.pdClient().ListIncidents(options)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_incidents", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if len(incidents) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.me.empty"),
+		}
+	}
+
+	sortIncidentsByPriority(incidents)
+
+	attachments := make([]*model.SlackAttachment, 0, len(incidents))
+	for _, incident := range incidents {
+		attachments = append(attachments, &model.SlackAttachment{
+			Text: fmt.Sprintf("%s [#%d](%s) %s · %s · %s",
+				formatPriority(incident.Priority),
+				incident.IncidentNumber,
+				incident.HTMLURL,
+				incident.Title,
+				incident.Service.DisplayName(),
+				t("command.list.status."+incident.Status),
+			),
+			Actions: h.myIncidentActions(incident, t),
+		})
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         "### " + t("command.me.title") + "\n\n" + t("command.me.assigned", map[string]interface{}{"Count": len(incidents), "User": pdUser.DisplayName()}),
+		Attachments:  attachments,
+	}
+}
+
+// myIncidentActions returns the buttons to acknowledge and resolve an incident listed by the me
+// command, which use the same endpoints as the buttons of the incident post
+func (h *Handler) myIncidentActions(incident pagerduty.Incident, t i18n.TranslateFunc) []*model.PostAction {
+	var actions []*model.PostAction
+	if incident.Status == client.StatusTriggered {
+		actions = append(actions, &model.PostAction{
+			Id:    "acknowledge",
+			Name:  t("incident.action.acknowledge"),
+			Type:  "button",
+			Style: "primary",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("%s/api/v1/incidents/%s/acknowledge", h.pluginURLPath, incident.ID),
+				Context: map[string]interface{}{
+					"incident_id": incident.ID,
+				},
+			},
+		})
+	}
+
+	return append(actions, &model.PostAction{
+		Id:    "resolve",
+		Name:  t("incident.action.resolve"),
+		Type:  "button",
+		Style: "success",
+		Integration: &model.PostActionIntegration{
+			URL: fmt.Sprintf("%s/api/v1/incidents/%s/resolve", h.pluginURLPath, incident.ID),
+			Context: map[string]interface{}{
+				"incident_id": incident.ID,
+			},
+		},
+	})
+}
+
+// getOwnPagerDutyUser finds the invoking user's PagerDuty user: the owner of their connected
+// user API token if they connected their account, or else the user mapped to them
+func (h *Handler) getOwnPagerDutyUser(userID string) (*pagerduty.User, error) {
+	token, err := h.userToken(userID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get user token", "user_id", userID, "error", err.Error())
+	}
+	if token != "" {
+		pdUser, err := h.pdClient().WithAPIKey(token).GetCurrentUser()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the user of your connected account")
+		}
+		return pdUser, nil
+	}
+
+	return h.getPagerDutyUser(userID)
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+)
+
+// MergeState is the state of the merge confirmation dialog
+type MergeState struct {
+	TargetID  string   `json:"target_id"`
+	SourceIDs []string `json:"source_ids"`
+}
+
+// mergeCommand asks for confirmation in a dialog before merging the source incidents into the
+// target incident
+func (h *Handler) mergeCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.merge.usage"),
+		}
+	}
+
+	var ids []string
+	for _, param := range params {
+		id := param
+		if parsed, ok := incidentIDFromURL(param); ok {
+			id = parsed
+		}
+		if slices.Contains(ids, id) {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.merge.error.duplicate", map[string]interface{}{"ID": id}) + " " + t("command.merge.usage"),
+			}
+		}
+		ids = append(ids, id)
+	}
+
+	state := MergeState{TargetID: ids[0], SourceIDs: ids[1:]}
+	var target string
+	var lines []string
+	for i, id := range ids {
+		incident, err := h.pdClient().GetIncident(id)
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.merge.error.get_incident", map[string]interface{}{"ID": id, "Error": err.Error()}),
+			}
+		}
+
+		if incident.Status == client.StatusResolved {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.merge.error.resolved", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL}),
+			}
+		}
+
+		if i == 0 {
+			target = fmt.Sprintf("#%d %s", incident.IncidentNumber, incident.Title)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("* #%d %s", incident.IncidentNumber, incident.Title))
+	}
+
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.prepare_confirmation", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if err := h.client.Frontend.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: args.TriggerId,
+		URL:       fmt.Sprintf("%s/api/v1/incidents/merge", h.pluginURLPath),
+		Dialog: model.Dialog{
+			Title:            t("command.merge.dialog.title", map[string]interface{}{"Count": len(state.SourceIDs)}),
+			IntroductionText: t("command.merge.dialog.introduction", map[string]interface{}{"Target": target}) + "\n\n" + strings.Join(lines, "\n"),
+			SubmitLabel:      t("command.merge.dialog.submit"),
+			State:            string(stateJSON),
+		},
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.open_confirmation", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{}
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// noteCommand adds a note to an incident on behalf of the user
+func (h *Handler) noteCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.note.usage"),
+		}
+	}
+
+	incidentID := params[0]
+	if id, ok := incidentIDFromURL(incidentID); ok {
+		incidentID = id
+	}
+	content := strings.Join(params[1:], " ")
+
+	user, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	if _, err := pdClient.AddNote(incidentID, content, user.Email); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.note.error.add", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.note.done", map[string]interface{}{"IncidentID": incidentID}),
+	}
+}
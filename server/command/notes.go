@@ -0,0 +1,58 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// Number of notes and status updates shown by the get command
+const maxIncidentActivity = 3
+
+// formatIncidentActivity formats the latest notes and status updates of an incident, newest first.
+// Either is left out if there are none or they can't be fetched, e.g. when status updates are
+// not available on the account's plan.
+func (h *Handler) formatIncidentActivity(incidentID string, loc *time.Location, t i18n.TranslateFunc) string {
+	var text string
+
+	notes, err := h.pdClient().ListIncidentNotes(incidentID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get incident notes", "incident_id", incidentID, "error", err.Error())
+	}
+	if len(notes) > 0 {
+		sort.SliceStable(notes, func(i, j int) bool {
+			return notes[i].CreatedAt.After(notes[j].CreatedAt)
+		})
+
+		text += "\n\n**" + t("command.get.latest_notes") + ":**\n"
+		for _, note := range notes[:min(len(notes), maxIncidentActivity)] {
+			text += fmt.Sprintf("* %s, %s: %s\n", note.User.DisplayName(), timezone.Format(note.CreatedAt, loc), flattenActivityText(note.Content))
+		}
+	}
+
+	updates, err := h.pdClient().ListIncidentStatusUpdates(incidentID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get incident status updates", "incident_id", incidentID, "error", err.Error())
+	}
+	if len(updates) > 0 {
+		sort.SliceStable(updates, func(i, j int) bool {
+			return updates[i].CreatedAt.After(updates[j].CreatedAt)
+		})
+
+		text += "\n\n**" + t("command.get.latest_status_updates") + ":**\n"
+		for _, update := range updates[:min(len(updates), maxIncidentActivity)] {
+			text += fmt.Sprintf("* %s, %s: %s\n", update.Sender.DisplayName(), timezone.Format(update.CreatedAt, loc), flattenActivityText(update.Message))
+		}
+	}
+
+	return strings.TrimSuffix(text, "\n")
+}
+
+// flattenActivityText flattens note or status update text onto a single list item line
+func flattenActivityText(content string) string {
+	return strings.Join(strings.Fields(content), " ")
+}
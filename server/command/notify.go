@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// notifyCommand asks for confirmation before paging a specific person through PagerDuty
+func (h *Handler) notifyCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.notify.usage"),
+		}
+	}
+
+	user, pdUser, err := h.getPagerDutyUserByMention(params[0])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.look_up", map[string]interface{}{"Query": params[0], "Error": err.Error()}),
+		}
+	}
+
+	if pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.no_pagerduty_account", map[string]interface{}{"User": "@" + user.Username}),
+		}
+	}
+
+	message := strings.Join(params[1:], " ")
+	context := map[string]interface{}{
+		"pd_user_id": pdUser.ID,
+		"username":   user.Username,
+		"message":    message,
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Attachments: []*model.SlackAttachment{
+			{
+				Title: t("command.notify.title", map[string]interface{}{"Username": user.Username}),
+				Text:  "> " + message,
+				Actions: []*model.PostAction{
+					{
+						Id:    "confirmnotify",
+						Name:  t("command.notify.page"),
+						Type:  "button",
+						Style: "danger",
+						Integration: &model.PostActionIntegration{
+							URL:     fmt.Sprintf("%s/api/v1/notify/confirm", h.pluginURLPath),
+							Context: context,
+						},
+					},
+					{
+						Id:   "cancelnotify",
+						Name: t("command.notify.cancel"),
+						Type: "button",
+						Integration: &model.PostActionIntegration{
+							URL:     fmt.Sprintf("%s/api/v1/notify/cancel", h.pluginURLPath),
+							Context: context,
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// openCommand lists the open incidents posted in the current channel, with links to their posts
+func (h *Handler) openCommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	openIncidents, err := h.kvstore.GetOpenIncidents(args.ChannelId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.open.error.get_open_incidents", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	var incidents []pagerduty.Incident
+	for incidentID := range openIncidents {
+		attachment, err := h.kvstore.GetIncidentAttachment(incidentID)
+		if err != nil {
+			h.client.Log.Warn("Failed to get incident attachment", "incident_id", incidentID, "error", err.Error())
+			continue
+		}
+		if attachment != nil {
+			incidents = append(incidents, attachment.Incident)
+		}
+	}
+
+	if len(incidents) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.open.empty"),
+		}
+	}
+
+	slices.SortFunc(incidents, func(a, b pagerduty.Incident) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	text := "### " + t("command.open.title") + "\n\n"
+	for _, incident := range incidents {
+		text += fmt.Sprintf("* [#%d %s](%s) - %s\n",
+			incident.IncidentNumber, incident.Title, h.getPostPermalink(openIncidents[incident.ID]),
+			t("command.open.state", map[string]interface{}{"Status": t("command.list.status." + incident.Status), "Urgency": incident.Urgency}))
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// getPostPermalink returns the permalink of a post
+func (h *Handler) getPostPermalink(postID string) string {
+	siteURL := ""
+	if config := h.client.Configuration.GetConfig(); config != nil && config.ServiceSettings.SiteURL != nil {
+		siteURL = strings.TrimSuffix(*config.ServiceSettings.SiteURL, "/")
+	}
+
+	return fmt.Sprintf("%s/_redirect/pl/%s", siteURL, postID)
+}
@@ -0,0 +1,330 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// overridesLookahead is how far ahead to list upcoming overrides
+const overridesLookahead = 30 * 24 * time.Hour
+
+// OverrideCancelLookahead is how far ahead to look up an override before canceling it, to check
+// whose override it is
+const OverrideCancelLookahead = 365 * 24 * time.Hour
+
+// Names of the elements of the override dialog
+const (
+	OverrideDialogSchedule  = "schedule_id"
+	OverrideDialogUser      = "user_id"
+	OverrideDialogStartDate = "start_date"
+	OverrideDialogStartTime = "start_time"
+	OverrideDialogDuration  = "duration"
+)
+
+// Layouts of the date and time fields of the override dialog
+const (
+	OverrideDateLayout = "2006-01-02"
+	OverrideTimeLayout = "15:04"
+)
+
+// overrideDurations are the durations offered in the override dialog, with the IDs of their names
+var overrideDurations = []struct {
+	NameID   string
+	Duration time.Duration
+}{
+	{"command.overrides.duration.30m", 30 * time.Minute},
+	{"command.overrides.duration.1h", time.Hour},
+	{"command.overrides.duration.2h", 2 * time.Hour},
+	{"command.overrides.duration.4h", 4 * time.Hour},
+	{"command.overrides.duration.8h", 8 * time.Hour},
+	{"command.overrides.duration.12h", 12 * time.Hour},
+	{"command.overrides.duration.1d", 24 * time.Hour},
+	{"command.overrides.duration.2d", 48 * time.Hour},
+	{"command.overrides.duration.3d", 72 * time.Hour},
+	{"command.overrides.duration.1w", 7 * 24 * time.Hour},
+}
+
+// overridesCommand lists the upcoming overrides on a schedule, or cancels one
+func (h *Handler) overridesCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) > 0 && strings.EqualFold(params[0], "cancel") {
+		return h.cancelOverrideCommand(args, params[1:], t)
+	}
+	if len(params) > 0 && strings.EqualFold(params[0], "create") {
+		return h.createOverrideCommand(args, t)
+	}
+
+	if len(params) != 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.overrides.usage"),
+		}
+	}
+
+	scheduleID := params[0]
+	now := time.Now()
+
+	overrides, err := h.pdClient().ListOverrides(scheduleID, now, now.Add(overridesLookahead))
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.overrides.error.get", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if len(overrides) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.overrides.empty", map[string]interface{}{"Days": int(overridesLookahead.Hours() / 24)}),
+		}
+	}
+
+	loc := h.userTimezone(args.UserId)
+	var attachments []*model.SlackAttachment
+	for _, override := range overrides {
+		attachments = append(attachments, &model.SlackAttachment{
+			Text: t("command.overrides.override", map[string]interface{}{
+				"User":  override.User.DisplayName(),
+				"Start": timezone.Format(override.Start, loc),
+				"End":   timezone.Format(override.End, loc),
+				"ID":    override.ID,
+			}),
+			Actions: []*model.PostAction{
+				{
+					Id:    "canceloverride",
+					Name:  t("command.overrides.cancel"),
+					Type:  "button",
+					Style: "danger",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("%s/api/v1/schedules/%s/overrides/%s/cancel", h.pluginURLPath, scheduleID, override.ID),
+						Context: map[string]interface{}{
+							"schedule_id": scheduleID,
+							"override_id": override.ID,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         "### " + t("command.overrides.title") + "\n\n" + t("command.overrides.count", map[string]interface{}{"Count": len(overrides), "Days": int(overridesLookahead.Hours() / 24)}),
+		Attachments:  attachments,
+	}
+}
+
+// cancelOverrideCommand cancels an override on a schedule. Only the user the override puts on
+// call or a system admin may cancel it.
+func (h *Handler) cancelOverrideCommand(args *model.CommandArgs, params []string, t i18n.TranslateFunc) *model.CommandResponse {
+	if len(params) != 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.overrides.cancel_usage"),
+		}
+	}
+	scheduleID, overrideID := params[0], params[1]
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	if !h.client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		owner, err := h.isOverrideOwner(pdClient, args.UserId, scheduleID, overrideID)
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.overrides.error.cancel", map[string]interface{}{"Error": err.Error()}),
+			}
+		}
+		if !owner {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.overrides.error.not_allowed", map[string]interface{}{"ID": overrideID}),
+			}
+		}
+	}
+
+	if err := pdClient.DeleteOverride(scheduleID, overrideID); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.overrides.error.cancel", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	h.client.Log.Info("Override canceled", "schedule_id", scheduleID, "override_id", overrideID, "user_id", args.UserId)
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.overrides.canceled", map[string]interface{}{"ID": overrideID}),
+	}
+}
+
+// isOverrideOwner tells whether an override that hasn't ended puts a user on call. An override
+// that can't be found isn't theirs.
+func (h *Handler) isOverrideOwner(pdClient client.PDClient, userID, scheduleID, overrideID string) (bool, error) {
+	pdUser, err := h.getOwnPagerDutyUser(userID)
+	if err != nil {
+		return false, err
+	}
+	if pdUser == nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	overrides, err := pdClient.ListOverrides(scheduleID, now, now.Add(OverrideCancelLookahead))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get overrides")
+	}
+
+	for _, override := range overrides {
+		if override.ID == overrideID {
+			return override.User.ID == pdUser.ID, nil
+		}
+	}
+
+	return false, nil
+}
+
+// createOverrideCommand opens a dialog to put a user on call for a schedule, picking the schedule,
+// the user and the time of the override instead of typing them
+func (h *Handler) createOverrideCommand(args *model.CommandArgs, t i18n.TranslateFunc) *model.CommandResponse {
+	schedules, err := h.pdClient().ListSchedules(url.Values{})
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_schedules", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if len(schedules) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.overrides.no_schedules"),
+		}
+	}
+
+	users, err := h.pdClient().ListUsers()
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_users", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return strings.ToLower(schedules[i].Name) < strings.ToLower(schedules[j].Name)
+	})
+	scheduleOptions := make([]*model.PostActionOptions, 0, len(schedules))
+	for _, schedule := range schedules {
+		scheduleOptions = append(scheduleOptions, &model.PostActionOptions{
+			Text:  schedule.Name,
+			Value: schedule.ID,
+		})
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return strings.ToLower(users[i].DisplayName()) < strings.ToLower(users[j].DisplayName())
+	})
+	userOptions := make([]*model.PostActionOptions, 0, len(users))
+	for _, user := range users {
+		userOptions = append(userOptions, &model.PostActionOptions{
+			Text:  user.DisplayName(),
+			Value: user.ID,
+		})
+	}
+
+	defaultUser := ""
+	if pdUser, err := h.getPagerDutyUser(args.UserId); err == nil && pdUser != nil {
+		defaultUser = pdUser.ID
+	}
+
+	// Start at the next half hour by default, in the user's timezone
+	loc := h.userTimezone(args.UserId)
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute()/30*30, 0, 0, loc).Add(30 * time.Minute)
+
+	timeOptions := make([]*model.PostActionOptions, 0, 48)
+	for at := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC); at.Day() == 1; at = at.Add(30 * time.Minute) {
+		timeOptions = append(timeOptions, &model.PostActionOptions{
+			Text:  at.Format(OverrideTimeLayout),
+			Value: at.Format(OverrideTimeLayout),
+		})
+	}
+
+	durationOptions := make([]*model.PostActionOptions, 0, len(overrideDurations))
+	for _, duration := range overrideDurations {
+		durationOptions = append(durationOptions, &model.PostActionOptions{
+			Text:  t(duration.NameID),
+			Value: duration.Duration.String(),
+		})
+	}
+
+	if err := h.client.Frontend.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: args.TriggerId,
+		URL:       fmt.Sprintf("%s/api/v1/schedules/overrides", h.pluginURLPath),
+		Dialog: model.Dialog{
+			Title:       t("command.overrides.dialog.title"),
+			SubmitLabel: t("command.overrides.dialog.submit"),
+			Elements: []model.DialogElement{
+				{
+					DisplayName: t("command.overrides.dialog.schedule"),
+					Name:        OverrideDialogSchedule,
+					Type:        "select",
+					Options:     scheduleOptions,
+				},
+				{
+					DisplayName: t("command.overrides.dialog.user"),
+					Name:        OverrideDialogUser,
+					Type:        "select",
+					Default:     defaultUser,
+					Options:     userOptions,
+				},
+				{
+					DisplayName: t("command.overrides.dialog.start_date"),
+					Name:        OverrideDialogStartDate,
+					Type:        "text",
+					Default:     start.Format(OverrideDateLayout),
+					Placeholder: "YYYY-MM-DD",
+					HelpText:    t("command.overrides.dialog.timezone", map[string]interface{}{"Timezone": loc.String()}),
+				},
+				{
+					DisplayName: t("command.overrides.dialog.start_time"),
+					Name:        OverrideDialogStartTime,
+					Type:        "select",
+					Default:     start.Format(OverrideTimeLayout),
+					Options:     timeOptions,
+				},
+				{
+					DisplayName: t("command.overrides.dialog.duration"),
+					Name:        OverrideDialogDuration,
+					Type:        "select",
+					Default:     time.Hour.String(),
+					Options:     durationOptions,
+				},
+			},
+		},
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.open_dialog", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{}
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// priorityEmojis maps the rank of a priority (P1, P2, ...) to an emoji
+var priorityEmojis = map[int]string{
+	1: ":red_circle:",
+	2: ":large_orange_circle:",
+	3: ":large_yellow_circle:",
+	4: ":large_blue_circle:",
+	5: ":white_circle:",
+}
+
+// priorityRank returns the rank of a priority named like P1 or SEV-2. Incidents without
+// a recognizable priority rank after all others.
+func priorityRank(priority *pagerduty.Priority) int {
+	if priority == nil {
+		return math.MaxInt
+	}
+
+	isNotDigit := func(r rune) bool {
+		return r < '0' || r > '9'
+	}
+
+	digits := strings.TrimLeftFunc(priority.Name, isNotDigit)
+	if end := strings.IndexFunc(digits, isNotDigit); end >= 0 {
+		digits = digits[:end]
+	}
+
+	rank, err := strconv.Atoi(digits)
+	if err != nil {
+		return math.MaxInt
+	}
+
+	return rank
+}
+
+// formatPriority formats a priority with an emoji
+func formatPriority(priority *pagerduty.Priority) string {
+	if priority == nil {
+		return "-"
+	}
+
+	if emoji, ok := priorityEmojis[priorityRank(priority)]; ok {
+		return emoji + " " + priority.Name
+	}
+
+	return priority.Name
+}
+
+// matchesPriority checks whether an incident has one of a comma-separated list of priorities
+func matchesPriority(incident pagerduty.Incident, priorities string) bool {
+	if incident.Priority == nil {
+		return false
+	}
+
+	for _, priority := range strings.Split(priorities, ",") {
+		if strings.EqualFold(strings.TrimSpace(priority), incident.Priority.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortIncidentsByPriority sorts open incidents first, by priority and then by age, oldest first
+func sortIncidentsByPriority(incidents []pagerduty.Incident) {
+	sort.SliceStable(incidents, func(i, j int) bool {
+		a, b := incidents[i], incidents[j]
+
+		aOpen, bOpen := a.Status != client.StatusResolved, b.Status != client.StatusResolved
+		if aOpen != bOpen {
+			return aOpen
+		}
+
+		if aRank, bRank := priorityRank(a.Priority), priorityRank(b.Priority); aRank != bRank {
+			return aRank < bRank
+		}
+
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+)
+
+// Name of the reason element of the reopen dialog
+const ReopenDialogReason = "reason"
+
+// reopenCommand opens a dialog confirming that a resolved incident should be reopened
+func (h *Handler) reopenCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) != 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.reopen.usage"),
+		}
+	}
+
+	incidentID := params[0]
+	if id, ok := incidentIDFromURL(incidentID); ok {
+		incidentID = id
+	}
+
+	incident, err := h.pdClient().GetIncident(incidentID)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_incident", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if incident.Status != client.StatusResolved {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.reopen.error.not_resolved", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL, "Status": strings.ToLower(t("command.list.status." + incident.Status))}),
+		}
+	}
+
+	if err := h.client.Frontend.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: args.TriggerId,
+		URL:       fmt.Sprintf("%s/api/v1/incidents/reopen", h.pluginURLPath),
+		Dialog: model.Dialog{
+			Title:            t("command.reopen.dialog.title", map[string]interface{}{"Number": incident.IncidentNumber}),
+			IntroductionText: t("command.reopen.dialog.introduction", map[string]interface{}{"Title": incident.Title}),
+			SubmitLabel:      t("command.reopen.dialog.submit"),
+			State:            incident.ID,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: t("command.reopen.dialog.reason"),
+					Name:        ReopenDialogReason,
+					Type:        "textarea",
+					Optional:    true,
+					MaxLength:   3000,
+					HelpText:    t("command.reopen.dialog.reason_help"),
+				},
+			},
+		},
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.open_dialog", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{}
+}
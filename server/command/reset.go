@@ -0,0 +1,113 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+)
+
+// ResetState is the state of the confirmation dialog of the reset command
+type ResetState struct {
+	DeletePosts bool `json:"delete_posts"`
+}
+
+// resetCommand deletes all plugin data, and optionally the posts of tracked incidents, after
+// confirmation in a dialog. A dry run only reports what would be deleted.
+func (h *Handler) resetCommand(args *model.CommandArgs, params []string, t i18n.TranslateFunc) *model.CommandResponse {
+	var state ResetState
+	dryRun := false
+	for _, param := range params {
+		switch strings.ToLower(param) {
+		case "posts":
+			state.DeletePosts = true
+		case "dry-run":
+			dryRun = true
+		default:
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.unknown_option", map[string]interface{}{"Option": param}) + " " + t("command.admin.usage"),
+			}
+		}
+	}
+
+	summary, err := h.resetSummary(state, t)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.admin.reset.error.prepare", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if dryRun {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         "### " + t("command.admin.reset.dry_run.title") + "\n\n" + t("command.admin.reset.dry_run.text") + "\n" + summary,
+		}
+	}
+
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.prepare_confirmation", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if err := h.client.Frontend.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: args.TriggerId,
+		URL:       fmt.Sprintf("%s/api/v1/admin/reset", h.pluginURLPath),
+		Dialog: model.Dialog{
+			Title:            t("command.admin.reset.dialog.title"),
+			IntroductionText: t("command.admin.reset.dialog.deletes") + "\n" + summary + "\n" + t("command.admin.reset.dialog.irreversible"),
+			SubmitLabel:      t("command.admin.reset.dialog.submit"),
+			State:            string(stateJSON),
+		},
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.open_confirmation", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{}
+}
+
+// resetSummary lists what a reset deletes
+func (h *Handler) resetSummary(state ResetState, t i18n.TranslateFunc) (string, error) {
+	keys, err := h.kvstore.ListKeys()
+	if err != nil {
+		return "", err
+	}
+
+	subscriptions, err := h.kvstore.GetSubscriptions()
+	if err != nil {
+		return "", err
+	}
+
+	attachments, err := h.kvstore.GetIncidentAttachments()
+	if err != nil {
+		return "", err
+	}
+
+	posts := 0
+	for _, attachment := range attachments {
+		if attachment.PostID != "" && !attachment.Suppressed {
+			posts++
+		}
+	}
+
+	text := "* " + t("command.admin.reset.summary.subscriptions", map[string]interface{}{"Count": len(subscriptions)}) + "\n"
+	text += "* " + t("command.admin.reset.summary.incidents", map[string]interface{}{"Count": len(attachments)}) + "\n"
+	text += "* " + t("command.admin.reset.summary.records", map[string]interface{}{"Count": len(keys)}) + "\n"
+	if state.DeletePosts {
+		text += "* " + t("command.admin.reset.summary.posts", map[string]interface{}{"Count": posts}) + "\n"
+	} else {
+		text += "* " + t("command.admin.reset.summary.no_posts", map[string]interface{}{"Count": posts}) + "\n"
+	}
+
+	return text, nil
+}
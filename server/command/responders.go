@@ -0,0 +1,148 @@
+package command
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// defaultResponderMessage is sent to the requested responders if the user gives no message
+const defaultResponderMessage = "Please help with this incident."
+
+// respondersCommand handles the responders subcommands
+func (h *Handler) respondersCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 3 || params[0] != "add" {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.responders.usage"),
+		}
+	}
+
+	incidentID := params[1]
+	if id, ok := incidentIDFromURL(incidentID); ok {
+		incidentID = id
+	}
+
+	target, err := h.findResponderTarget(params[2])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.responders.error.find_responder", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	message := strings.Join(params[3:], " ")
+	if message == "" {
+		message = defaultResponderMessage
+	}
+
+	user, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	requester, err := h.matchPagerDutyUser(user)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.find_your_account", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+	if requester == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.responders.error.no_account"),
+		}
+	}
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	if _, err := pdClient.RequestResponders(incidentID, requester.ID, message, []pagerduty.ResponderTarget{*target}, user.Email); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.responders.error.request", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	// Let the incident thread know help is on the way
+	attachment, err := h.kvstore.GetIncidentAttachment(incidentID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get incident attachment", "incident_id", incidentID, "error", err.Error())
+	}
+	if attachment != nil && !attachment.Suppressed && attachment.PostID != "" {
+		quoted := "> " + strings.ReplaceAll(message, "\n", "\n> ")
+		if err := h.client.Post.CreatePost(&model.Post{
+			UserId:    h.botUserID,
+			ChannelId: attachment.ChannelID,
+			RootId:    attachment.PostID,
+			Message:   h.displayLanguage()("thread.responders.requested_help", map[string]interface{}{"Username": user.Username, "Responders": "**" + target.Summary + "**", "Message": quoted}),
+		}); err != nil {
+			h.client.Log.Warn("Failed to post responder request", "incident_id", incidentID, "error", err.Error())
+		} else if err := h.kvstore.MarkResponderRequestPosted(incidentID, target.ID); err != nil {
+			h.client.Log.Warn("Failed to mark responder request as posted", "incident_id", incidentID, "error", err.Error())
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.responders.done", map[string]interface{}{"Responder": target.Summary, "ID": incidentID}),
+	}
+}
+
+// findResponderTarget finds who to request as a responder: a user by @mention, PagerDuty ID or
+// email, or else an escalation policy by ID or name
+func (h *Handler) findResponderTarget(value string) (*pagerduty.ResponderTarget, error) {
+	if strings.HasPrefix(value, "@") {
+		_, pdUser, err := h.getPagerDutyUserByMention(value)
+		if err != nil {
+			return nil, err
+		}
+		if pdUser == nil {
+			return nil, errors.Errorf("%s has no PagerDuty account", value)
+		}
+		return &pagerduty.ResponderTarget{ID: pdUser.ID, Type: pagerduty.ResponderTargetUser, Summary: pdUser.DisplayName()}, nil
+	}
+
+	options := url.Values{}
+	options.Set("query", value)
+	policies, err := h.pdClient().ListEscalationPolicies(options)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if policy.ID == value || strings.EqualFold(policy.Name, value) {
+			return &pagerduty.ResponderTarget{ID: policy.ID, Type: pagerduty.ResponderTargetEscalationPolicy, Summary: policy.Name}, nil
+		}
+	}
+
+	users, err := h.pdClient().ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if user.ID == value || strings.EqualFold(user.Email, value) {
+			return &pagerduty.ResponderTarget{ID: user.ID, Type: pagerduty.ResponderTargetUser, Summary: user.DisplayName()}, nil
+		}
+	}
+
+	if policy, err := h.pdClient().GetEscalationPolicy(value); err == nil {
+		return &pagerduty.ResponderTarget{ID: policy.ID, Type: pagerduty.ResponderTargetEscalationPolicy, Summary: policy.Name}, nil
+	}
+
+	return nil, errors.Errorf("no user or escalation policy matches %s", value)
+}
@@ -0,0 +1,164 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// routeCommand manages the routes of services to channels, which take precedence over the
+// "Service Channel Routes" of the configuration. Only system admins can manage routes.
+func (h *Handler) routeCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if !h.client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.error.not_admin"),
+		}
+	}
+
+	if len(params) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.usage"),
+		}
+	}
+
+	switch strings.ToLower(params[0]) {
+	case "add":
+		return h.addRouteCommand(args, params[1:], t)
+	case "remove":
+		return h.removeRouteCommand(params[1:], t)
+	case "list":
+		return h.listRoutesCommand(t)
+	default:
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.usage"),
+		}
+	}
+}
+
+// addRouteCommand routes a service's incidents to a channel, the current one by default
+func (h *Handler) addRouteCommand(args *model.CommandArgs, params []string, t i18n.TranslateFunc) *model.CommandResponse {
+	if len(params) < 1 || len(params) > 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.usage"),
+		}
+	}
+
+	service, err := h.pdClient().GetService(params[0])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_service", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	channelID := args.ChannelId
+	if len(params) == 2 {
+		channel, err := h.client.Channel.GetByName(args.TeamId, strings.TrimPrefix(params[1], "~"), false)
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.find_channel", map[string]interface{}{"Channel": params[1], "Error": err.Error()}),
+			}
+		}
+		channelID = channel.Id
+	}
+
+	if err := h.kvstore.SaveChannelRoute(&kvstore.ChannelRoute{
+		ServiceID:   service.ID,
+		ServiceName: service.Name,
+		ChannelID:   channelID,
+		CreatorID:   args.UserId,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.error.save", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.route.added", map[string]interface{}{"Service": service.Name, "Channel": h.formatChannel(channelID)}),
+	}
+}
+
+// removeRouteCommand removes a service's route, so its incidents follow the configuration again
+func (h *Handler) removeRouteCommand(params []string, t i18n.TranslateFunc) *model.CommandResponse {
+	if len(params) != 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.usage"),
+		}
+	}
+
+	route, err := h.kvstore.GetChannelRoute(params[0])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.error.get", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if route == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.error.not_found", map[string]interface{}{"ID": params[0]}),
+		}
+	}
+
+	if err := h.kvstore.DeleteChannelRoute(route.ServiceID); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.error.delete", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.route.removed", map[string]interface{}{"Service": route.ServiceName}),
+	}
+}
+
+// listRoutesCommand lists the routes managed with the route command
+func (h *Handler) listRoutesCommand(t i18n.TranslateFunc) *model.CommandResponse {
+	routes, err := h.kvstore.GetChannelRoutes()
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.route.error.list", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	text := "### " + t("command.route.title") + "\n\n"
+	if len(routes) == 0 {
+		text += t("command.route.empty") + "\n"
+	}
+	for _, route := range routes {
+		text += fmt.Sprintf("* **%s** (`%s`) → %s\n", route.ServiceName, route.ServiceID, h.formatChannel(route.ChannelID))
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// formatChannel formats a channel as a ~name link, falling back to its ID
+func (h *Handler) formatChannel(channelID string) string {
+	channel, err := h.client.Channel.Get(channelID)
+	if err != nil {
+		return fmt.Sprintf("`%s`", channelID)
+	}
+	return "~" + channel.Name
+}
@@ -0,0 +1,219 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+const (
+	// Default and maximum number of upcoming shifts shown for a schedule
+	defaultScheduleShifts = 10
+	maxScheduleShifts     = 50
+
+	// scheduleLookahead is how far ahead shifts of a schedule are rendered
+	scheduleLookahead = 30 * 24 * time.Hour
+)
+
+// schedulesCommand lists the schedules of the account with who is currently on call on each,
+// optionally filtered by team and by a query matched against schedule names
+func (h *Handler) schedulesCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	options := url.Values{}
+	var query []string
+	for _, param := range params {
+		if team, ok := strings.CutPrefix(param, "team="); ok {
+			if team == "" {
+				return &model.CommandResponse{
+					ResponseType: model.CommandResponseTypeEphemeral,
+					Text:         t("command.schedules.usage"),
+				}
+			}
+			teamID, err := h.findTeamID(team)
+			if err != nil {
+				return &model.CommandResponse{
+					ResponseType: model.CommandResponseTypeEphemeral,
+					Text:         findTeamErrorText(t, team, err),
+				}
+			}
+			options.Add("team_ids[]", teamID)
+			continue
+		}
+		query = append(query, param)
+	}
+	if len(query) > 0 {
+		options.Set("query", strings.Join(query, " "))
+	}
+
+	schedules, err := h.pdClient().ListSchedules(options)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_schedules", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if len(schedules) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.schedules.empty"),
+		}
+	}
+
+	onCallOptions := url.Values{}
+	for _, schedule := range schedules {
+		onCallOptions.Add("schedule_ids[]", schedule.ID)
+	}
+	onCalls, err := h.pdClient().ListOnCalls(onCallOptions)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_oncalls", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	// The same user is listed once per escalation level the schedule covers
+	onCallUsers := map[string][]string{}
+	for _, onCall := range onCalls {
+		if onCall.Schedule == nil {
+			continue
+		}
+		name := onCall.User.DisplayName()
+		if !slices.Contains(onCallUsers[onCall.Schedule.ID], name) {
+			onCallUsers[onCall.Schedule.ID] = append(onCallUsers[onCall.Schedule.ID], name)
+		}
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return strings.ToLower(schedules[i].Name) < strings.ToLower(schedules[j].Name)
+	})
+
+	text := "### " + t("command.schedules.title") + "\n\n"
+	for _, schedule := range schedules {
+		onCall := t("command.schedules.nobody_on_call")
+		if users := onCallUsers[schedule.ID]; len(users) > 0 {
+			onCall = t("command.schedules.on_call", map[string]interface{}{"Users": "**" + strings.Join(users, "**, **") + "**"})
+		}
+		text += fmt.Sprintf("* [%s](%s) (`%s`), %s\n", schedule.Name, schedule.HTMLURL, schedule.ID, onCall)
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// scheduleCommand shows the next shifts of a schedule, found by ID or name, in the invoking
+// user's timezone
+func (h *Handler) scheduleCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	usage := t("command.schedule.usage", map[string]interface{}{"Max": maxScheduleShifts, "Default": defaultScheduleShifts})
+	if len(params) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         usage,
+		}
+	}
+
+	count := defaultScheduleShifts
+	if len(params) > 1 {
+		if parsed, err := strconv.Atoi(params[len(params)-1]); err == nil {
+			if parsed < 1 || parsed > maxScheduleShifts {
+				return &model.CommandResponse{
+					ResponseType: model.CommandResponseTypeEphemeral,
+					Text:         usage,
+				}
+			}
+			count = parsed
+			params = params[:len(params)-1]
+		}
+	}
+
+	scheduleID, err := h.findScheduleID(strings.Join(params, " "))
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.find_schedule", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	now := time.Now()
+	schedule, err := h.pdClient().GetSchedule(scheduleID, now, now.Add(scheduleLookahead))
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_schedule", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	var entries []pagerduty.ScheduleEntry
+	if schedule.FinalSchedule != nil {
+		entries = schedule.FinalSchedule.Entries
+	}
+
+	text := fmt.Sprintf("### [%s](%s)\n\n", schedule.Name, schedule.HTMLURL)
+	if len(entries) == 0 {
+		text += t("command.schedule.empty", map[string]interface{}{"Days": int(scheduleLookahead.Hours() / 24)})
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         text,
+		}
+	}
+
+	loc := h.userTimezone(args.UserId)
+	text += fmt.Sprintf("| %s | %s | %s |\n", t("command.schedule.column.who"), t("command.shifts.column.start"), t("command.shifts.column.end"))
+	text += "|:----|:------|:----|\n"
+	for _, entry := range entries[:min(count, len(entries))] {
+		text += fmt.Sprintf("| %s | %s | %s |\n", entry.User.DisplayName(), timezone.Format(entry.Start, loc), timezone.Format(entry.End, loc))
+	}
+	if len(entries) < count {
+		text += "\n_" + t("command.schedule.lookahead", map[string]interface{}{"Days": int(scheduleLookahead.Hours() / 24)}) + "_"
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// findScheduleID finds a schedule by ID or by name, preferring an exact name match
+func (h *Handler) findScheduleID(value string) (string, error) {
+	options := url.Values{}
+	options.Set("query", value)
+	schedules, err := h.pdClient().ListSchedules(options)
+	if err != nil {
+		return "", err
+	}
+
+	for _, schedule := range schedules {
+		if schedule.ID == value || strings.EqualFold(schedule.Name, value) {
+			return schedule.ID, nil
+		}
+	}
+
+	switch len(schedules) {
+	case 0:
+		// The query matches names only, so the value may be an ID
+		return value, nil
+	case 1:
+		return schedules[0].ID, nil
+	default:
+		names := make([]string, 0, len(schedules))
+		for _, schedule := range schedules {
+			names = append(names, fmt.Sprintf("%s (`%s`)", schedule.Name, schedule.ID))
+		}
+		return "", errors.Errorf("%d schedules match %s: %s", len(schedules), value, strings.Join(names, ", "))
+	}
+}
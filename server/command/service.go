@@ -0,0 +1,101 @@
+package command
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// createServiceCommand creates a PagerDuty service and subscribes the current channel to it.
+// Only system admins can create services.
+func (h *Handler) createServiceCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if !h.client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.create_service.error.not_admin"),
+		}
+	}
+
+	if len(params) < 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.create_service.usage"),
+		}
+	}
+
+	escalationPolicyID := params[0]
+	urgency := "high"
+	var nameParts, descriptionParts []string
+
+	// The name and description may span several words; a description runs to the end of the command
+	inDescription := false
+	for _, param := range params[1:] {
+		key, value, found := strings.Cut(param, "=")
+		switch {
+		case found && strings.EqualFold(key, "urgency"):
+			switch strings.ToLower(value) {
+			case "high", "low", "severity_based":
+				urgency = strings.ToLower(value)
+			default:
+				return &model.CommandResponse{
+					ResponseType: model.CommandResponseTypeEphemeral,
+					Text:         t("command.create_service.error.invalid_urgency", map[string]interface{}{"Urgency": value}) + " " + t("command.create_service.usage"),
+				}
+			}
+		case found && strings.EqualFold(key, "description"):
+			inDescription = true
+			descriptionParts = append(descriptionParts, value)
+		case inDescription:
+			descriptionParts = append(descriptionParts, param)
+		default:
+			nameParts = append(nameParts, param)
+		}
+	}
+
+	name := strings.Join(nameParts, " ")
+	if name == "" {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.create_service.usage"),
+		}
+	}
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	service, err := pdClient.CreateService(name, strings.Join(descriptionParts, " "), escalationPolicyID, urgency)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.create_service.error.create", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if err := h.kvstore.SaveSubscription(&kvstore.Subscription{
+		ServiceID:   service.ID,
+		ServiceName: service.Name,
+		ChannelID:   args.ChannelId,
+		CreatorID:   args.UserId,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.create_service.error.subscribe", map[string]interface{}{"Name": service.Name, "URL": service.HTMLURL, "ID": service.ID, "Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeInChannel,
+		Text:         h.displayLanguage()("command.create_service.done", map[string]interface{}{"Name": service.Name, "URL": service.HTMLURL, "ID": service.ID}),
+	}
+}
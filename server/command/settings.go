@@ -0,0 +1,99 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+)
+
+// settingsCommand shows or changes the invoking user's personal settings
+func (h *Handler) settingsCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	settings, err := h.kvstore.GetUserSettings(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.settings.error.get", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if len(params) == 0 {
+		text := "### " + t("command.settings.title") + "\n\n"
+		text += "* **ephemeral**: " + formatToggle(settings.EphemeralResponses, t) + " - " + t("command.settings.ephemeral") + "\n"
+		text += "* **assigned**: " + formatToggle(settings.NotifyOnAssignment, t) + " - " + t("command.settings.assigned") + "\n"
+		text += "* **high-urgency**: " + formatToggle(settings.NotifyOnHighUrgency, t) + " - " + t("command.settings.high_urgency") + "\n"
+		text += "* **escalated**: " + formatToggle(settings.NotifyOnEscalation, t) + " - " + t("command.settings.escalated") + "\n"
+		text += "\n" + t("command.settings.change")
+
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         text,
+		}
+	}
+
+	if len(params) != 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.settings.usage"),
+		}
+	}
+
+	value, ok := parseToggle(params[1])
+	if !ok {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.settings.error.invalid_value", map[string]interface{}{"Value": params[1]}),
+		}
+	}
+
+	switch strings.ToLower(params[0]) {
+	case "ephemeral":
+		settings.EphemeralResponses = value
+	case "assigned":
+		settings.NotifyOnAssignment = value
+	case "high-urgency":
+		settings.NotifyOnHighUrgency = value
+	case "escalated":
+		settings.NotifyOnEscalation = value
+	default:
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.settings.error.unknown", map[string]interface{}{"Setting": params[0]}),
+		}
+	}
+
+	if err := h.kvstore.SaveUserSettings(args.UserId, settings); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.settings.error.save", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.settings.done", map[string]interface{}{"Setting": strings.ToLower(params[0]), "Value": formatToggle(value, t)}),
+	}
+}
+
+// parseToggle parses an on/off value
+func parseToggle(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "on", "true", "yes":
+		return true, true
+	case "off", "false", "no":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// formatToggle formats a boolean setting as on/off
+func formatToggle(value bool, t i18n.TranslateFunc) string {
+	if value {
+		return t("command.settings.on")
+	}
+	return t("command.settings.off")
+}
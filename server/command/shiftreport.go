@@ -0,0 +1,204 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+const (
+	// shiftReportLookback is how far back to look for the last on-call shift
+	shiftReportLookback = 7 * 24 * time.Hour
+
+	// Maximum number of incidents listed per report section
+	shiftReportMaxListed = 5
+)
+
+// shiftReportCommand summarizes the incidents of the invoking user's last on-call shift.
+// The report is sent as a DM unless "channel" is passed.
+func (h *Handler) shiftReportCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+	toChannel := len(params) > 0 && strings.EqualFold(params[0], "channel")
+
+	pdUser, err := h.getPagerDutyUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_own_account", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+	if pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.no_account_for_email"),
+		}
+	}
+
+	// Find the shift window and the escalation policies covered during it
+	now := time.Now()
+	onCallParams := url.Values{}
+	onCallParams.Add("user_ids[]", pdUser.ID)
+	onCallParams.Set("since", now.Add(-shiftReportLookback).UTC().Format(time.RFC3339))
+	onCallParams.Set("until", now.UTC().Format(time.RFC3339))
+
+	onCalls, err := h.pdClient().ListOnCalls(onCallParams)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.shift_report.error.get_shifts", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	start, end, policyIDs := lastShiftWindow(onCalls, now)
+	if len(policyIDs) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.shift_report.error.no_shift", map[string]interface{}{"Days": int(shiftReportLookback.Hours() / 24)}),
+		}
+	}
+
+	// Get the incidents of the covered escalation policies during the shift
+	incidentParams := url.Values{}
+	incidentParams.Set("since", start.UTC().Format(time.RFC3339))
+	incidentParams.Set("until", end.UTC().Format(time.RFC3339))
+	incidentParams.Set("limit", "100")
+
+	incidents, err := h.pdClient().ListIncidents(incidentParams)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_incidents", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	var shiftIncidents []pagerduty.Incident
+	for _, incident := range incidents {
+		if policyIDs[incident.EscalationPolicy.ID] {
+			shiftIncidents = append(shiftIncidents, incident)
+		}
+	}
+
+	loc, reportLanguage := h.userTimezone(args.UserId), t
+	if toChannel {
+		loc, reportLanguage = h.displayTimezone(), h.displayLanguage()
+	}
+
+	text := formatShiftReport(pdUser, start, end, shiftIncidents, loc, reportLanguage)
+
+	if toChannel {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeInChannel,
+			Text:         text,
+		}
+	}
+
+	if err := h.client.Post.DM(h.botUserID, args.UserId, &model.Post{Message: text}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.shift_report.error.send", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.shift_report.sent"),
+	}
+}
+
+// lastShiftWindow finds the most recently finished shift among the on-call entries, falling
+// back to the current shift up to now. It returns the window and the escalation policies
+// covered in it.
+func lastShiftWindow(onCalls []pagerduty.OnCall, now time.Time) (time.Time, time.Time, map[string]bool) {
+	var start, end time.Time
+	for _, onCall := range onCalls {
+		if onCall.Start == nil {
+			continue
+		}
+
+		onCallEnd := now
+		if onCall.End != nil && onCall.End.Before(now) {
+			onCallEnd = *onCall.End
+		}
+
+		if end.IsZero() || onCallEnd.After(end) || (onCallEnd.Equal(end) && onCall.Start.Before(start)) {
+			start, end = *onCall.Start, onCallEnd
+		}
+	}
+
+	policyIDs := map[string]bool{}
+	for _, onCall := range onCalls {
+		if onCall.Start == nil || onCall.Start.After(end) {
+			continue
+		}
+		if onCall.End != nil && onCall.End.Before(start) {
+			continue
+		}
+		policyIDs[onCall.EscalationPolicy.ID] = true
+	}
+
+	return start, end, policyIDs
+}
+
+// formatShiftReport renders the shift report
+func formatShiftReport(pdUser *pagerduty.User, start, end time.Time, incidents []pagerduty.Incident, loc *time.Location, t i18n.TranslateFunc) string {
+	var highUrgency, open []pagerduty.Incident
+	resolved := 0
+	for _, incident := range incidents {
+		if incident.Urgency == "high" {
+			highUrgency = append(highUrgency, incident)
+		}
+		if incident.Status == client.StatusResolved {
+			resolved++
+		} else {
+			open = append(open, incident)
+		}
+	}
+
+	text := "### " + t("command.shift_report.title", map[string]interface{}{"Name": pdUser.Name}) + "\n\n"
+	text += t("command.shift_report.shift", map[string]interface{}{"Start": timezone.Format(start, loc), "End": timezone.Format(end, loc)}) + "\n"
+	text += t("command.shift_report.incidents", map[string]interface{}{
+		"Count":       len(incidents),
+		"HighUrgency": len(highUrgency),
+		"Resolved":    resolved,
+		"Open":        len(open),
+	}) + "\n"
+
+	if len(incidents) == 0 {
+		text += "\n" + t("command.shift_report.quiet")
+		return text
+	}
+
+	if len(highUrgency) > 0 {
+		text += "\n#### " + t("command.shift_report.high_urgency") + "\n"
+		text += formatIncidentBullets(highUrgency, t)
+	}
+
+	if len(open) > 0 {
+		text += "\n#### " + t("command.shift_report.carryovers") + "\n"
+		text += formatIncidentBullets(open, t)
+	}
+
+	return text
+}
+
+// formatIncidentBullets renders incidents as a bulleted list, capped at shiftReportMaxListed
+func formatIncidentBullets(incidents []pagerduty.Incident, t i18n.TranslateFunc) string {
+	var text string
+	for i, incident := range incidents {
+		if i == shiftReportMaxListed {
+			text += "* " + t("command.shift_report.more", map[string]interface{}{"Count": len(incidents) - shiftReportMaxListed}) + "\n"
+			break
+		}
+		text += fmt.Sprintf("* [#%d](%s) %s - %s (%s)\n",
+			incident.IncidentNumber, incident.HTMLURL, incident.Title, incident.Service.Name, t("command.list.status."+incident.Status))
+	}
+	return text
+}
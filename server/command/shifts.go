@@ -0,0 +1,133 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+const (
+	// Default and maximum number of weeks to list on-call shifts for. PagerDuty
+	// limits on-call queries to a 90 day range.
+	defaultShiftsWeeks = 2
+	maxShiftsWeeks     = 12
+)
+
+// shiftsCommand lists the invoking user's upcoming on-call shifts across all schedules
+func (h *Handler) shiftsCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	weeks := defaultShiftsWeeks
+	if len(params) > 0 {
+		parsed, err := strconv.Atoi(params[0])
+		if err != nil || parsed < 1 || parsed > maxShiftsWeeks {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.shifts.usage", map[string]interface{}{"Max": maxShiftsWeeks, "Default": defaultShiftsWeeks}),
+			}
+		}
+		weeks = parsed
+	}
+
+	pdUser, err := h.getPagerDutyUser(args.UserId)
+	if err != nil || pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.own_account_not_found"),
+		}
+	}
+
+	now := time.Now()
+	onCallParams := url.Values{}
+	onCallParams.Add("user_ids[]", pdUser.ID)
+	onCallParams.Set("since", now.UTC().Format(time.RFC3339))
+	onCallParams.Set("until", now.AddDate(0, 0, 7*weeks).UTC().Format(time.RFC3339))
+
+	onCalls, err := h.pdClient().ListOnCalls(onCallParams)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_shifts", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	shifts := uniqueShifts(onCalls)
+	if len(shifts) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.shifts.empty", map[string]interface{}{"Weeks": weeks}),
+		}
+	}
+
+	loc := h.userTimezone(args.UserId)
+	text := "### " + t("command.shifts.title", map[string]interface{}{"Weeks": weeks}) + "\n\n"
+	text += fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+		t("command.shifts.column.start"),
+		t("command.shifts.column.end"),
+		t("command.shifts.column.schedule"),
+		t("incident.field.escalation_policy"),
+		t("command.shifts.column.level"),
+	)
+	text += "| --- | --- | --- | --- | --- |\n"
+	for _, shift := range shifts {
+		start, end, schedule := t("command.shifts.always"), t("command.shifts.always"), "-"
+		if shift.Start != nil {
+			start = timezone.Format(*shift.Start, loc)
+		}
+		if shift.End != nil {
+			end = timezone.Format(*shift.End, loc)
+		}
+		if shift.Schedule != nil {
+			schedule = fmt.Sprintf("[%s](%s)", shift.Schedule.Name, shift.Schedule.HTMLURL)
+		}
+
+		text += fmt.Sprintf("| %s | %s | %s | %s | %d |\n", start, end, schedule, shift.EscalationPolicy.Name, shift.EscalationLevel)
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// uniqueShifts removes duplicate on-call entries of the same schedule shift, which PagerDuty
+// returns once per escalation policy using the schedule, and sorts them by start
+func uniqueShifts(onCalls []pagerduty.OnCall) []pagerduty.OnCall {
+	seen := map[string]bool{}
+	var shifts []pagerduty.OnCall
+	for _, onCall := range onCalls {
+		key := onCall.EscalationPolicy.ID
+		if onCall.Schedule != nil {
+			key = onCall.Schedule.ID
+		}
+		if onCall.Start != nil {
+			key += onCall.Start.String()
+		}
+		if onCall.End != nil {
+			key += onCall.End.String()
+		}
+
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		shifts = append(shifts, onCall)
+	}
+
+	// Entries without a start are permanent on-calls, listed first
+	sort.SliceStable(shifts, func(i, j int) bool {
+		if shifts[i].Start == nil || shifts[j].Start == nil {
+			return shifts[i].Start == nil && shifts[j].Start != nil
+		}
+		return shifts[i].Start.Before(*shifts[j].Start)
+	})
+
+	return shifts
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// snoozeCommand snoozes an acknowledged incident and tracks the snooze so the user is
+// notified if the incident is still open when the snooze ends
+func (h *Handler) snoozeCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) != 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.snooze.usage"),
+		}
+	}
+
+	incidentID := params[0]
+	duration, err := time.ParseDuration(params[1])
+	if err != nil || duration <= 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.invalid_duration", map[string]interface{}{"Duration": params[1]}),
+		}
+	}
+
+	user, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	incident, err := pdClient.SnoozeIncident(incidentID, duration, user.Email)
+	if errors.Is(err, client.ErrNotAcknowledged) {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.snooze.error.not_acknowledged"),
+		}
+	}
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.snooze.error.snooze", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	until := time.Now().Add(duration)
+	if err := h.kvstore.SaveSnooze(&kvstore.Snooze{
+		IncidentID: incident.ID,
+		UserID:     args.UserId,
+		Until:      until,
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.snooze.error.track", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.snooze.done", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL, "Until": timezone.Format(until, h.userTimezone(args.UserId))}),
+	}
+}
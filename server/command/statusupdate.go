@@ -0,0 +1,78 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// statusUpdateCommand publishes a status update to the stakeholders of an incident on behalf of
+// the user and posts it in the incident thread
+func (h *Handler) statusUpdateCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.status_update.usage"),
+		}
+	}
+
+	incidentID := params[0]
+	if id, ok := incidentIDFromURL(incidentID); ok {
+		incidentID = id
+	}
+	message := strings.Join(params[1:], " ")
+
+	user, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	statusUpdate, err := pdClient.CreateStatusUpdate(incidentID, message, user.Email)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.status_update.error.publish", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	attachment, err := h.kvstore.GetIncidentAttachment(incidentID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get incident attachment", "incident_id", incidentID, "error", err.Error())
+	}
+	if attachment != nil && !attachment.Suppressed && attachment.PostID != "" {
+		// The status update also arrives by webhook, so only the first to mark it posts it
+		posted, err := h.kvstore.MarkStatusUpdatePosted(statusUpdate.ID)
+		if err != nil {
+			h.client.Log.Warn("Failed to mark status update as posted", "incident_id", incidentID, "error", err.Error())
+		}
+		if posted {
+			quoted := "> " + strings.ReplaceAll(strings.TrimSpace(message), "\n", "\n> ")
+			if err := h.client.Post.CreatePost(&model.Post{
+				UserId:    h.botUserID,
+				ChannelId: attachment.ChannelID,
+				RootId:    attachment.PostID,
+				Message:   h.displayLanguage()("thread.status_update", map[string]interface{}{"Author": "@" + user.Username, "Message": quoted}),
+			}); err != nil {
+				h.client.Log.Warn("Failed to post status update", "incident_id", incidentID, "error", err.Error())
+			}
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.status_update.done", map[string]interface{}{"IncidentID": incidentID}),
+	}
+}
@@ -0,0 +1,260 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// subscribeCommand subscribes the current channel to the incidents of a PagerDuty service, or of
+// all services carrying a tag. Running it again for a subscribed service updates the
+// subscription's options.
+func (h *Handler) subscribeCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.subscribe.usage"),
+		}
+	}
+
+	var services []pagerduty.Service
+	tag, byTag := strings.CutPrefix(params[0], "tag=")
+	if byTag {
+		tagged, err := h.findTaggedServices(tag)
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         findTagErrorText(t, tag, err),
+			}
+		}
+
+		if len(tagged) == 0 {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.no_tagged_services", map[string]interface{}{"Tag": tag}),
+			}
+		}
+		services = tagged
+	} else {
+		service, err := h.pdClient().GetService(params[0])
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.get_service", map[string]interface{}{"Error": err.Error()}),
+			}
+		}
+		services = []pagerduty.Service{*service}
+	}
+
+	// A service is subscribed in a single channel, so refuse instead of moving a subscription
+	// away from the channel that owns it
+	existing := make(map[string]*kvstore.Subscription, len(services))
+	for _, service := range services {
+		subscription, err := h.kvstore.GetSubscription(service.ID)
+		if err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.get_subscription", map[string]interface{}{"Error": err.Error()}),
+			}
+		}
+
+		if subscription != nil && subscription.ChannelID != args.ChannelId {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text: t("command.subscribe.error.other_channel", map[string]interface{}{
+					"Service": service.Name,
+					"Channel": h.formatChannel(subscription.ChannelID),
+				}),
+			}
+		}
+		existing[service.ID] = subscription
+	}
+
+	var names []string
+	for _, service := range services {
+		// Keep the options of an existing subscription in this channel
+		subscription := existing[service.ID]
+		if subscription == nil {
+			subscription = &kvstore.Subscription{
+				ServiceID: service.ID,
+				ChannelID: args.ChannelId,
+				CreatorID: args.UserId,
+				CreatedAt: time.Now(),
+			}
+		}
+		subscription.ServiceName = service.Name
+		if byTag {
+			subscription.Tag = tag
+		}
+
+		if err := applySubscriptionOptions(subscription, params[1:]); err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.subscribe.error.options", map[string]interface{}{"Error": err.Error()}) + " " + t("command.subscribe.usage"),
+			}
+		}
+
+		if err := h.kvstore.SaveSubscription(subscription); err != nil {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.subscribe.error.save", map[string]interface{}{"Error": err.Error()}),
+			}
+		}
+
+		names = append(names, fmt.Sprintf("**%s**", service.Name))
+	}
+
+	channelLanguage := h.displayLanguage()
+	text := channelLanguage("command.subscribe.done", map[string]interface{}{"Service": names[0]})
+	if byTag {
+		text = channelLanguage("command.subscribe.done_tag", map[string]interface{}{"Tag": tag, "Services": strings.Join(names, ", ")})
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeInChannel,
+		Text:         text,
+	}
+}
+
+// applySubscriptionOptions applies the key=value options of the subscribe command to a subscription
+func applySubscriptionOptions(subscription *kvstore.Subscription, params []string) error {
+	for _, param := range params {
+		key, value, _ := strings.Cut(param, "=")
+		switch strings.ToLower(key) {
+		case "label":
+			subscription.Label = value
+		case "fields":
+			fields, err := parseIncidentFields(value)
+			if err != nil {
+				return errors.Errorf("invalid fields: %s", err.Error())
+			}
+			subscription.Fields = fields
+		case "maintenance":
+			switch strings.ToLower(value) {
+			case kvstore.MaintenanceModeNormal, kvstore.MaintenanceModeMute, kvstore.MaintenanceModeSuppress, kvstore.MaintenanceModeQuiet:
+				subscription.MaintenanceMode = strings.ToLower(value)
+			default:
+				return errors.Errorf("invalid maintenance mode %s", value)
+			}
+		default:
+			return errors.Errorf("unknown option %s", param)
+		}
+	}
+
+	return nil
+}
+
+// unsubscribeCommand removes a service's subscription
+func (h *Handler) unsubscribeCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) != 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.unsubscribe.usage"),
+		}
+	}
+
+	subscription, err := h.kvstore.GetSubscription(params[0])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_subscription", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if subscription == nil || subscription.ChannelID != args.ChannelId {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.unsubscribe.error.not_subscribed", map[string]interface{}{"ID": params[0]}),
+		}
+	}
+
+	if err := h.kvstore.DeleteSubscription(subscription.ServiceID); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.unsubscribe.error.delete", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeInChannel,
+		Text:         h.displayLanguage()("command.unsubscribe.done", map[string]interface{}{"Service": subscription.ServiceName}),
+	}
+}
+
+// subscriptionsCommand lists the subscriptions of the current channel
+func (h *Handler) subscriptionsCommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	subscriptions, err := h.kvstore.GetSubscriptions()
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.subscriptions.error.get", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	text := "### " + t("command.subscriptions.title") + "\n\n"
+
+	count := 0
+	for _, subscription := range subscriptions {
+		if subscription.ChannelID != args.ChannelId {
+			continue
+		}
+		text += fmt.Sprintf("* **%s** (`%s`)", subscription.ServiceName, subscription.ServiceID)
+		if subscription.Tag != "" {
+			text += " - " + t("command.subscriptions.tag") + ": `" + subscription.Tag + "`"
+		}
+		if subscription.Label != "" {
+			text += " - " + t("command.subscriptions.label") + ": `" + subscription.Label + "`"
+		}
+		if len(subscription.Fields) > 0 {
+			text += " - " + t("command.subscriptions.fields") + ": " + strings.Join(subscription.Fields, ", ")
+		}
+		if subscription.MaintenanceMode != "" && subscription.MaintenanceMode != kvstore.MaintenanceModeNormal {
+			text += " - " + t("command.subscriptions.maintenance") + ": " + subscription.MaintenanceMode
+		}
+		text += "\n"
+		count++
+	}
+
+	if count == 0 {
+		text += t("command.subscriptions.empty")
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// parseIncidentFields parses a comma-separated list of incident post fields.
+// "default" resets to the default fields.
+func parseIncidentFields(value string) ([]string, error) {
+	if strings.EqualFold(value, "default") {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(strings.ToLower(value), ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(kvstore.IncidentFields, field) {
+			return nil, errors.Errorf("unknown field %s, available fields are %s", field, strings.Join(kvstore.IncidentFields, ", "))
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,216 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// Date format accepted for shift dates
+const shiftDateFormat = "2006-01-02"
+
+// swapCommand proposes swapping on-call shifts on a schedule with a teammate. The teammate
+// is asked to accept or decline in a DM.
+func (h *Handler) swapCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) != 4 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.swap.usage"),
+		}
+	}
+
+	scheduleID := params[0]
+
+	// Dates are days in the requester's timezone
+	loc := h.userTimezone(args.UserId)
+
+	yourDate, err := time.ParseInLocation(shiftDateFormat, params[2], loc)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.invalid_date", map[string]interface{}{"Date": params[2]}),
+		}
+	}
+
+	theirDate, err := time.ParseInLocation(shiftDateFormat, params[3], loc)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.invalid_date", map[string]interface{}{"Date": params[3]}),
+		}
+	}
+
+	pdUser, err := h.getPagerDutyUser(args.UserId)
+	if err != nil || pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.own_account_not_found"),
+		}
+	}
+
+	teammate, teammatePDUser, err := h.getPagerDutyUserByMention(params[1])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.swap.error.find_teammate", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+	if teammatePDUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.no_pagerduty_account", map[string]interface{}{"User": params[1]}),
+		}
+	}
+	if teammate.Id == args.UserId {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.swap.error.self"),
+		}
+	}
+
+	yourShift, err := h.findShift(scheduleID, pdUser.ID, yourDate)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.swap.error.find_your_shift", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	theirShift, err := h.findShift(scheduleID, teammatePDUser.ID, theirDate)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.swap.error.find_their_shift", map[string]interface{}{"User": params[1], "Error": err.Error()}),
+		}
+	}
+
+	swap := &kvstore.ShiftSwap{
+		ID:                  model.NewId(),
+		ScheduleID:          scheduleID,
+		RequesterUserID:     args.UserId,
+		RequesterPDUserID:   pdUser.ID,
+		RequesterShiftStart: *yourShift.Start,
+		RequesterShiftEnd:   *yourShift.End,
+		TargetUserID:        teammate.Id,
+		TargetPDUserID:      teammatePDUser.ID,
+		TargetShiftStart:    *theirShift.Start,
+		TargetShiftEnd:      *theirShift.End,
+		CreatedAt:           time.Now(),
+	}
+
+	if err := h.kvstore.SaveShiftSwap(swap); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.swap.error.save", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	requester, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	post := &model.Post{
+		Props: model.StringInterface{
+			"attachments": []*model.SlackAttachment{h.swapProposalAttachment(swap, requester.Username, yourShift.Schedule, timezone.ForUser(teammate), i18n.ForUser(teammate))},
+		},
+	}
+
+	if err := h.client.Post.DM(h.botUserID, teammate.Id, post); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.swap.error.send", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.swap.sent", map[string]interface{}{"User": "@" + teammate.Username}),
+	}
+}
+
+// findShift finds a user's on-call shift on a schedule that covers the given day, which starts at
+// midnight in the timezone it was parsed in
+func (h *Handler) findShift(scheduleID, pdUserID string, day time.Time) (*pagerduty.OnCall, error) {
+	params := url.Values{}
+	params.Add("schedule_ids[]", scheduleID)
+	params.Add("user_ids[]", pdUserID)
+	params.Set("since", day.UTC().Format(time.RFC3339))
+	params.Set("until", day.AddDate(0, 0, 1).UTC().Format(time.RFC3339))
+
+	onCalls, err := h.pdClient().ListOnCalls(params)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range onCalls {
+		if onCalls[i].Start != nil && onCalls[i].End != nil {
+			return &onCalls[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("no shift on %s", day.Format(shiftDateFormat))
+}
+
+// swapProposalAttachment builds the attachment asking the teammate to accept or decline a swap
+func (h *Handler) swapProposalAttachment(swap *kvstore.ShiftSwap, requesterUsername string, schedule *pagerduty.Schedule, loc *time.Location, t i18n.TranslateFunc) *model.SlackAttachment {
+	scheduleName := swap.ScheduleID
+	if schedule != nil && schedule.Name != "" {
+		scheduleName = fmt.Sprintf("[%s](%s)", schedule.Name, schedule.HTMLURL)
+	}
+
+	actionURL := fmt.Sprintf("%s/api/v1/swaps/%s", h.pluginURLPath, swap.ID)
+
+	return &model.SlackAttachment{
+		Title: t("swap.proposal.title"),
+		Text:  t("swap.proposal.text", map[string]interface{}{"User": "@" + requesterUsername, "Schedule": scheduleName}),
+		Color: "#FFA500",
+		Fields: []*model.SlackAttachmentField{
+			{
+				Title: t("swap.proposal.you_take"),
+				Value: t("swap.proposal.range", map[string]interface{}{"Start": timezone.Format(swap.RequesterShiftStart, loc), "End": timezone.Format(swap.RequesterShiftEnd, loc)}),
+				Short: true,
+			},
+			{
+				Title: t("swap.proposal.they_take"),
+				Value: t("swap.proposal.range", map[string]interface{}{"Start": timezone.Format(swap.TargetShiftStart, loc), "End": timezone.Format(swap.TargetShiftEnd, loc)}),
+				Short: true,
+			},
+		},
+		Actions: []*model.PostAction{
+			{
+				Id:    "acceptswap",
+				Name:  t("swap.proposal.accept"),
+				Type:  "button",
+				Style: "primary",
+				Integration: &model.PostActionIntegration{
+					URL:     actionURL + "/accept",
+					Context: map[string]interface{}{"swap_id": swap.ID},
+				},
+			},
+			{
+				Id:    "declineswap",
+				Name:  t("swap.proposal.decline"),
+				Type:  "button",
+				Style: "danger",
+				Integration: &model.PostActionIntegration{
+					URL:     actionURL + "/decline",
+					Context: map[string]interface{}{"swap_id": swap.ID},
+				},
+			},
+		},
+	}
+}
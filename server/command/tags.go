@@ -0,0 +1,74 @@
+package command
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// errTagNotFound is returned when no PagerDuty tag has the given label
+var errTagNotFound = errors.New("tag not found")
+
+// findTaggedServices finds the services owned by the teams or escalation policies carrying a tag,
+// matching the tag's label ignoring case
+func (h *Handler) findTaggedServices(label string) ([]pagerduty.Service, error) {
+	tags, err := h.pdClient().ListTags(label)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag *pagerduty.Tag
+	for i := range tags {
+		if strings.EqualFold(tags[i].Label, label) {
+			tag = &tags[i]
+			break
+		}
+	}
+	if tag == nil {
+		return nil, errTagNotFound
+	}
+
+	// Services can't be tagged, so they are resolved through their teams and escalation policies
+	teamIDs, err := h.pdClient().ListTaggedEntityIDs(tag.ID, "teams")
+	if err != nil {
+		return nil, err
+	}
+
+	policyIDs, err := h.pdClient().ListTaggedEntityIDs(tag.ID, "escalation_policies")
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := h.pdClient().ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	var tagged []pagerduty.Service
+	for _, service := range services {
+		if service.EscalationPolicy != nil && slices.Contains(policyIDs, service.EscalationPolicy.ID) {
+			tagged = append(tagged, service)
+			continue
+		}
+		for _, team := range service.Teams {
+			if slices.Contains(teamIDs, team.ID) {
+				tagged = append(tagged, service)
+				break
+			}
+		}
+	}
+
+	return tagged, nil
+}
+
+// findTagErrorText describes why the services of a tag couldn't be found in the user's language
+func findTagErrorText(t i18n.TranslateFunc, label string, err error) string {
+	if errors.Is(err, errTagNotFound) {
+		return t("command.error.tag_not_found", map[string]interface{}{"Tag": label})
+	}
+	return t("command.error.resolve_tag", map[string]interface{}{"Error": err.Error()})
+}
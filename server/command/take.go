@@ -0,0 +1,100 @@
+package command
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// Default duration of a "take the pager" override
+const defaultTakeDuration = time.Hour
+
+// takeCommand creates an override putting the invoking user on call for a schedule right now
+func (h *Handler) takeCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 1 || len(params) > 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.take.usage"),
+		}
+	}
+
+	scheduleID := params[0]
+	duration := defaultTakeDuration
+	if len(params) == 2 {
+		parsed, err := time.ParseDuration(params[1])
+		if err != nil || parsed <= 0 {
+			return &model.CommandResponse{
+				ResponseType: model.CommandResponseTypeEphemeral,
+				Text:         t("command.error.invalid_duration", map[string]interface{}{"Duration": params[1]}),
+			}
+		}
+		duration = parsed
+	}
+
+	pdUser, err := h.getPagerDutyUser(args.UserId)
+	if err != nil || pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.own_account_not_found"),
+		}
+	}
+
+	// Find who is currently on call so we can tell the user who they relieved
+	onCallParams := url.Values{}
+	onCallParams.Add("schedule_ids[]", scheduleID)
+	onCallParams.Add("include[]", "users")
+
+	onCalls, err := h.pdClient().ListOnCalls(onCallParams)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.take.error.get_oncall", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	// The schedule has an on-call entry for each escalation policy and level that uses it, so
+	// the same user may be listed more than once
+	var relieved []string
+	seen := map[string]bool{pdUser.ID: true}
+	for _, onCall := range onCalls {
+		if !seen[onCall.User.ID] {
+			seen[onCall.User.ID] = true
+			relieved = append(relieved, onCall.User.Name)
+		}
+	}
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	start := time.Now()
+	end := start.Add(duration)
+
+	if _, err := pdClient.CreateOverride(scheduleID, pdUser.ID, start, end); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.create_override", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	data := map[string]interface{}{"End": timezone.Format(end, h.userTimezone(args.UserId))}
+	text := t("command.take.done", data)
+	if len(relieved) > 0 {
+		data["Relieved"] = strings.Join(relieved, ", ")
+		text = t("command.take.done_relieving", data)
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+)
+
+// errTeamNotFound is returned when no PagerDuty team has the given ID or name
+var errTeamNotFound = errors.New("team not found")
+
+// ambiguousTeamError is returned when several PagerDuty teams match a name and none exactly
+type ambiguousTeamError struct {
+	teams []string
+}
+
+func (e *ambiguousTeamError) Error() string {
+	return fmt.Sprintf("%d teams match: %s", len(e.teams), strings.Join(e.teams, ", "))
+}
+
+// findTeamID finds a PagerDuty team by ID or by name, preferring an exact name match
+func (h *Handler) findTeamID(value string) (string, error) {
+	options := url.Values{}
+	options.Set("query", value)
+	teams, err := h.pdClient().ListTeams(options)
+	if err != nil {
+		return "", err
+	}
+
+	for _, team := range teams {
+		if team.ID == value || strings.EqualFold(team.Name, value) {
+			return team.ID, nil
+		}
+	}
+
+	switch len(teams) {
+	case 0:
+		// The query matches names only, so the value may be an ID
+		team, err := h.pdClient().GetTeam(value)
+		if err != nil {
+			return "", errTeamNotFound
+		}
+		return team.ID, nil
+	case 1:
+		return teams[0].ID, nil
+	default:
+		names := make([]string, 0, len(teams))
+		for _, team := range teams {
+			names = append(names, fmt.Sprintf("%s (`%s`)", team.Name, team.ID))
+		}
+		return "", &ambiguousTeamError{teams: names}
+	}
+}
+
+// findTeamErrorText describes why a team couldn't be found in the user's language
+func findTeamErrorText(t i18n.TranslateFunc, value string, err error) string {
+	var ambiguous *ambiguousTeamError
+	switch {
+	case errors.Is(err, errTeamNotFound):
+		return t("command.error.team_not_found", map[string]interface{}{"Team": value})
+	case errors.As(err, &ambiguous):
+		return t("command.error.team_ambiguous", map[string]interface{}{"Team": value, "Count": len(ambiguous.teams), "Teams": strings.Join(ambiguous.teams, ", ")})
+	default:
+		return t("command.error.find_team", map[string]interface{}{"Error": err.Error()})
+	}
+}
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// Names of the elements of the trigger dialog
+const (
+	TriggerDialogService     = "service_id"
+	TriggerDialogTitle       = "title"
+	TriggerDialogUrgency     = "urgency"
+	TriggerDialogDescription = "description"
+)
+
+// triggerCommand opens a dialog to create a PagerDuty incident
+func (h *Handler) triggerCommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	services, err := h.pdClient().ListServices()
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_services", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if len(services) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.trigger.no_services"),
+		}
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].DisplayName() < services[j].DisplayName()
+	})
+
+	serviceOptions := make([]*model.PostActionOptions, 0, len(services))
+	for _, service := range services {
+		serviceOptions = append(serviceOptions, &model.PostActionOptions{
+			Text:  service.DisplayName(),
+			Value: service.ID,
+		})
+	}
+
+	if err := h.client.Frontend.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: args.TriggerId,
+		URL:       fmt.Sprintf("%s/api/v1/incidents/trigger", h.pluginURLPath),
+		Dialog: model.Dialog{
+			Title:       t("command.trigger.dialog.title"),
+			SubmitLabel: t("command.trigger.dialog.submit"),
+			Elements: []model.DialogElement{
+				{
+					DisplayName: t("incident.field.service"),
+					Name:        TriggerDialogService,
+					Type:        "select",
+					Options:     serviceOptions,
+				},
+				{
+					DisplayName: t("command.list.column.title"),
+					Name:        TriggerDialogTitle,
+					Type:        "text",
+					MaxLength:   1024,
+				},
+				{
+					DisplayName: t("incident.field.urgency"),
+					Name:        TriggerDialogUrgency,
+					Type:        "radio",
+					Default:     "high",
+					Options: []*model.PostActionOptions{
+						{Text: t("command.trigger.dialog.urgency_high"), Value: "high"},
+						{Text: t("command.trigger.dialog.urgency_low"), Value: "low"},
+					},
+				},
+				{
+					DisplayName: t("command.get.description"),
+					Name:        TriggerDialogDescription,
+					Type:        "textarea",
+					Optional:    true,
+				},
+			},
+		},
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.open_dialog", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{}
+}
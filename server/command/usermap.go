@@ -0,0 +1,157 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// mapUserCommand maps a Mattermost user to a PagerDuty user, overriding the match by email.
+// Only system admins can map users.
+func (h *Handler) mapUserCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if !h.client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.map_user.error.not_admin"),
+		}
+	}
+
+	if len(params) != 2 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.map_user.usage"),
+		}
+	}
+
+	user, err := h.client.User.GetByUsername(strings.TrimPrefix(params[0], "@"))
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.find_user", map[string]interface{}{"User": params[0], "Error": err.Error()}),
+		}
+	}
+
+	pdUser, err := h.pdClient().GetUser(params[1])
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.map_user.error.get_pagerduty_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if err := h.kvstore.SaveManualUserMapping(&kvstore.UserMapping{
+		MattermostUserID: user.Id,
+		PagerDutyUser:    *pdUser,
+		CreatorID:        args.UserId,
+		CreatedAt:        time.Now(),
+	}); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.map_user.error.save", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.map_user.done", map[string]interface{}{"Username": user.Username, "Name": pdUser.DisplayName(), "ID": pdUser.ID}),
+	}
+}
+
+// unmapUserCommand removes the manual mapping of a Mattermost user, so the user is matched
+// by email again. Only system admins can unmap users.
+func (h *Handler) unmapUserCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if !h.client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.unmap_user.error.not_admin"),
+		}
+	}
+
+	if len(params) != 1 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.unmap_user.usage"),
+		}
+	}
+
+	user, err := h.client.User.GetByUsername(strings.TrimPrefix(params[0], "@"))
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.find_user", map[string]interface{}{"User": params[0], "Error": err.Error()}),
+		}
+	}
+
+	mapping, err := h.kvstore.GetUserMappingByMattermostID(user.Id)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.unmap_user.error.get", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if mapping == nil || !mapping.Manual {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.unmap_user.error.not_mapped", map[string]interface{}{"Username": user.Username}),
+		}
+	}
+
+	if err := h.kvstore.DeleteManualUserMapping(user.Id); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.unmap_user.error.delete", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.unmap_user.done", map[string]interface{}{"Username": user.Username, "Name": mapping.PagerDutyUser.DisplayName()}),
+	}
+}
+
+// userMappingsCommand lists the manual user mappings. Only system admins can list them.
+func (h *Handler) userMappingsCommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if !h.client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.user_mappings.error.not_admin"),
+		}
+	}
+
+	mappings, err := h.kvstore.GetManualUserMappings()
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.user_mappings.error.get", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	text := "### " + t("command.user_mappings.title") + "\n\n"
+	if len(mappings) == 0 {
+		text += t("command.user_mappings.empty")
+	}
+
+	for _, mapping := range mappings {
+		username := mapping.MattermostUserID
+		if user, err := h.client.User.Get(mapping.MattermostUserID); err == nil {
+			username = "@" + user.Username
+		}
+		text += fmt.Sprintf("* %s → **%s** (`%s`)\n", username, mapping.PagerDutyUser.DisplayName(), mapping.PagerDutyUser.ID)
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
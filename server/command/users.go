@@ -0,0 +1,128 @@
+package command
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// getPagerDutyUser finds the PagerDuty user linked to a Mattermost user by email, caching
+// the match. It returns nil without an error if the user has no PagerDuty account.
+func (h *Handler) getPagerDutyUser(userID string) (*pagerduty.User, error) {
+	user, err := h.client.User.Get(userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+
+	return h.matchPagerDutyUser(user)
+}
+
+// getPagerDutyUserByMention resolves an @username mention to the Mattermost user and their
+// linked PagerDuty user. The PagerDuty user is nil if the user has no PagerDuty account.
+func (h *Handler) getPagerDutyUserByMention(mention string) (*model.User, *pagerduty.User, error) {
+	user, err := h.client.User.GetByUsername(strings.TrimPrefix(mention, "@"))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to find user %s", mention)
+	}
+
+	pdUser, err := h.matchPagerDutyUser(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, pdUser, nil
+}
+
+// matchPagerDutyUser finds the PagerDuty user an admin mapped a Mattermost user to, or else
+// the one with the same email, using the cached match while the email is unchanged
+func (h *Handler) matchPagerDutyUser(user *model.User) (*pagerduty.User, error) {
+	mapping, err := h.kvstore.GetUserMappingByMattermostID(user.Id)
+	if err != nil {
+		h.client.Log.Warn("Failed to get user mapping", "user_id", user.Id, "error", err.Error())
+	}
+	if mapping != nil && mapping.Manual {
+		pdUser := mapping.PagerDutyUser
+		return &pdUser, nil
+	}
+	if mapping != nil && strings.EqualFold(mapping.PagerDutyUser.Email, user.Email) {
+		pdUser := mapping.PagerDutyUser
+		return h.unlessMappedElsewhere(&pdUser, user.Id), nil
+	}
+
+	pdUser, err := h.pdClient().GetUserByEmail(user.Email)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find PagerDuty user")
+	}
+	if pdUser == nil {
+		return nil, nil
+	}
+
+	if err := h.kvstore.SaveUserMapping(&kvstore.UserMapping{MattermostUserID: user.Id, PagerDutyUser: *pdUser}); err != nil {
+		h.client.Log.Warn("Failed to save user mapping", "user_id", user.Id, "error", err.Error())
+	}
+
+	return h.unlessMappedElsewhere(pdUser, user.Id), nil
+}
+
+// unlessMappedElsewhere returns the PagerDuty user matched to a Mattermost user by email, or
+// nil if an admin has mapped the PagerDuty user to a different Mattermost user
+func (h *Handler) unlessMappedElsewhere(pdUser *pagerduty.User, userID string) *pagerduty.User {
+	mapping, err := h.kvstore.GetUserMappingByPagerDutyID(pdUser.ID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get user mapping", "pd_user_id", pdUser.ID, "error", err.Error())
+		return pdUser
+	}
+	if mapping != nil && mapping.Manual && mapping.MattermostUserID != userID {
+		return nil
+	}
+	return pdUser
+}
+
+// userTimezone returns a user's preferred timezone, falling back to UTC
+func (h *Handler) userTimezone(userID string) *time.Location {
+	user, err := h.client.User.Get(userID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get user", "user_id", userID, "error", err.Error())
+		return time.UTC
+	}
+
+	return timezone.ForUser(user)
+}
+
+// translate returns the translate function of a user's locale, falling back to English
+func (h *Handler) translate(userID string) i18n.TranslateFunc {
+	user, err := h.client.User.Get(userID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get user", "user_id", userID, "error", err.Error())
+		return i18n.Load(i18n.DefaultLocale)
+	}
+
+	return i18n.ForUser(user)
+}
+
+// outputLanguage returns the translate function of a command response: the invoking user's
+// language for ephemeral responses, or the display language for responses posted in the channel
+func (h *Handler) outputLanguage(userID, responseType string) i18n.TranslateFunc {
+	if responseType == model.CommandResponseTypeInChannel {
+		return h.displayLanguage()
+	}
+
+	return h.translate(userID)
+}
+
+// outputTimezone returns the timezone of timestamps in a command response: the invoking user's
+// timezone for ephemeral responses, or the display timezone for responses posted in the channel
+func (h *Handler) outputTimezone(userID, responseType string) *time.Location {
+	if responseType == model.CommandResponseTypeInChannel {
+		return h.displayTimezone()
+	}
+
+	return h.userTimezone(userID)
+}
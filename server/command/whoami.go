@@ -0,0 +1,153 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// onCallLookahead is how far ahead to look for the next on-call shift
+const onCallLookahead = 30 * 24 * time.Hour
+
+// whoAmICommand shows the invoking user's linked PagerDuty account
+func (h *Handler) whoAmICommand(args *model.CommandArgs) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	pdUser, err := h.getPagerDutyUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_own_account", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	if pdUser == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.no_account_for_email"),
+		}
+	}
+
+	text := "### " + t("command.whoami.title") + "\n\n"
+	text += h.formatPagerDutyAccount(pdUser, h.userTimezone(args.UserId), t)
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// formatPagerDutyAccount formats a PagerDuty user's account details and on-call status
+func (h *Handler) formatPagerDutyAccount(pdUser *pagerduty.User, loc *time.Location, t i18n.TranslateFunc) string {
+	text := fmt.Sprintf("**%s:** [%s](%s)\n", t("command.account.name"), pdUser.Name, pdUser.HTMLURL)
+	text += fmt.Sprintf("**%s:** %s\n", t("command.account.email"), pdUser.Email)
+	text += fmt.Sprintf("**%s:** %s\n", t("command.account.role"), formatRole(pdUser.Role, t))
+
+	// Format teams
+	if len(pdUser.Teams) > 0 {
+		var names []string
+		for _, team := range pdUser.Teams {
+			names = append(names, team.Name)
+		}
+		text += fmt.Sprintf("**%s:** %s\n", t("command.account.teams"), strings.Join(names, ", "))
+	} else {
+		text += fmt.Sprintf("**%s:** %s\n", t("command.account.teams"), t("command.account.none"))
+	}
+
+	// Format contact methods
+	if summary := formatContactMethods(pdUser.ContactMethods); summary != "" {
+		text += fmt.Sprintf("**%s:** %s\n", t("command.account.contact_methods"), summary)
+	} else {
+		text += fmt.Sprintf("**%s:** %s\n", t("command.account.contact_methods"), t("command.account.no_contact_methods"))
+	}
+
+	// Format on-call status
+	params := url.Values{}
+	params.Add("user_ids[]", pdUser.ID)
+	params.Set("since", time.Now().UTC().Format(time.RFC3339))
+	params.Set("until", time.Now().Add(onCallLookahead).UTC().Format(time.RFC3339))
+
+	onCalls, err := h.pdClient().ListOnCalls(params)
+	if err != nil {
+		text += fmt.Sprintf("**%s:** %s\n", t("command.account.on_call"), t("command.account.on_call_unknown", map[string]interface{}{"Error": err.Error()}))
+	} else {
+		text += fmt.Sprintf("**%s:** %s\n", t("command.account.on_call"), formatNearestShift(onCalls, loc, t))
+	}
+
+	return text
+}
+
+// formatRole formats a PagerDuty role like "limited_user" for display
+func formatRole(role string, t i18n.TranslateFunc) string {
+	if role == "" {
+		return t("command.account.role_unknown")
+	}
+	return cases.Title(language.English).String(strings.ReplaceAll(role, "_", " "))
+}
+
+// formatContactMethods summarizes contact methods by type, e.g. "2 email, 1 phone"
+func formatContactMethods(contactMethods []pagerduty.ContactMethod) string {
+	counts := map[string]int{}
+	for _, contactMethod := range contactMethods {
+		kind := strings.TrimSuffix(strings.TrimSuffix(contactMethod.Type, "_reference"), "_contact_method")
+		counts[strings.ReplaceAll(kind, "_", " ")]++
+	}
+
+	var parts []string
+	for kind, count := range counts {
+		parts = append(parts, fmt.Sprintf("%d %s", count, kind))
+	}
+	sort.Strings(parts)
+
+	return strings.Join(parts, ", ")
+}
+
+// formatNearestShift describes the current or next on-call shift among the entries
+func formatNearestShift(onCalls []pagerduty.OnCall, loc *time.Location, t i18n.TranslateFunc) string {
+	var nearest *pagerduty.OnCall
+	for i := range onCalls {
+		onCall := &onCalls[i]
+		// Entries without a start are permanent on-calls, which are always current
+		if nearest == nil || onCall.Start == nil || (nearest.Start != nil && onCall.Start.Before(*nearest.Start)) {
+			nearest = onCall
+		}
+	}
+
+	if nearest == nil {
+		return t("command.account.shift.none", map[string]interface{}{"Days": int(onCallLookahead.Hours() / 24)})
+	}
+
+	source := nearest.EscalationPolicy.Name
+	if nearest.Schedule != nil && nearest.Schedule.Name != "" {
+		source = nearest.Schedule.Name
+	}
+
+	data := map[string]interface{}{"Source": source, "Level": nearest.EscalationLevel}
+	if nearest.End != nil {
+		data["End"] = timezone.Format(*nearest.End, loc)
+	}
+
+	if nearest.Start == nil || !nearest.Start.After(time.Now()) {
+		if nearest.End == nil {
+			return t("command.account.shift.now", data)
+		}
+		return t("command.account.shift.now_until", data)
+	}
+
+	data["Start"] = timezone.Format(*nearest.Start, loc)
+	if nearest.End == nil {
+		return t("command.account.shift.next", data)
+	}
+	return t("command.account.shift.next_until", data)
+}
@@ -0,0 +1,148 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// Maximum number of users listed when a name matches several PagerDuty users
+const maxWhoisMatches = 10
+
+// whoisCommand looks up a PagerDuty user by name or email and shows their account, contact
+// methods, on-call status and linked Mattermost account
+func (h *Handler) whoisCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) == 0 {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.whois.usage"),
+		}
+	}
+
+	query := strings.Join(params, " ")
+	matches, err := h.findPagerDutyUsers(query)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.look_up", map[string]interface{}{"Query": query, "Error": err.Error()}),
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.whois.no_match", map[string]interface{}{"Query": query}),
+		}
+	case len(matches) > 1:
+		text := t("command.whois.several_matches", map[string]interface{}{"Count": len(matches), "Query": query}) + "\n"
+		for i, match := range matches {
+			if i == maxWhoisMatches {
+				text += "- " + t("command.whois.more_matches", map[string]interface{}{"Count": len(matches) - maxWhoisMatches}) + "\n"
+				break
+			}
+			text += fmt.Sprintf("- [%s](%s) (%s)\n", match.Name, match.HTMLURL, match.Email)
+		}
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         text,
+		}
+	}
+
+	// Users listed by name lack their contact methods and teams
+	pdUser := &matches[0]
+	if len(pdUser.ContactMethods) == 0 && len(pdUser.Teams) == 0 {
+		if detailed, err := h.pdClient().GetUserByEmail(pdUser.Email); err == nil && detailed != nil {
+			pdUser = detailed
+		}
+	}
+
+	text := "### " + t("command.whois.title", map[string]interface{}{"User": pdUser.DisplayName()}) + "\n\n"
+	text += h.formatPagerDutyAccount(pdUser, h.userTimezone(args.UserId), t)
+
+	if user := h.getMattermostUser(pdUser); user != nil {
+		text += fmt.Sprintf("**Mattermost:** @%s\n", user.Username)
+	} else {
+		text += fmt.Sprintf("**Mattermost:** %s\n", t("command.whois.not_linked"))
+	}
+
+	if len(pdUser.ContactMethods) > 0 {
+		text += "\n#### " + t("command.account.contact_methods") + "\n"
+		for _, contactMethod := range pdUser.ContactMethods {
+			kind := strings.TrimSuffix(strings.TrimSuffix(contactMethod.Type, "_reference"), "_contact_method")
+			text += fmt.Sprintf("- %s (%s): %s\n", contactMethod.Label, strings.ReplaceAll(kind, "_", " "), contactMethod.Address)
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
+
+// findPagerDutyUsers finds the PagerDuty users matching an email, or else a name. An exact name
+// match wins over names containing the query.
+func (h *Handler) findPagerDutyUsers(query string) ([]pagerduty.User, error) {
+	if strings.Contains(query, "@") {
+		pdUser, err := h.pdClient().GetUserByEmail(query)
+		if err != nil {
+			return nil, err
+		}
+		if pdUser == nil {
+			return nil, nil
+		}
+		return []pagerduty.User{*pdUser}, nil
+	}
+
+	users, err := h.pdClient().ListUsers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list users")
+	}
+
+	var matches []pagerduty.User
+	for _, user := range users {
+		if user.ID == query || strings.EqualFold(user.Name, query) {
+			return []pagerduty.User{user}, nil
+		}
+		if strings.Contains(strings.ToLower(user.Name), strings.ToLower(query)) {
+			matches = append(matches, user)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Name < matches[j].Name
+	})
+
+	return matches, nil
+}
+
+// getMattermostUser finds the Mattermost user linked to a PagerDuty user, by an admin's mapping
+// or else by email. It returns nil if there is none.
+func (h *Handler) getMattermostUser(pdUser *pagerduty.User) *model.User {
+	mapping, err := h.kvstore.GetUserMappingByPagerDutyID(pdUser.ID)
+	if err != nil {
+		h.client.Log.Warn("Failed to get user mapping", "pd_user_id", pdUser.ID, "error", err.Error())
+	}
+	if mapping != nil && mapping.MattermostUserID != "" {
+		if user, err := h.client.User.Get(mapping.MattermostUserID); err == nil {
+			return user
+		}
+	}
+
+	if pdUser.Email == "" {
+		return nil
+	}
+	user, err := h.client.User.GetByEmail(pdUser.Email)
+	if err != nil {
+		return nil
+	}
+
+	return user
+}
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// workflowCommand handles the workflow subcommands
+func (h *Handler) workflowCommand(args *model.CommandArgs, params []string) *model.CommandResponse {
+	t := h.translate(args.UserId)
+
+	if len(params) < 3 || params[0] != "run" {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.workflow.usage"),
+		}
+	}
+
+	incidentID := params[1]
+	if id, ok := incidentIDFromURL(incidentID); ok {
+		incidentID = id
+	}
+
+	workflow, err := h.findIncidentWorkflow(strings.Join(params[2:], " "))
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.workflow.error.find", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	user, err := h.client.User.Get(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.get_user", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	pdClient, err := h.pdClientForUser(args.UserId)
+	if err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.error.user_token"),
+		}
+	}
+
+	if _, err := pdClient.StartIncidentWorkflow(workflow.ID, incidentID, user.Email); err != nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         t("command.workflow.error.start", map[string]interface{}{"Error": err.Error()}),
+		}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         t("command.workflow.done", map[string]interface{}{"Workflow": workflow.Name, "ID": incidentID}),
+	}
+}
+
+// findIncidentWorkflow finds an incident workflow by ID or by name, preferring an exact name match
+func (h *Handler) findIncidentWorkflow(value string) (*pagerduty.IncidentWorkflow, error) {
+	options := url.Values{}
+	options.Set("query", value)
+	workflows, err := h.pdClient().ListIncidentWorkflows(options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, workflow := range workflows {
+		if workflow.ID == value || strings.EqualFold(workflow.Name, value) {
+			return &workflows[i], nil
+		}
+	}
+
+	switch len(workflows) {
+	case 0:
+		// The query matches names only, so the value may be an ID
+		return &pagerduty.IncidentWorkflow{ID: value, Name: value}, nil
+	case 1:
+		return &workflows[0], nil
+	default:
+		names := make([]string, 0, len(workflows))
+		for _, workflow := range workflows {
+			names = append(names, fmt.Sprintf("%s (`%s`)", workflow.Name, workflow.ID))
+		}
+		return nil, errors.Errorf("%d workflows match %s: %s", len(workflows), value, strings.Join(names, ", "))
+	}
+}
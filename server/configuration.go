@@ -1,9 +1,26 @@
 package main
 
 import (
+	"encoding/json"
+	"net/url"
 	"reflect"
+	"strings"
+	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// Service regions of PagerDuty accounts
+const (
+	serviceRegionUS     = "us"
+	serviceRegionEU     = "eu"
+	serviceRegionCustom = "custom"
 )
 
 // configuration captures the plugin's external configuration as exposed in the Mattermost server
@@ -13,11 +30,95 @@ type configuration struct {
 	// PagerDuty API Key
 	PagerDutyAPIKey string
 
+	// Service region of the PagerDuty account: us, eu or custom
+	PagerDutyServiceRegion string
+
+	// Base URL of the PagerDuty REST API when the service region is custom
+	PagerDutyAPIURL string
+
 	// Webhook Secret for verifying webhook requests from PagerDuty
 	WebhookSecret string
 
+	// Key that encrypts the PagerDuty user API tokens of connected users
+	EncryptionKey string
+
 	// Default channel to post notifications
 	DefaultChannel string
+
+	// Routes of services to channels, one SERVICE_ID=channel rule per line
+	ServiceChannelRoutes string
+
+	// How incidents are posted while their service is under maintenance, unless the subscription
+	// to the service sets it: normal, mute, suppress or quiet
+	MaintenanceMode string
+
+	// Channel incidents are posted to in the quiet maintenance mode
+	MaintenanceChannel string
+
+	// Add the on-call responders to the channel when an incident triggers
+	AutoInviteOnCall bool
+
+	// DM the on-call responders when a high-urgency incident triggers
+	NotifyOnCallHighUrgency bool
+
+	// Poll PagerDuty for recent incidents whose webhooks never arrived
+	PollMissedIncidents bool
+
+	// Check the posts of open incidents against PagerDuty and update those out of date
+	ResyncIncidentPosts bool
+
+	// Days after resolution that an incident's post is tracked for updates, or 0 to keep it forever
+	ResolvedIncidentRetentionDays int
+
+	// Warn on incident posts when assignees have no way to be paged in time
+	WarnUnreachableAssignees bool
+
+	// Token external tools must present to the alert ingest endpoint
+	AlertIngestToken string
+
+	// Events API v2 routing key used to forward ingested alerts to PagerDuty
+	AlertIngestRoutingKey string
+
+	// Events API v2 routing keys of services, one SERVICE_ID=routing_key rule per line
+	ServiceRoutingKeys string
+
+	// Service on which incidents are created to page people with the notify command
+	NotifyServiceID string
+
+	// Post a daily summary of the incidents in each channel
+	DailyDigest bool
+
+	// Time of day, as HH:MM in the display timezone, at which the daily digest is posted
+	DailyDigestTime string
+
+	// Comma-separated durations before on-call shifts at which users get a reminder DM
+	ShiftReminderLeadTimes string
+
+	// IANA timezone in which timestamps are rendered in channel posts
+	DisplayTimezone string
+
+	// Locale, such as es, of the language channel posts are written in
+	DisplayLanguage string
+
+	// Go templates, rendered with the incident, replacing the default title and body of incident
+	// posts
+	IncidentTitleTemplate string
+	IncidentBodyTemplate  string
+
+	// Extra fields of incident posts, as one Title=template field per line
+	IncidentFieldTemplates string
+
+	// Post incidents as plain markdown instead of interactive attachments
+	MarkdownOnly bool
+
+	// Post status changes as replies in the thread of the incident post
+	ThreadedUpdates bool
+
+	// Attach the raw payload of the triggering alert to the thread of new incident posts
+	AttachRawPayload bool
+
+	// Ask for confirmation, with an optional resolution note, before resolving from a button
+	ConfirmResolve bool
 }
 
 // Clone shallow copies the configuration. Your implementation may require a deep copy if
@@ -68,6 +169,22 @@ func (p *Plugin) setConfiguration(configuration *configuration) {
 	p.configuration = configuration
 }
 
+// getIncidentTemplates returns the parsed incident post templates of the active configuration
+func (p *Plugin) getIncidentTemplates() incidentTemplates {
+	p.configurationLock.RLock()
+	defer p.configurationLock.RUnlock()
+
+	return p.incidentTemplates
+}
+
+// setIncidentTemplates replaces the parsed incident post templates under lock
+func (p *Plugin) setIncidentTemplates(templates incidentTemplates) {
+	p.configurationLock.Lock()
+	defer p.configurationLock.Unlock()
+
+	p.incidentTemplates = templates
+}
+
 // OnConfigurationChange is invoked when configuration changes may have been made.
 func (p *Plugin) OnConfigurationChange() error {
 	var configuration = new(configuration)
@@ -77,7 +194,29 @@ func (p *Plugin) OnConfigurationChange() error {
 		return errors.Wrap(err, "failed to load plugin configuration")
 	}
 
+	for _, problem := range parseServiceChannelRoutes(configuration.ServiceChannelRoutes).problems {
+		p.API.LogWarn("Ignoring invalid service channel route", "problem", problem)
+	}
+	_, routingKeyProblems := parseServiceRoutingKeys(configuration.ServiceRoutingKeys)
+	for _, problem := range routingKeyProblems {
+		p.API.LogWarn("Ignoring invalid service routing key", "problem", problem)
+	}
+	templates := parseIncidentTemplates(configuration)
+	for _, problem := range templates.problems {
+		p.API.LogWarn("Ignoring invalid incident template", "problem", problem)
+	}
+
+	previous := p.getConfiguration()
 	p.setConfiguration(configuration)
+	p.setIncidentTemplates(templates)
+	p.clearChannelIDCache()
+
+	// The command's autocomplete is in the display language
+	if p.commandHandler != nil && configuration.DisplayLanguage != previous.DisplayLanguage {
+		if err := p.commandHandler.Register(); err != nil {
+			return errors.Wrap(err, "failed to register commands")
+		}
+	}
 
 	// Initialize or update PagerDuty client with new configuration
 	if configuration.PagerDutyAPIKey != "" {
@@ -88,3 +227,121 @@ func (p *Plugin) OnConfigurationChange() error {
 
 	return nil
 }
+
+// ConfigurationWillBeSaved checks a changed PagerDuty API key or service region against the
+// PagerDuty API before the configuration is saved, so that an invalid key is reported in the
+// System Console instead of failing later on the first webhook or command.
+func (p *Plugin) ConfigurationWillBeSaved(newCfg *model.Config) (*model.Config, error) {
+	settings, ok := newCfg.PluginSettings.Plugins[pluginID]
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal plugin settings")
+	}
+
+	configuration := new(configuration)
+	if err := json.Unmarshal(data, configuration); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal plugin settings")
+	}
+
+	current := p.getConfiguration()
+	if configuration.PagerDutyAPIKey == "" ||
+		(configuration.PagerDutyAPIKey == current.PagerDutyAPIKey &&
+			configuration.PagerDutyServiceRegion == current.PagerDutyServiceRegion &&
+			configuration.PagerDutyAPIURL == current.PagerDutyAPIURL) {
+		return nil, nil
+	}
+
+	baseURL, err := configuration.pagerDutyAPIBaseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := client.NewPagerDutyClient(configuration.PagerDutyAPIKey, baseURL).ListAbilities(); err != nil {
+		if errors.Is(err, client.ErrUnauthorized) {
+			return nil, errors.New("the PagerDuty API key is invalid. Create a General Access REST API key in PagerDuty under Integrations → API Access Keys")
+		}
+		return nil, errors.Wrap(err, "failed to check the PagerDuty API key")
+	}
+
+	return nil, nil
+}
+
+// ensureEncryptionKey generates the key that encrypts user tokens if the configuration has none
+// yet. Servers of a cluster generate it one at a time, so they all end up with the same key.
+func (p *Plugin) ensureEncryptionKey() error {
+	if p.getConfiguration().EncryptionKey != "" {
+		return nil
+	}
+
+	mutex, err := cluster.NewMutex(p.API, "encryption_key")
+	if err != nil {
+		return errors.Wrap(err, "failed to create encryption key mutex")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	// Another server may have generated the key while this one waited for the mutex
+	stored := new(configuration)
+	if err := p.API.LoadPluginConfiguration(stored); err != nil {
+		return errors.Wrap(err, "failed to load plugin configuration")
+	}
+
+	if stored.EncryptionKey == "" {
+		key, err := generateEncryptionKey()
+		if err != nil {
+			return err
+		}
+		stored.EncryptionKey = key
+
+		data, err := json.Marshal(stored)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal plugin settings")
+		}
+		var settings map[string]interface{}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return errors.Wrap(err, "failed to unmarshal plugin settings")
+		}
+		if appErr := p.API.SavePluginConfig(settings); appErr != nil {
+			return errors.Wrap(appErr, "failed to save encryption key")
+		}
+	}
+
+	configuration := p.getConfiguration().Clone()
+	configuration.EncryptionKey = stored.EncryptionKey
+	p.setConfiguration(configuration)
+
+	return nil
+}
+
+// pagerDutyAPIBaseURL returns the base URL of the PagerDuty REST API in the configured service region
+func (c *configuration) pagerDutyAPIBaseURL() (string, error) {
+	switch c.PagerDutyServiceRegion {
+	case "", serviceRegionUS:
+		return client.USAPIBaseURL, nil
+	case serviceRegionEU:
+		return client.EUAPIBaseURL, nil
+	case serviceRegionCustom:
+		apiURL, err := url.Parse(strings.TrimSpace(c.PagerDutyAPIURL))
+		if err != nil || (apiURL.Scheme != "https" && apiURL.Scheme != "http") || apiURL.Host == "" {
+			return "", errors.Errorf("invalid custom PagerDuty API URL %q", c.PagerDutyAPIURL)
+		}
+		return strings.TrimSuffix(apiURL.String(), "/"), nil
+	default:
+		return "", errors.Errorf("unknown PagerDuty service region %q", c.PagerDutyServiceRegion)
+	}
+}
+
+// displayTimezone returns the configured timezone for channel posts, falling back to UTC
+func (p *Plugin) displayTimezone() *time.Location {
+	return timezone.Load(p.getConfiguration().DisplayTimezone)
+}
+
+// displayLanguage returns the translate function of the configured language for channel posts,
+// falling back to English
+func (p *Plugin) displayLanguage() i18n.TranslateFunc {
+	return i18n.Load(p.getConfiguration().DisplayLanguage)
+}
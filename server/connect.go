@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/command"
+)
+
+// pdClientForUser gets a PagerDuty client acting as a user who connected their PagerDuty account,
+// falling back to the client using the plugin's API key for users who did not connect. A token
+// that can't be read is an error rather than a fallback, so the user isn't silently acting with
+// the plugin's privileges.
+func (p *Plugin) pdClientForUser(userID string) (client.PDClient, error) {
+	token, err := p.getUserToken(userID)
+	if err != nil {
+		p.API.LogWarn("Failed to get user token", "user_id", userID, "error", err.Error())
+		return nil, errors.New("your connected PagerDuty token can't be read, run `/pagerduty connect` again")
+	}
+
+	if token == "" {
+		return p.getPagerDutyClient(), nil
+	}
+
+	return p.getPagerDutyClient().WithAPIKey(token), nil
+}
+
+// getUserToken gets the PagerDuty user API token a user connected, decrypted, returning an empty
+// string if none. Decrypting fails if the encryption key was regenerated since the user connected.
+func (p *Plugin) getUserToken(userID string) (string, error) {
+	encrypted, err := p.kvstore.GetUserToken(userID)
+	if err != nil || encrypted == "" {
+		return "", err
+	}
+
+	token, err := decrypt(p.getConfiguration().EncryptionKey, encrypted)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt user token, the user must connect again")
+	}
+	return token, nil
+}
+
+// saveUserToken encrypts and saves the PagerDuty user API token of a user
+func (p *Plugin) saveUserToken(userID, token string) error {
+	encrypted, err := encrypt(p.getConfiguration().EncryptionKey, token)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt user token")
+	}
+	return p.kvstore.SaveUserToken(userID, encrypted)
+}
+
+// handleConnectDialog connects a user's PagerDuty account with the user API token entered in the
+// connect dialog, after checking that the token works
+func (p *Plugin) handleConnectDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	t := p.translate(userID)
+	token, _ := request.Submission[command.ConnectDialogToken].(string)
+	token = strings.TrimSpace(token)
+
+	pdUser, err := p.getPagerDutyClient().WithAPIKey(token).GetCurrentUser()
+	if err != nil {
+		p.writeDialogErrors(w, map[string]string{
+			command.ConnectDialogToken: t("dialog.connect.error.check_token", map[string]interface{}{"Error": err.Error()}),
+		})
+		return
+	}
+
+	if err := p.saveUserToken(userID, token); err != nil {
+		p.API.LogError("Failed to save user token", "user_id", userID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.connect.error.save", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	p.API.SendEphemeralPost(userID, &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: request.ChannelId,
+		Message:   t("dialog.connect.connected", map[string]interface{}{"Name": pdUser.DisplayName(), "Email": pdUser.Email}),
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// encryptUserTokens encrypts the user API tokens that connected users saved before tokens were
+// encrypted. Tokens that are already encrypted, such as those encrypted before an earlier run of
+// the migration failed, are left as they are.
+func (p *Plugin) encryptUserTokens() error {
+	tokens, err := p.kvstore.GetUserTokens()
+	if err != nil {
+		return errors.Wrap(err, "failed to get user tokens")
+	}
+
+	key := p.getConfiguration().EncryptionKey
+	for userID, token := range tokens {
+		if _, err := decrypt(key, token); err == nil {
+			continue
+		}
+		if err := p.saveUserToken(userID, token); err != nil {
+			return errors.Wrapf(err, "failed to encrypt the token of user %s", userID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	// digestPeriod is the period a daily digest covers
+	digestPeriod = 24 * time.Hour
+
+	// digestLookback is how far back incidents are fetched to find those resolved during the
+	// digest period, as PagerDuty filters incidents by their creation time
+	digestLookback = 7 * 24 * time.Hour
+
+	// defaultDigestTime is when the daily digest is posted if no time is configured
+	defaultDigestTime = "09:00"
+)
+
+// digestServiceStats counts the incidents of a service in a daily digest
+type digestServiceStats struct {
+	Name     string
+	Opened   int
+	Resolved int
+	Open     int
+	High     int
+	Low      int
+}
+
+// digestTime parses the configured time of day of the daily digest
+func (c *configuration) digestTime() (hour, minute int, err error) {
+	value := strings.TrimSpace(c.DailyDigestTime)
+	if value == "" {
+		value = defaultDigestTime
+	}
+
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid daily digest time %s, use HH:MM", value)
+	}
+
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+// checkDailyDigest posts the daily digest once the configured time of day has passed, once
+// per day across the cluster
+func (p *Plugin) checkDailyDigest() error {
+	hour, minute, err := p.getConfiguration().digestTime()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(p.displayTimezone())
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if now.Before(scheduled) || now.Sub(scheduled) > jobInterval*2 {
+		return nil
+	}
+
+	posted, err := p.kvstore.MarkDigestPosted(scheduled.Format(time.DateOnly))
+	if err != nil {
+		return err
+	}
+	if !posted {
+		return nil
+	}
+
+	return p.postDailyDigests(now)
+}
+
+// postDailyDigests posts a summary of the incidents of the last day to each channel
+// incidents are posted in
+func (p *Plugin) postDailyDigests(now time.Time) error {
+	since := now.Add(-digestPeriod)
+
+	params := url.Values{}
+	params.Set("since", now.Add(-digestLookback).Format(time.RFC3339))
+	recent, err := p.getJobPagerDutyClient().ListIncidents(params)
+	if err != nil {
+		return errors.Wrap(err, "failed to list recent incidents")
+	}
+
+	params = url.Values{}
+	params.Add("statuses[]", client.StatusTriggered)
+	params.Add("statuses[]", client.StatusAcknowledged)
+	open, err := p.getJobPagerDutyClient().ListIncidents(params)
+	if err != nil {
+		return errors.Wrap(err, "failed to list open incidents")
+	}
+
+	seen := map[string]bool{}
+	incidentsByChannel := map[string][]pagerduty.Incident{}
+	for _, incident := range append(recent, open...) {
+		if seen[incident.ID] {
+			continue
+		}
+		seen[incident.ID] = true
+
+		// Skip incidents resolved before the period unless they were opened during it
+		if incident.Status == client.StatusResolved && !incident.CreatedAt.After(since) && !incident.LastStatusChangeAt.After(since) {
+			continue
+		}
+
+		channelID, err := p.getIncidentChannelID(incident)
+		if err != nil {
+			p.API.LogWarn("Failed to get channel ID", "incident_id", incident.ID, "error", err.Error())
+			continue
+		}
+		incidentsByChannel[channelID] = append(incidentsByChannel[channelID], incident)
+	}
+
+	for channelID, incidents := range incidentsByChannel {
+		if _, appErr := p.API.CreatePost(&model.Post{
+			UserId:    p.botUserID,
+			ChannelId: channelID,
+			Message:   formatDailyDigest(incidents, since, p.displayTimezone(), p.displayLanguage()),
+		}); appErr != nil {
+			p.API.LogWarn("Failed to post daily digest", "channel_id", channelID, "error", appErr.Error())
+		}
+	}
+
+	return nil
+}
+
+// formatDailyDigest formats the digest of a channel's incidents since a time, broken down by
+// service and urgency, with links to the incidents still open
+func formatDailyDigest(incidents []pagerduty.Incident, since time.Time, loc *time.Location, t i18n.TranslateFunc) string {
+	statsByService := map[string]*digestServiceStats{}
+	var totalOpened, totalResolved int
+	var stillOpen []pagerduty.Incident
+
+	for _, incident := range incidents {
+		stats, ok := statsByService[incident.Service.ID]
+		if !ok {
+			stats = &digestServiceStats{Name: incident.Service.DisplayName()}
+			statsByService[incident.Service.ID] = stats
+		}
+
+		if incident.CreatedAt.After(since) {
+			stats.Opened++
+			totalOpened++
+		}
+		if incident.Status == client.StatusResolved {
+			if incident.LastStatusChangeAt.After(since) {
+				stats.Resolved++
+				totalResolved++
+			}
+		} else {
+			stats.Open++
+			stillOpen = append(stillOpen, incident)
+		}
+
+		if incident.Urgency == "high" {
+			stats.High++
+		} else {
+			stats.Low++
+		}
+	}
+
+	text := "### :newspaper: " + t("digest.title") + "\n\n"
+	text += t("digest.summary", map[string]interface{}{
+		"Since":    since.In(loc).Format("Jan 2 15:04 MST"),
+		"Opened":   totalOpened,
+		"Resolved": totalResolved,
+		"Open":     len(stillOpen),
+	}) + "\n\n"
+
+	services := make([]*digestServiceStats, 0, len(statsByService))
+	for _, stats := range statsByService {
+		services = append(services, stats)
+	}
+	slices.SortFunc(services, func(a, b *digestServiceStats) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	text += fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n", t("command.list.column.service"), t("digest.column.opened"), t("digest.column.resolved"),
+		t("digest.column.still_open"), t("command.shift_report.high_urgency"), t("digest.column.low_urgency"))
+	text += "|:--------|-------:|---------:|-----------:|-------------:|------------:|\n"
+	for _, stats := range services {
+		text += fmt.Sprintf("| %s | %d | %d | %d | %d | %d |\n", stats.Name, stats.Opened, stats.Resolved, stats.Open, stats.High, stats.Low)
+	}
+
+	if len(stillOpen) > 0 {
+		slices.SortFunc(stillOpen, func(a, b pagerduty.Incident) int {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		})
+
+		text += "\n**" + t("digest.still_open") + "**\n"
+		for _, incident := range stillOpen {
+			text += t("digest.incident", map[string]interface{}{
+				"Number":  incident.IncidentNumber,
+				"URL":     incident.HTMLURL,
+				"Title":   incident.Title,
+				"Service": incident.Service.DisplayName(),
+				"Urgency": t("digest.urgency." + incident.Urgency),
+				"Status":  strings.ToLower(t("command.list.status." + incident.Status)),
+			}) + "\n"
+		}
+	}
+
+	return text
+}
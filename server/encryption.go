@@ -0,0 +1,83 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// encryptionKeySize is the size in bytes of generated encryption keys
+const encryptionKeySize = 32
+
+// generateEncryptionKey generates a random encryption key for the plugin configuration
+func generateEncryptionKey() (string, error) {
+	key := make([]byte, encryptionKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", errors.Wrap(err, "failed to generate encryption key")
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// newCipher returns an AES-GCM cipher keyed by the configured encryption key
+func newCipher(key string) (cipher.AEAD, error) {
+	if key == "" {
+		return nil, errors.New("no encryption key configured")
+	}
+
+	// Hashing accepts keys of any length, such as keys typed in by an admin
+	hashed := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(hashed[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return gcm, nil
+}
+
+// encrypt encrypts a value with a key, returning it with its nonce, base64 encoded
+func encrypt(key, plaintext string) (string, error) {
+	gcm, err := newCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "failed to generate nonce")
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt decrypts a value encrypted with encrypt. It fails if the key changed since.
+func decrypt(key, ciphertext string) (string, error) {
+	gcm, err := newCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode encrypted value")
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt value")
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryption(t *testing.T) {
+	key, err := generateEncryptionKey()
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		encrypted, err := encrypt(key, "u+abcdef123456")
+		require.NoError(t, err)
+		assert.NotContains(t, encrypted, "u+abcdef123456")
+
+		decrypted, err := decrypt(key, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "u+abcdef123456", decrypted)
+	})
+
+	t.Run("nonces differ", func(t *testing.T) {
+		first, err := encrypt(key, "u+abcdef123456")
+		require.NoError(t, err)
+		second, err := encrypt(key, "u+abcdef123456")
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("regenerated key", func(t *testing.T) {
+		encrypted, err := encrypt(key, "u+abcdef123456")
+		require.NoError(t, err)
+
+		otherKey, err := generateEncryptionKey()
+		require.NoError(t, err)
+		_, err = decrypt(otherKey, encrypted)
+		assert.Error(t, err)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := encrypt("", "u+abcdef123456")
+		assert.Error(t, err)
+		_, err = decrypt("", "c2VjcmV0")
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed value", func(t *testing.T) {
+		_, err := decrypt(key, "not base64!")
+		assert.Error(t, err)
+		_, err = decrypt(key, "c2hvcnQ=")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// performEscalate escalates an incident to the level after its current one
+func (p *Plugin) performEscalate(w http.ResponseWriter, pdClient client.PDClient, incidentID string, user *model.User) {
+	t := i18n.ForUser(user)
+	text, err := p.escalateToNextLevel(pdClient, incidentID, user.Email, t)
+	if err != nil {
+		p.API.LogError("Failed to escalate incident", "incident_id", incidentID, "error", err.Error())
+		text = t("action.error.escalate", map[string]interface{}{"Error": err.Error()})
+	}
+
+	p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+		EphemeralText: text,
+	})
+}
+
+// escalateToNextLevel escalates an incident to the level after its current one, returning a
+// message for the user
+func (p *Plugin) escalateToNextLevel(pdClient client.PDClient, incidentID, userEmail string, t i18n.TranslateFunc) (string, error) {
+	incident, err := pdClient.GetIncident(incidentID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get incident")
+	}
+
+	params := url.Values{}
+	params.Add("escalation_policy_ids[]", incident.EscalationPolicy.ID)
+
+	onCalls, err := pdClient.ListOnCalls(params)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list on-calls")
+	}
+
+	level, ok := incident.NextEscalationLevel(onCalls)
+	if !ok {
+		return t("action.incident.last_level"), nil
+	}
+
+	if _, err := pdClient.EscalateIncident(incidentID, level, userEmail); err != nil {
+		return "", err
+	}
+
+	if paged := p.getEscalationLevelTargets(incident.EscalationPolicy.ID, level, onCalls); len(paged) > 0 {
+		return t("action.incident.escalated_paging", map[string]interface{}{"Level": level, "Paged": strings.Join(paged, ", ")}), nil
+	}
+	return t("action.incident.escalated", map[string]interface{}{"Level": level}), nil
+}
+
+// getEscalationLevelTargets gets the names of who a level of an escalation policy pages: the
+// users on call for it, or else the users and schedules the level targets
+func (p *Plugin) getEscalationLevelTargets(policyID string, level int, onCalls []pagerduty.OnCall) []string {
+	var names []string
+	for _, onCall := range onCalls {
+		if onCall.EscalationPolicy.ID == policyID && onCall.EscalationLevel == level && !slices.Contains(names, onCall.User.DisplayName()) {
+			names = append(names, onCall.User.DisplayName())
+		}
+	}
+	if len(names) > 0 {
+		return names
+	}
+
+	policy := p.getEscalationPolicy(policyID)
+	if policy == nil || level > len(policy.EscalationRules) {
+		return nil
+	}
+	for _, target := range policy.EscalationRules[level-1].Targets {
+		names = append(names, target.Summary)
+	}
+	return names
+}
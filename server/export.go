@@ -0,0 +1,366 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// pluginExport is the plugin data exported to migrate the plugin between environments. Channels
+// and users are exported with their names too, as their IDs differ between environments. The user
+// API tokens of connected users are secrets and never exported, so users connect again after an
+// import.
+type pluginExport struct {
+	SchemaVersion        int                         `json:"schema_version"`
+	ExportedAt           time.Time                   `json:"exported_at"`
+	ServiceChannelRoutes string                      `json:"service_channel_routes,omitempty"`
+	ChannelRoutes        []exportedChannelRoute      `json:"channel_routes"`
+	Subscriptions        []exportedSubscription      `json:"subscriptions"`
+	UserMappings         []exportedUserMapping       `json:"user_mappings"`
+	OpenIncidents        []*pagerduty.PostAttachment `json:"open_incidents"`
+}
+
+// exportedSubscription is a subscription with the name of its channel
+type exportedSubscription struct {
+	*kvstore.Subscription
+	ChannelName string `json:"channel_name,omitempty"`
+}
+
+// exportedChannelRoute is a route managed with the route command with the name of its channel
+type exportedChannelRoute struct {
+	*kvstore.ChannelRoute
+	ChannelName string `json:"channel_name,omitempty"`
+}
+
+// exportedUserMapping is a manual user mapping with the username of its Mattermost user
+type exportedUserMapping struct {
+	*kvstore.UserMapping
+	Username string `json:"username,omitempty"`
+}
+
+// importResult counts what an import saved and describes what it skipped
+type importResult struct {
+	Subscriptions int      `json:"subscriptions"`
+	UserMappings  int      `json:"user_mappings"`
+	OpenIncidents int      `json:"open_incidents"`
+	Routes        int      `json:"routes"`
+	Skipped       []string `json:"skipped,omitempty"`
+}
+
+// handleExport handles exporting the plugin data as JSON. Only system admins can export.
+func (p *Plugin) handleExport(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	export, err := p.exportPluginData()
+	if err != nil {
+		p.API.LogError("Failed to export plugin data", "error", err.Error())
+		http.Error(w, "Failed to export plugin data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p.API.LogInfo("Plugin data exported", "user_id", userID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=pagerduty-plugin-%s.json", export.ExportedAt.Format("20060102-150405")))
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		p.API.LogError("Failed to encode plugin data", "error", err.Error())
+	}
+}
+
+// handleImport handles importing plugin data exported from another environment. Imported data is
+// added to the existing data, replacing subscriptions and mappings of the same services and users.
+// Only system admins can import.
+func (p *Plugin) handleImport(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	var export pluginExport
+	if err := json.NewDecoder(r.Body).Decode(&export); err != nil {
+		http.Error(w, "Invalid plugin data: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if export.SchemaVersion > len(migrations) {
+		http.Error(w, fmt.Sprintf("Plugin data has schema version %d, this plugin version supports up to %d", export.SchemaVersion, len(migrations)), http.StatusBadRequest)
+		return
+	}
+
+	result, err := p.importPluginData(&export)
+	if err != nil {
+		p.API.LogError("Failed to import plugin data", "error", err.Error())
+		http.Error(w, "Failed to import plugin data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p.API.LogInfo("Plugin data imported", "user_id", userID,
+		"subscriptions", result.Subscriptions, "user_mappings", result.UserMappings, "open_incidents", result.OpenIncidents, "routes", result.Routes)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
+
+// exportPluginData collects the subscriptions, routing rules, manual user mappings and open
+// incidents of the plugin
+func (p *Plugin) exportPluginData() (*pluginExport, error) {
+	version, err := p.kvstore.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	export := &pluginExport{
+		SchemaVersion:        version,
+		ExportedAt:           time.Now().UTC(),
+		ServiceChannelRoutes: p.getConfiguration().ServiceChannelRoutes,
+		ChannelRoutes:        []exportedChannelRoute{},
+		Subscriptions:        []exportedSubscription{},
+		UserMappings:         []exportedUserMapping{},
+		OpenIncidents:        []*pagerduty.PostAttachment{},
+	}
+
+	subscriptions, err := p.kvstore.GetSubscriptions()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get subscriptions")
+	}
+	for _, subscription := range subscriptions {
+		exported := exportedSubscription{Subscription: subscription}
+		if channel, appErr := p.API.GetChannel(subscription.ChannelID); appErr == nil {
+			exported.ChannelName = channel.Name
+		}
+		export.Subscriptions = append(export.Subscriptions, exported)
+	}
+
+	routes, err := p.kvstore.GetChannelRoutes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get channel routes")
+	}
+	for _, route := range routes {
+		exported := exportedChannelRoute{ChannelRoute: route}
+		if channel, appErr := p.API.GetChannel(route.ChannelID); appErr == nil {
+			exported.ChannelName = channel.Name
+		}
+		export.ChannelRoutes = append(export.ChannelRoutes, exported)
+	}
+
+	mappings, err := p.kvstore.GetManualUserMappings()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user mappings")
+	}
+	for _, mapping := range mappings {
+		exported := exportedUserMapping{UserMapping: mapping}
+		if user, appErr := p.API.GetUser(mapping.MattermostUserID); appErr == nil {
+			exported.Username = user.Username
+		}
+		export.UserMappings = append(export.UserMappings, exported)
+	}
+
+	attachments, err := p.kvstore.GetIncidentAttachments()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get incident attachments")
+	}
+	for _, attachment := range attachments {
+		if attachment.Incident.Status != client.StatusResolved && attachment.MergedIntoID == "" {
+			export.OpenIncidents = append(export.OpenIncidents, attachment)
+		}
+	}
+
+	return export, nil
+}
+
+// importPluginData saves exported plugin data. Channels and users are found by ID, or by name if
+// the data was exported from another environment. Incidents already tracked here are kept.
+func (p *Plugin) importPluginData(export *pluginExport) (*importResult, error) {
+	result := &importResult{}
+
+	for _, subscription := range export.Subscriptions {
+		if subscription.Subscription == nil || subscription.ServiceID == "" {
+			continue
+		}
+
+		channelID, err := p.findImportedChannelID(subscription.ChannelID, subscription.ChannelName)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("subscription to service %s: %s", subscription.ServiceID, err.Error()))
+			continue
+		}
+		subscription.ChannelID = channelID
+
+		if err := p.kvstore.SaveSubscription(subscription.Subscription); err != nil {
+			return result, err
+		}
+		result.Subscriptions++
+	}
+
+	for _, route := range export.ChannelRoutes {
+		if route.ChannelRoute == nil || route.ServiceID == "" {
+			continue
+		}
+
+		channelID, err := p.findImportedChannelID(route.ChannelID, route.ChannelName)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("route of service %s: %s", route.ServiceID, err.Error()))
+			continue
+		}
+		route.ChannelID = channelID
+
+		if err := p.kvstore.SaveChannelRoute(route.ChannelRoute); err != nil {
+			return result, err
+		}
+		result.Routes++
+	}
+
+	for _, mapping := range export.UserMappings {
+		if mapping.UserMapping == nil || mapping.PagerDutyUser.ID == "" {
+			continue
+		}
+
+		user, appErr := p.API.GetUser(mapping.MattermostUserID)
+		if appErr != nil && mapping.Username != "" {
+			user, appErr = p.API.GetUserByUsername(mapping.Username)
+		}
+		if appErr != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("mapping of PagerDuty user %s: user @%s not found", mapping.PagerDutyUser.ID, mapping.Username))
+			continue
+		}
+		mapping.MattermostUserID = user.Id
+
+		if err := p.kvstore.SaveManualUserMapping(mapping.UserMapping); err != nil {
+			return result, err
+		}
+		result.UserMappings++
+	}
+
+	for _, attachment := range export.OpenIncidents {
+		if attachment == nil || attachment.ID == "" {
+			continue
+		}
+
+		imported, err := p.importOpenIncident(attachment)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("incident %s: %s", attachment.ID, err.Error()))
+			continue
+		}
+		if imported {
+			result.OpenIncidents++
+		}
+	}
+
+	routes, err := p.importServiceChannelRoutes(export.ServiceChannelRoutes)
+	if err != nil {
+		return result, err
+	}
+	result.Routes += routes
+
+	return result, nil
+}
+
+// importOpenIncident tracks an imported open incident under its lock, so it isn't posted twice
+// when a webhook for it arrives during the import. Incidents already tracked here are kept.
+func (p *Plugin) importOpenIncident(attachment *pagerduty.PostAttachment) (bool, error) {
+	unlock, err := p.lockIncident(attachment.ID)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	existing, err := p.getIncidentAttachment(attachment.ID)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		return false, nil
+	}
+
+	// Posts of another environment don't exist here, so the incident gets a new post
+	if attachment.PostID != "" {
+		if _, appErr := p.API.GetPost(attachment.PostID); appErr != nil {
+			attachment.PostID = ""
+		}
+	}
+	if attachment.PostID == "" && !attachment.Suppressed {
+		channelID, err := p.getIncidentChannelID(attachment.Incident)
+		if err != nil {
+			return false, err
+		}
+		if _, err := p.postIncident(attachment.Incident, channelID, ""); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if err := p.storeIncidentAttachment(attachment); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findImportedChannelID finds an imported channel by ID, falling back to its name
+func (p *Plugin) findImportedChannelID(channelID, channelName string) (string, error) {
+	if channel, appErr := p.API.GetChannel(channelID); appErr == nil {
+		return channel.Id, nil
+	}
+	if channelName == "" {
+		return "", errors.Errorf("channel %s not found", channelID)
+	}
+
+	return p.findChannelID(channelName)
+}
+
+// importServiceChannelRoutes appends the imported routing rules that differ from the configured
+// ones to the configuration, so they take precedence, and returns how many were added
+func (p *Plugin) importServiceChannelRoutes(value string) (int, error) {
+	current := p.getConfiguration().ServiceChannelRoutes
+	configured := parseServiceChannelRoutes(current).channels
+
+	var added []string
+	for serviceID, channel := range parseServiceChannelRoutes(value).channels {
+		if configured[serviceID] != channel {
+			added = append(added, fmt.Sprintf("%s=%s", serviceID, channel))
+		}
+	}
+	if len(added) == 0 {
+		return 0, nil
+	}
+
+	routes := strings.TrimRight(current, "\n")
+	if routes != "" {
+		routes += "\n"
+	}
+	routes += strings.Join(added, "\n")
+
+	config := p.API.GetPluginConfig()
+	if config == nil {
+		config = map[string]any{}
+	}
+	key := "ServiceChannelRoutes"
+	for existing := range config {
+		if strings.EqualFold(existing, key) {
+			key = existing
+		}
+	}
+	config[key] = routes
+
+	if appErr := p.API.SavePluginConfig(config); appErr != nil {
+		return 0, errors.Wrap(appErr, "failed to save routing rules")
+	}
+
+	return len(added), nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client/mocks"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// importStore keeps the imported subscriptions, user mappings and incident attachments in memory
+type importStore struct {
+	attachmentStore
+	subscriptions map[string]*kvstore.Subscription
+	mappings      map[string]*kvstore.UserMapping
+}
+
+func (s *importStore) GetSubscription(serviceID string) (*kvstore.Subscription, error) {
+	return s.subscriptions[serviceID], nil
+}
+
+func (s *importStore) SaveSubscription(subscription *kvstore.Subscription) error {
+	s.subscriptions[subscription.ServiceID] = subscription
+	return nil
+}
+
+func (s *importStore) SaveManualUserMapping(mapping *kvstore.UserMapping) error {
+	s.mappings[mapping.MattermostUserID] = mapping
+	return nil
+}
+
+func (s *importStore) GetCachedChannelID(channelName string) (string, error) {
+	return "", nil
+}
+
+func (s *importStore) SaveCachedChannelID(channelName, channelID string) error {
+	return nil
+}
+
+func TestImportPluginData(t *testing.T) {
+	setup := func(t *testing.T, attachments ...*pagerduty.PostAttachment) (*Plugin, *plugintest.API, *importStore) {
+		pdClient := mocks.NewMockPDClient(gomock.NewController(t))
+		pdClient.EXPECT().ListIncidentAlerts(gomock.Any()).Return(nil, nil).AnyTimes()
+
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		for _, args := range [][]interface{}{{mock.Anything}, {mock.Anything, mock.Anything, mock.Anything}, {mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}, {mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}} {
+			api.On("LogDebug", args...).Maybe()
+			api.On("LogInfo", args...).Maybe()
+			api.On("LogWarn", args...).Maybe()
+		}
+
+		// Channels and users of the other environment only exist here under the same names
+		api.On("GetChannel", "channel1").Return(&model.Channel{Id: "channel1", Name: "incidents"}, nil).Maybe()
+		api.On("GetChannel", mock.Anything).Return(nil, &model.AppError{Message: "not found"}).Maybe()
+		api.On("GetTeams").Return([]*model.Team{{Id: "team1", Name: "ops"}}, nil).Maybe()
+		api.On("GetChannelByName", "team1", "alerts", false).Return(&model.Channel{Id: "channel2", Name: "alerts"}, nil).Maybe()
+		api.On("GetChannelByName", "team1", mock.Anything, false).Return(nil, &model.AppError{Message: "not found"}).Maybe()
+		api.On("GetChannelsForTeamForUser", "team1", "me", false).Return([]*model.Channel{}, nil).Maybe()
+		api.On("GetUser", mock.Anything).Return(nil, &model.AppError{Message: "not found"}).Maybe()
+		api.On("GetUserByUsername", "alice").Return(&model.User{Id: "user1", Username: "alice"}, nil).Maybe()
+		api.On("GetUserByUsername", mock.Anything).Return(nil, &model.AppError{Message: "not found"}).Maybe()
+
+		store := &importStore{
+			attachmentStore: attachmentStore{attachments: map[string]*pagerduty.PostAttachment{}},
+			subscriptions:   map[string]*kvstore.Subscription{},
+			mappings:        map[string]*kvstore.UserMapping{},
+		}
+		for _, attachment := range attachments {
+			copied := *attachment
+			store.attachments[attachment.ID] = &copied
+		}
+
+		p := &Plugin{pdClient: pdClient, kvstore: store}
+		p.SetAPI(api)
+		return p, api, store
+	}
+
+	t.Run("finds channels and users by name", func(t *testing.T) {
+		p, _, store := setup(t)
+
+		result, err := p.importPluginData(&pluginExport{
+			Subscriptions: []exportedSubscription{
+				{Subscription: &kvstore.Subscription{ServiceID: "S1", ChannelID: "channel1"}, ChannelName: "incidents"},
+				{Subscription: &kvstore.Subscription{ServiceID: "S2", ChannelID: "elsewhere"}, ChannelName: "alerts"},
+				{Subscription: &kvstore.Subscription{ServiceID: "S3", ChannelID: "elsewhere"}, ChannelName: "missing"},
+			},
+			UserMappings: []exportedUserMapping{
+				{UserMapping: &kvstore.UserMapping{MattermostUserID: "elsewhere", PagerDutyUser: pagerduty.User{ID: "P1"}}, Username: "alice"},
+				{UserMapping: &kvstore.UserMapping{MattermostUserID: "elsewhere", PagerDutyUser: pagerduty.User{ID: "P2"}}, Username: "bob"},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, result.Subscriptions)
+		assert.Equal(t, "channel1", store.subscriptions["S1"].ChannelID)
+		assert.Equal(t, "channel2", store.subscriptions["S2"].ChannelID)
+		assert.NotContains(t, store.subscriptions, "S3")
+
+		assert.Equal(t, 1, result.UserMappings)
+		require.Contains(t, store.mappings, "user1")
+		assert.Equal(t, "P1", store.mappings["user1"].PagerDutyUser.ID)
+
+		require.Len(t, result.Skipped, 2)
+		assert.Contains(t, result.Skipped[0], "service S3")
+		assert.Contains(t, result.Skipped[1], "@bob")
+	})
+
+	t.Run("keeps incidents already tracked", func(t *testing.T) {
+		tracked := &pagerduty.PostAttachment{ID: "I1", PostID: "post1", ChannelID: "channel1", Incident: pagerduty.Incident{ID: "I1", Status: client.StatusAcknowledged}}
+		p, api, store := setup(t, tracked)
+
+		result, err := p.importPluginData(&pluginExport{OpenIncidents: []*pagerduty.PostAttachment{
+			{ID: "I1", PostID: "other", ChannelID: "channel3", Incident: pagerduty.Incident{ID: "I1", Status: client.StatusTriggered}},
+		}})
+		require.NoError(t, err)
+
+		assert.Zero(t, result.OpenIncidents)
+		assert.Equal(t, *tracked, *store.attachments["I1"])
+		api.AssertNotCalled(t, "CreatePost", mock.Anything)
+	})
+
+	t.Run("posts incidents whose post doesn't exist here", func(t *testing.T) {
+		p, api, store := setup(t)
+		store.subscriptions["S1"] = &kvstore.Subscription{ServiceID: "S1", ChannelID: "channel1"}
+		api.On("GetPost", "post1").Return(&model.Post{Id: "post1", ChannelId: "channel1"}, nil)
+		api.On("GetPost", "elsewhere").Return(nil, &model.AppError{Message: "not found"})
+
+		var posted *model.Post
+		api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+			posted = args.Get(0).(*model.Post)
+		}).Return(&model.Post{Id: "post2"}, nil).Once()
+
+		incident := pagerduty.Incident{ID: "I2", IncidentNumber: 2, Title: "API is down", Status: client.StatusTriggered, Service: pagerduty.Service{ID: "S1"}}
+		result, err := p.importPluginData(&pluginExport{OpenIncidents: []*pagerduty.PostAttachment{
+			{ID: "I1", PostID: "post1", ChannelID: "channel1", Incident: pagerduty.Incident{ID: "I1", Status: client.StatusTriggered}},
+			{ID: "I2", PostID: "elsewhere", ChannelID: "channel3", Incident: incident},
+		}})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, result.OpenIncidents)
+		assert.Empty(t, result.Skipped)
+		assert.Equal(t, "post1", store.attachments["I1"].PostID, "existing posts are kept")
+
+		require.NotNil(t, posted)
+		assert.Equal(t, "channel1", posted.ChannelId, "incidents are posted to the channel of their service")
+		require.Contains(t, store.attachments, "I2")
+		assert.Equal(t, "post2", store.attachments["I2"].PostID)
+		assert.Equal(t, "channel1", store.attachments["I2"].ChannelID)
+		api.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,75 @@
+// Package i18n translates user-facing messages into a user's or the configured display language.
+package i18n
+
+import (
+	"embed"
+	"path"
+
+	"github.com/mattermost/go-i18n/i18n/bundle"
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// DefaultLocale is the language of messages that have no translation in the requested language
+const DefaultLocale = "en"
+
+// TranslateFunc translates a message by ID, filling in its template with the optional data
+type TranslateFunc func(id string, data ...interface{}) string
+
+//go:embed translations/*.json
+var translationFiles embed.FS
+
+// messages holds the translations of all messages, loaded once from the embedded files
+var messages = loadMessages()
+
+// loadMessages loads the embedded translation files, one per language named after its locale
+func loadMessages() *bundle.Bundle {
+	messages := bundle.New()
+
+	entries, err := translationFiles.ReadDir("translations")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		name := path.Join("translations", entry.Name())
+		buf, err := translationFiles.ReadFile(name)
+		if err != nil {
+			panic(err)
+		}
+		if err := messages.ParseTranslationFileBytes(name, buf); err != nil {
+			panic(err)
+		}
+	}
+
+	return messages
+}
+
+// Load returns the translate function of a locale, such as `es` or `pt-BR`. Messages fall back
+// to English if the locale or the message has no translation.
+func Load(locale string) TranslateFunc {
+	fallback := messages.MustTfunc(DefaultLocale)
+	if locale == "" {
+		return TranslateFunc(fallback)
+	}
+
+	translate, err := messages.Tfunc(locale, DefaultLocale)
+	if err != nil {
+		return TranslateFunc(fallback)
+	}
+
+	return func(id string, data ...interface{}) string {
+		// Missing translations come back as their ID
+		if text := translate(id, data...); text != id {
+			return text
+		}
+		return fallback(id, data...)
+	}
+}
+
+// ForUser returns the translate function of a user's locale, falling back to English
+func ForUser(user *model.User) TranslateFunc {
+	if user == nil {
+		return Load(DefaultLocale)
+	}
+
+	return Load(user.Locale)
+}
@@ -0,0 +1,36 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("translates into the locale", func(t *testing.T) {
+		assert.Equal(t, "Servicio", Load("es")("incident.field.service"))
+	})
+
+	t.Run("falls back to English for unknown locales", func(t *testing.T) {
+		assert.Equal(t, "Service", Load("xx")("incident.field.service"))
+		assert.Equal(t, "Service", Load("")("incident.field.service"))
+	})
+
+	t.Run("fills in templates", func(t *testing.T) {
+		text := Load("en")("command.error.unknown_subcommand", map[string]interface{}{"Subcommand": "foo"})
+		assert.Equal(t, "Unknown subcommand: foo. Try `/pagerduty help` for available commands.", text)
+	})
+
+	t.Run("uses the user's locale", func(t *testing.T) {
+		assert.Equal(t, "Urgencia", ForUser(&model.User{Locale: "es"})("incident.field.urgency"))
+		assert.Equal(t, "Urgency", ForUser(nil)("incident.field.urgency"))
+	})
+}
+
+func TestTranslationsComplete(t *testing.T) {
+	english := messages.LanguageTranslationIDs(DefaultLocale)
+	for _, tag := range messages.LanguageTags() {
+		assert.ElementsMatch(t, english, messages.LanguageTranslationIDs(tag), "translations of %s", tag)
+	}
+}
@@ -1,11 +1,51 @@
 package main
 
+import (
+	"time"
+)
+
+// jobInterval is how often the background job runs
+const jobInterval = 5 * time.Minute
+
 // runJob is called by the cluster scheduler defined in plugin.go.
 // Although this appears unused, it's referenced through a function pointer
 // in the cluster.Schedule call.
-//
-//nolint:unused
 func (p *Plugin) runJob() {
-	// Include job logic here
-	p.API.LogInfo("Job is currently running")
+	if p.getJobPagerDutyClient() == nil {
+		return
+	}
+
+	if err := p.checkMaintenanceWindows(); err != nil {
+		p.API.LogError("Failed to check maintenance windows", "error", err.Error())
+	}
+
+	if err := p.checkExpiredSnoozes(); err != nil {
+		p.API.LogError("Failed to check expired snoozes", "error", err.Error())
+	}
+
+	if p.getConfiguration().DailyDigest {
+		if err := p.checkDailyDigest(); err != nil {
+			p.API.LogError("Failed to check daily digest", "error", err.Error())
+		}
+	}
+
+	if err := p.checkShiftReminders(); err != nil {
+		p.API.LogError("Failed to check shift reminders", "error", err.Error())
+	}
+
+	if p.getConfiguration().PollMissedIncidents {
+		if err := p.checkMissedIncidents(); err != nil {
+			p.API.LogError("Failed to check missed incidents", "error", err.Error())
+		}
+	}
+
+	if err := p.cleanupResolvedIncidents(); err != nil {
+		p.API.LogError("Failed to clean up resolved incidents", "error", err.Error())
+	}
+
+	if p.getConfiguration().ResyncIncidentPosts {
+		if err := p.resyncOpenIncidentPosts(); err != nil {
+			p.API.LogError("Failed to resynchronize incident posts", "error", err.Error())
+		}
+	}
 }
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// checkMaintenanceWindows announces the start and end of maintenance windows covering
+// subscribed services in the subscribed channels
+func (p *Plugin) checkMaintenanceWindows() error {
+	subscriptions, err := p.kvstore.GetSubscriptions()
+	if err != nil {
+		return errors.Wrap(err, "failed to get subscriptions")
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	subscriptionsByService := map[string]*kvstore.Subscription{}
+	params := url.Values{}
+	params.Set("filter", "ongoing")
+	for _, subscription := range subscriptions {
+		subscriptionsByService[subscription.ServiceID] = subscription
+		params.Add("service_ids[]", subscription.ServiceID)
+	}
+
+	windows, err := p.getJobPagerDutyClient().ListMaintenanceWindows(params)
+	if err != nil {
+		return errors.Wrap(err, "failed to list maintenance windows")
+	}
+
+	active, err := p.kvstore.GetActiveMaintenanceWindows()
+	if err != nil {
+		return errors.Wrap(err, "failed to get active maintenance windows")
+	}
+
+	ongoing := map[string]bool{}
+	for _, window := range windows {
+		ongoing[window.ID] = true
+		if _, ok := active[window.ID]; ok {
+			continue
+		}
+
+		p.announceMaintenanceWindow(window, subscriptionsByService, true)
+		active[window.ID] = window
+	}
+
+	for id, window := range active {
+		if ongoing[id] {
+			continue
+		}
+
+		p.announceMaintenanceWindow(window, subscriptionsByService, false)
+		delete(active, id)
+	}
+
+	if err := p.kvstore.SaveActiveMaintenanceWindows(active); err != nil {
+		return errors.Wrap(err, "failed to save active maintenance windows")
+	}
+
+	return nil
+}
+
+// announceMaintenanceWindow posts the start or end of a maintenance window to each channel
+// subscribed to one of its services
+func (p *Plugin) announceMaintenanceWindow(window pagerduty.MaintenanceWindow, subscriptionsByService map[string]*kvstore.Subscription, started bool) {
+	servicesByChannel := map[string][]string{}
+	for _, service := range window.Services {
+		subscription, ok := subscriptionsByService[service.ID]
+		if !ok {
+			continue
+		}
+		servicesByChannel[subscription.ChannelID] = append(servicesByChannel[subscription.ChannelID], subscription.ServiceName)
+	}
+
+	t := p.displayLanguage()
+	for channelID, services := range servicesByChannel {
+		sort.Strings(services)
+		names := strings.Join(services, ", ")
+
+		var message string
+		if started {
+			message = t("maintenance.started", map[string]interface{}{
+				"Services": names,
+				"URL":      window.HTMLURL,
+				"End":      timezone.Format(window.EndTime, p.displayTimezone()),
+			})
+			if window.Description != "" {
+				message += "\n> " + window.Description
+			}
+		} else {
+			message = t("maintenance.ended", map[string]interface{}{"URL": window.HTMLURL, "Services": names})
+		}
+
+		if _, appErr := p.API.CreatePost(&model.Post{
+			UserId:    p.botUserID,
+			ChannelId: channelID,
+			Message:   message,
+		}); appErr != nil {
+			p.API.LogWarn("Failed to announce maintenance window", "window_id", window.ID, "channel_id", channelID, "error", appErr.Error())
+		}
+	}
+}
+
+// getMaintenanceMode returns how to post an incident while its service is under maintenance: the
+// subscription's maintenance mode, or else the configured one. It is normal if the service is not
+// under maintenance.
+func (p *Plugin) getMaintenanceMode(incident pagerduty.Incident) string {
+	mode := p.getConfiguration().MaintenanceMode
+
+	subscription, err := p.kvstore.GetSubscription(incident.Service.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to get subscription", "service_id", incident.Service.ID, "error", err.Error())
+	} else if subscription != nil && subscription.MaintenanceMode != "" {
+		mode = subscription.MaintenanceMode
+	}
+
+	if mode == "" || mode == kvstore.MaintenanceModeNormal {
+		return kvstore.MaintenanceModeNormal
+	}
+
+	if !p.isUnderMaintenance(incident.Service.ID) {
+		return kvstore.MaintenanceModeNormal
+	}
+
+	return mode
+}
+
+// isUnderMaintenance checks if a maintenance window covering a service is ongoing, caching the
+// ongoing windows of the service for a short while
+func (p *Plugin) isUnderMaintenance(serviceID string) bool {
+	windows, err := p.kvstore.GetCachedMaintenanceWindows(serviceID)
+	if err != nil {
+		p.API.LogWarn("Failed to get cached maintenance windows", "service_id", serviceID, "error", err.Error())
+	}
+
+	if windows == nil {
+		params := url.Values{}
+		params.Set("filter", "ongoing")
+		params.Add("service_ids[]", serviceID)
+
+		windows, err = p.getPagerDutyClient().ListMaintenanceWindows(params)
+		if err != nil {
+			p.API.LogWarn("Failed to list maintenance windows", "service_id", serviceID, "error", err.Error())
+			return false
+		}
+
+		if err := p.kvstore.SaveCachedMaintenanceWindows(serviceID, windows); err != nil {
+			p.API.LogWarn("Failed to cache maintenance windows", "service_id", serviceID, "error", err.Error())
+		}
+	}
+
+	// Cached windows may have ended since
+	now := time.Now()
+	for _, window := range windows {
+		if window.StartTime.Before(now) && window.EndTime.After(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getMaintenanceChannelID gets the channel incidents of services under maintenance are posted to
+// in the quiet maintenance mode. It returns false if none is configured or it can't be found.
+func (p *Plugin) getMaintenanceChannelID() (string, bool) {
+	channel := p.getConfiguration().MaintenanceChannel
+	if channel == "" {
+		return "", false
+	}
+
+	channelID, err := p.findChannelID(channel)
+	if err != nil {
+		p.API.LogWarn("Failed to find maintenance channel", "channel", channel, "error", err.Error())
+		return "", false
+	}
+
+	return channelID, true
+}
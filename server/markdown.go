@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// formatIncidentMarkdown renders the attachment of an incident post as a plain markdown message.
+// The status, shown by the attachment's color otherwise, is spelled out.
+func formatIncidentMarkdown(incident pagerduty.Incident, attachment *model.SlackAttachment, t i18n.TranslateFunc) string {
+	var lines []string
+
+	if attachment.Pretext != "" {
+		lines = append(lines, attachment.Pretext, "")
+	}
+
+	lines = append(lines, fmt.Sprintf("#### %s", attachment.Title))
+	if attachment.Text != "" {
+		lines = append(lines, attachment.Text)
+	}
+	lines = append(lines, "", fmt.Sprintf("**%s:** %s", t("incident.field.status"), t("command.list.status."+incident.Status)))
+
+	for _, field := range attachment.Fields {
+		lines = append(lines, fmt.Sprintf("**%s:** %v", field.Title, field.Value))
+	}
+
+	if attachment.Footer != "" {
+		lines = append(lines, "", fmt.Sprintf("_%s_", attachment.Footer))
+	}
+
+	return strings.Join(lines, "\n")
+}
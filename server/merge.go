@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/command"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// resolveReasonMerge is the resolve reason of an incident merged into another incident
+const resolveReasonMerge = "merge_resolve_reason"
+
+// getMergeResolveReason returns the resolve reason of a resolved incident if it was merged into
+// another incident, or nil otherwise. Webhook payloads may leave out the resolve reason, in which
+// case the incident is fetched from PagerDuty.
+func (p *Plugin) getMergeResolveReason(incident pagerduty.Incident) *pagerduty.ResolveReason {
+	reason := incident.ResolveReason
+	if reason == nil {
+		fetched, err := p.getPagerDutyClient().GetIncident(incident.ID)
+		if err != nil {
+			p.API.LogWarn("Failed to get incident", "incident_id", incident.ID, "error", err.Error())
+			return nil
+		}
+		reason = fetched.ResolveReason
+	}
+
+	if reason == nil || reason.Type != resolveReasonMerge {
+		return nil
+	}
+
+	return reason
+}
+
+// handleMergedIncident puts the post of an incident absorbed by a merge in a "Merged into" state,
+// linking to the post of the surviving incident. The post is not updated afterwards.
+func (p *Plugin) handleMergedIncident(incident pagerduty.Incident, attachment *pagerduty.PostAttachment, reason *pagerduty.ResolveReason) error {
+	p.API.LogInfo("Incident merged", "incident_id", incident.ID, "merged_into_id", reason.Incident.ID)
+
+	saved, err := p.updateIncidentAttachment(incident.ID, func(stored *pagerduty.PostAttachment) {
+		stored.Incident = incident
+		stored.MergedIntoID = reason.Incident.ID
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update incident attachment")
+	}
+	if saved != nil {
+		attachment = saved
+	}
+
+	// Suppressed during maintenance, there is no post to update
+	if attachment.Suppressed {
+		return nil
+	}
+
+	post, appErr := p.API.GetPost(attachment.PostID)
+	if appErr != nil {
+		return errors.New("failed to get incident post: " + appErr.Error())
+	}
+
+	options := p.newIncidentPostOptions(incident)
+	options.Maintenance = attachment.Maintenance
+	options.PreviousPostID = attachment.PreviousPostID
+	options.MergedInto = p.getMergedIntoLink(reason.Incident)
+	p.setIncidentPostContent(post, incident, options)
+
+	if _, appErr := p.API.UpdatePost(post); appErr != nil {
+		return errors.New("failed to update post: " + appErr.Error())
+	}
+
+	return nil
+}
+
+// getMergedIntoLink links to the post of the surviving incident of a merge,
+// falling back to the incident in PagerDuty if it was never posted
+func (p *Plugin) getMergedIntoLink(survivor pagerduty.IncidentReference) string {
+	text := survivor.Summary
+	link := survivor.HTMLURL
+
+	attachment, err := p.getIncidentAttachment(survivor.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to get incident attachment", "incident_id", survivor.ID, "error", err.Error())
+	}
+
+	if attachment != nil {
+		text = fmt.Sprintf("#%d", attachment.Incident.IncidentNumber)
+		if attachment.PostID != "" {
+			link = p.getPostPermalink(attachment.PostID)
+		}
+	}
+
+	if text == "" {
+		text = survivor.ID
+	}
+
+	return fmt.Sprintf("[%s](%s)", text, link)
+}
+
+// handleMergeDialog handles the dialog of the merge command, merging the source incidents into the
+// target incident and pointing the posts of the merged incidents at the target incident
+func (p *Plugin) handleMergeDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var state command.MergeState
+	if err := json.Unmarshal([]byte(request.State), &state); err != nil {
+		http.Error(w, "Invalid dialog state", http.StatusBadRequest)
+		return
+	}
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	t := i18n.ForUser(user)
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	target, err := pdClient.MergeIncidents(state.TargetID, state.SourceIDs, user.Email)
+	if err != nil {
+		p.API.LogError("Failed to merge incidents", "incident_id", state.TargetID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.merge.error.merge", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	p.API.LogInfo("Incidents merged from Mattermost", "incident_id", target.ID, "source_ids", state.SourceIDs, "user_id", userID)
+
+	reason := &pagerduty.ResolveReason{
+		Type: resolveReasonMerge,
+		Incident: pagerduty.IncidentReference{
+			ID:      target.ID,
+			Summary: target.Title,
+			HTMLURL: target.HTMLURL,
+		},
+	}
+	for _, sourceID := range state.SourceIDs {
+		if err := p.markIncidentMerged(sourceID, reason); err != nil {
+			p.API.LogWarn("Failed to update merged incident post", "incident_id", sourceID, "error", err.Error())
+		}
+	}
+
+	p.recordIncidentAction(target.ID, "merged", user)
+
+	p.refreshIncidentPost(*target)
+
+	p.API.SendEphemeralPost(userID, &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: request.ChannelId,
+		Message:   t("dialog.merge.merged", map[string]interface{}{"Count": len(state.SourceIDs), "Number": target.IncidentNumber, "URL": target.HTMLURL}),
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// markIncidentMerged puts the post of an incident merged from Mattermost in a "Merged into" state
+// right away, without waiting for its resolved webhook
+func (p *Plugin) markIncidentMerged(incidentID string, reason *pagerduty.ResolveReason) error {
+	unlock, err := p.lockIncident(incidentID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	attachment, err := p.getIncidentAttachment(incidentID)
+	if err != nil {
+		return err
+	}
+	if attachment == nil || attachment.MergedIntoID != "" {
+		return nil
+	}
+
+	incident := attachment.Incident
+	if fetched, err := p.getPagerDutyClient().GetIncident(incidentID); err == nil {
+		incident = *fetched
+	} else {
+		p.API.LogWarn("Failed to get incident", "incident_id", incidentID, "error", err.Error())
+		incident.Status = client.StatusResolved
+	}
+
+	return p.handleMergedIncident(incident, attachment, reason)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// migration is a step that upgrades the stored plugin data to the next schema version
+type migration struct {
+	description string
+	migrate     func(p *Plugin) error
+}
+
+// migrations upgrade the stored plugin data, in order. The schema version of the data is the
+// number of migrations applied to it, so new migrations must be appended and existing ones
+// never reordered or removed.
+var migrations = []migration{
+	{
+		description: "index the open incidents of each channel",
+		migrate:     (*Plugin).indexOpenIncidents,
+	},
+	{
+		description: "track when stored resolved incidents were resolved",
+		migrate:     (*Plugin).trackResolutionTimes,
+	},
+	{
+		description: "encrypt the user API tokens of connected users",
+		migrate:     (*Plugin).encryptUserTokens,
+	},
+}
+
+// migrate applies the migrations the stored plugin data hasn't been through yet. Only one
+// server of a cluster migrates at a time, and each migration is recorded as soon as it
+// succeeds, so a failed migration is retried from where it stopped on the next activation.
+func (p *Plugin) migrate() error {
+	mutex, err := cluster.NewMutex(p.API, "migrations")
+	if err != nil {
+		return errors.Wrap(err, "failed to create migration mutex")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	version, err := p.kvstore.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		p.API.LogWarn("Stored data has a newer schema than this plugin version supports", "schema_version", version, "supported_version", len(migrations))
+		return nil
+	}
+
+	for ; version < len(migrations); version++ {
+		step := migrations[version]
+		p.API.LogInfo("Migrating plugin data", "schema_version", version+1, "migration", step.description)
+
+		if err := step.migrate(p); err != nil {
+			return errors.Wrapf(err, "failed to %s", step.description)
+		}
+		if err := p.kvstore.SaveSchemaVersion(version + 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trackResolutionTimes starts the retention period of incidents stored as resolved before
+// resolution times were tracked
+func (p *Plugin) trackResolutionTimes() error {
+	attachments, err := p.kvstore.GetIncidentAttachments()
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident attachments")
+	}
+
+	for _, attachment := range attachments {
+		if attachment.Incident.Status != client.StatusResolved || !attachment.ResolvedAt.IsZero() {
+			continue
+		}
+
+		// Updating the attachment starts tracking its resolution time
+		if _, err := p.updateIncidentAttachment(attachment.ID, func(*pagerduty.PostAttachment) {}); err != nil {
+			return errors.Wrapf(err, "failed to update incident %s", attachment.ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// migrationStore keeps the schema version, incident attachments and user tokens in memory,
+// recording the saved versions and failing to list user tokens while tokensErr is set
+type migrationStore struct {
+	attachmentStore
+	version   int
+	saved     []int
+	tokens    map[string]string
+	tokensErr error
+}
+
+func (s *migrationStore) GetSchemaVersion() (int, error) {
+	return s.version, nil
+}
+
+func (s *migrationStore) SaveSchemaVersion(version int) error {
+	s.version = version
+	s.saved = append(s.saved, version)
+	return nil
+}
+
+func (s *migrationStore) GetIncidentAttachments() ([]*pagerduty.PostAttachment, error) {
+	attachments := make([]*pagerduty.PostAttachment, 0, len(s.attachments))
+	for _, attachment := range s.attachments {
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+func (s *migrationStore) GetUserTokens() (map[string]string, error) {
+	return s.tokens, s.tokensErr
+}
+
+func (s *migrationStore) GetUserToken(userID string) (string, error) {
+	return s.tokens[userID], nil
+}
+
+func (s *migrationStore) SaveUserToken(userID, token string) error {
+	s.tokens[userID] = token
+	return nil
+}
+
+func TestMigrate(t *testing.T) {
+	key, err := generateEncryptionKey()
+	require.NoError(t, err)
+
+	setup := func(t *testing.T, version int) (*Plugin, *plugintest.API, *migrationStore) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+		store := &migrationStore{
+			attachmentStore: attachmentStore{attachments: map[string]*pagerduty.PostAttachment{}},
+			version:         version,
+			tokens:          map[string]string{},
+		}
+
+		p := &Plugin{kvstore: store}
+		p.SetAPI(api)
+		p.setConfiguration(&configuration{EncryptionKey: key})
+		return p, api, store
+	}
+
+	t.Run("records the version after each migration", func(t *testing.T) {
+		p, _, store := setup(t, 0)
+
+		require.NoError(t, p.migrate())
+		require.Len(t, store.saved, len(migrations))
+		for i, version := range store.saved {
+			assert.Equal(t, i+1, version)
+		}
+	})
+
+	t.Run("only applies the pending migrations", func(t *testing.T) {
+		p, _, store := setup(t, len(migrations)-1)
+
+		require.NoError(t, p.migrate())
+		assert.Equal(t, []int{len(migrations)}, store.saved)
+	})
+
+	t.Run("resumes from a failed migration", func(t *testing.T) {
+		p, _, store := setup(t, 0)
+		store.tokens["user1"] = "u+token"
+		store.tokensErr = errors.New("unavailable")
+
+		err := p.migrate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "encrypt the user API tokens")
+		assert.Equal(t, []int{1, 2}, store.saved)
+
+		store.tokensErr = nil
+		store.saved = nil
+		require.NoError(t, p.migrate())
+		assert.Equal(t, []int{3}, store.saved)
+
+		token, err := p.getUserToken("user1")
+		require.NoError(t, err)
+		assert.Equal(t, "u+token", token)
+	})
+
+	t.Run("leaves data of a newer schema alone", func(t *testing.T) {
+		p, api, store := setup(t, len(migrations)+1)
+
+		require.NoError(t, p.migrate())
+		assert.Empty(t, store.saved)
+		api.AssertCalled(t, "LogWarn", "Stored data has a newer schema than this plugin version supports", "schema_version", len(migrations)+1, "supported_version", len(migrations))
+	})
+}
+
+func TestTrackResolutionTimes(t *testing.T) {
+	resolvedAt := time.Now().Add(-time.Hour)
+	store := &migrationStore{attachmentStore: attachmentStore{attachments: map[string]*pagerduty.PostAttachment{
+		"I1": {ID: "I1", Incident: pagerduty.Incident{ID: "I1", Status: client.StatusResolved}},
+		"I2": {ID: "I2", Incident: pagerduty.Incident{ID: "I2", Status: client.StatusResolved}, ResolvedAt: resolvedAt},
+		"I3": {ID: "I3", Incident: pagerduty.Incident{ID: "I3", Status: client.StatusTriggered}},
+	}}}
+
+	p := &Plugin{kvstore: store}
+	p.SetAPI(&plugintest.API{})
+
+	require.NoError(t, p.trackResolutionTimes())
+	assert.False(t, store.attachments["I1"].ResolvedAt.IsZero(), "resolved incidents start their retention period")
+	assert.Equal(t, resolvedAt, store.attachments["I2"].ResolvedAt, "tracked resolution times are kept")
+	assert.True(t, store.attachments["I3"].ResolvedAt.IsZero(), "open incidents aren't resolved")
+}
+
+func TestEncryptUserTokens(t *testing.T) {
+	key, err := generateEncryptionKey()
+	require.NoError(t, err)
+
+	encrypted, err := encrypt(key, "u+encrypted")
+	require.NoError(t, err)
+
+	store := &migrationStore{tokens: map[string]string{"user1": "u+plain", "user2": encrypted}}
+	p := &Plugin{kvstore: store}
+	p.setConfiguration(&configuration{EncryptionKey: key})
+
+	require.NoError(t, p.encryptUserTokens())
+	assert.NotEqual(t, "u+plain", store.tokens["user1"])
+	assert.Equal(t, encrypted, store.tokens["user2"], "encrypted tokens aren't encrypted again")
+
+	// Running the migration again changes nothing
+	require.NoError(t, p.encryptUserTokens())
+	for userID, want := range map[string]string{"user1": "u+plain", "user2": "u+encrypted"} {
+		token, err := p.getUserToken(userID)
+		require.NoError(t, err)
+		assert.Equal(t, want, token)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	// Name of the note element of the note dialog
+	noteDialogContent = "note"
+
+	// Maximum length of notes added with the note dialog
+	noteDialogMaxLength = 3000
+)
+
+// handleIncidentNote posts a note added to an incident in PagerDuty as a reply in the thread of
+// the incident's post
+func (p *Plugin) handleIncidentNote(event pagerduty.V3Event) error {
+	note := event.Note
+	if note == nil || note.Incident.ID == "" {
+		p.API.LogInfo("Ignoring annotation event without a note", "event_id", event.ID)
+		return nil
+	}
+
+	attachment, err := p.getIncidentAttachment(note.Incident.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident attachment")
+	}
+
+	if attachment == nil || attachment.PostID == "" || attachment.Suppressed {
+		p.API.LogDebug("Ignoring note of an incident without a post", "incident_id", note.Incident.ID)
+		return nil
+	}
+
+	content := note.Content
+	if note.Trimmed {
+		content = p.getFullNoteContent(note)
+	}
+
+	author := event.Agent.Summary
+	if author == "" {
+		author = "someone"
+	}
+
+	return p.postIncidentNote(attachment, note.ID, author, content)
+}
+
+// postIncidentNote posts a note as a reply in the thread of the incident's post, unless it was
+// already posted
+func (p *Plugin) postIncidentNote(attachment *pagerduty.PostAttachment, noteID, author, content string) error {
+	if noteID != "" {
+		posted, err := p.kvstore.MarkNotePosted(noteID)
+		if err != nil {
+			return errors.Wrap(err, "failed to mark note as posted")
+		}
+		if !posted {
+			p.API.LogDebug("Ignoring note already posted", "note_id", noteID)
+			return nil
+		}
+	}
+
+	quoted := "> " + strings.ReplaceAll(strings.TrimSpace(content), "\n", "\n> ")
+	if _, appErr := p.API.CreatePost(&model.Post{
+		UserId:    p.botUserID,
+		ChannelId: attachment.ChannelID,
+		RootId:    attachment.PostID,
+		Message:   p.displayLanguage()("thread.note", map[string]interface{}{"Author": author, "Message": quoted}),
+	}); appErr != nil {
+		return errors.New("failed to post note: " + appErr.Error())
+	}
+
+	return nil
+}
+
+// getFullNoteContent gets the untrimmed content of a long note, falling back to the trimmed content
+func (p *Plugin) getFullNoteContent(note *pagerduty.IncidentNote) string {
+	notes, err := p.getPagerDutyClient().ListIncidentNotes(note.Incident.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to get incident notes", "incident_id", note.Incident.ID, "error", err.Error())
+		return note.Content
+	}
+
+	for _, fullNote := range notes {
+		if fullNote.ID == note.ID {
+			return fullNote.Content
+		}
+	}
+
+	return note.Content
+}
+
+// performOpenNoteDialog opens the dialog to add a note to an incident from its post
+func (p *Plugin) performOpenNoteDialog(w http.ResponseWriter, incidentID, triggerID string, t i18n.TranslateFunc) {
+	if appErr := p.API.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/incidents/notes", pluginID),
+		Dialog: model.Dialog{
+			Title:       t("incident.action.add_note"),
+			SubmitLabel: t("dialog.note.submit"),
+			State:       incidentID,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: t("dialog.note.note"),
+					Name:        noteDialogContent,
+					Type:        "textarea",
+					MaxLength:   noteDialogMaxLength,
+					HelpText:    t("dialog.note.help"),
+				},
+			},
+		},
+	}); appErr != nil {
+		p.API.LogError("Failed to open note dialog", "error", appErr.Error())
+		http.Error(w, "Failed to open dialog", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&model.PostActionIntegrationResponse{}); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
+
+// handleNoteDialog handles the note dialog, adding the note to the incident in PagerDuty and
+// posting it in the incident thread
+func (p *Plugin) handleNoteDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+	t := i18n.ForUser(user)
+
+	incidentID := request.State
+	content, _ := request.Submission[noteDialogContent].(string)
+	if strings.TrimSpace(content) == "" {
+		p.writeDialogError(w, t("dialog.note.error.empty"))
+		return
+	}
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	note, err := pdClient.AddNote(incidentID, content, user.Email)
+	if err != nil {
+		p.API.LogError("Failed to add note", "incident_id", incidentID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.note.error.add", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	attachment, err := p.getIncidentAttachment(incidentID)
+	switch {
+	case err != nil:
+		p.API.LogWarn("Failed to get incident attachment", "incident_id", incidentID, "error", err.Error())
+	case attachment == nil || attachment.PostID == "":
+		p.API.LogDebug("Not posting note of an incident without a post", "incident_id", incidentID)
+	default:
+		if err := p.postIncidentNote(attachment, note.ID, "@"+user.Username, content); err != nil {
+			p.API.LogWarn("Failed to post note", "incident_id", incidentID, "error", err.Error())
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client/mocks"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+func TestGetFullNoteContent(t *testing.T) {
+	note := &pagerduty.IncidentNote{
+		ID:       "N1",
+		Content:  "Trimmed...",
+		Trimmed:  true,
+		Incident: pagerduty.IncidentReference{ID: "I1"},
+	}
+
+	setup := func(t *testing.T) (*Plugin, *mocks.MockPDClient) {
+		ctrl := gomock.NewController(t)
+		pdClient := mocks.NewMockPDClient(ctrl)
+
+		api := &plugintest.API{}
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+		p := &Plugin{pdClient: pdClient}
+		p.SetAPI(api)
+		return p, pdClient
+	}
+
+	t.Run("full content of the note", func(t *testing.T) {
+		p, pdClient := setup(t)
+		pdClient.EXPECT().ListIncidentNotes("I1").Return([]pagerduty.Note{
+			{ID: "N0", Content: "Another note"},
+			{ID: "N1", Content: "Trimmed... and the rest"},
+		}, nil)
+
+		assert.Equal(t, "Trimmed... and the rest", p.getFullNoteContent(note))
+	})
+
+	t.Run("trimmed content when the note is missing", func(t *testing.T) {
+		p, pdClient := setup(t)
+		pdClient.EXPECT().ListIncidentNotes("I1").Return([]pagerduty.Note{{ID: "N0", Content: "Another note"}}, nil)
+
+		assert.Equal(t, "Trimmed...", p.getFullNoteContent(note))
+	})
+
+	t.Run("trimmed content when listing notes fails", func(t *testing.T) {
+		p, pdClient := setup(t)
+		pdClient.EXPECT().ListIncidentNotes("I1").Return(nil, errors.New("unavailable"))
+
+		assert.Equal(t, "Trimmed...", p.getFullNoteContent(note))
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// Notify actions
+	NotifyActionConfirm = "confirm"
+	NotifyActionCancel  = "cancel"
+
+	// Each user may page at most notifyRateLimit people within notifyRateWindow
+	notifyRateLimit  = 3
+	notifyRateWindow = time.Hour
+)
+
+// handleNotifyAction handles the Page/Cancel buttons of a notify confirmation
+func (p *Plugin) handleNotifyAction(w http.ResponseWriter, r *http.Request) {
+	action := mux.Vars(r)["action"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	pdUserID, _ := request.Context["pd_user_id"].(string)
+	username, _ := request.Context["username"].(string)
+	message, _ := request.Context["message"].(string)
+
+	t := p.translate(userID)
+	if action == NotifyActionCancel {
+		p.writeNotifyResponse(w, t("action.notify.canceled", map[string]interface{}{"Username": username}))
+		return
+	}
+
+	if action != NotifyActionConfirm || pdUserID == "" {
+		http.Error(w, "Invalid action", http.StatusBadRequest)
+		return
+	}
+
+	serviceID := p.getConfiguration().NotifyServiceID
+	if serviceID == "" {
+		p.writeNotifyResponse(w, t("action.notify.not_configured"))
+		return
+	}
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeNotifyResponse(w, t("action.error.user_token"))
+		return
+	}
+
+	title := fmt.Sprintf("Page from @%s: %s", user.Username, message)
+	details := fmt.Sprintf("@%s paged @%s from Mattermost:\n\n%s", user.Username, username, message)
+
+	// Take a slot of the rate limit before paging, so concurrent clicks can't exceed it
+	pagedAt := time.Now()
+	reserved, err := p.kvstore.ReserveRecentPage(userID, pagedAt, notifyRateLimit, notifyRateWindow)
+	if err != nil {
+		p.API.LogError("Failed to reserve page", "error", err.Error())
+		http.Error(w, "Failed to check rate limit", http.StatusInternalServerError)
+		return
+	}
+	if !reserved {
+		p.writeNotifyResponse(w, t("action.notify.rate_limited", map[string]interface{}{"Limit": notifyRateLimit}))
+		return
+	}
+
+	incident, err := pdClient.CreateIncident(serviceID, title, details, "high", []string{pdUserID}, user.Email)
+	if err != nil {
+		p.API.LogError("Failed to page user", "error", err.Error(), "pd_user_id", pdUserID)
+		if err := p.kvstore.ReleaseRecentPage(userID, pagedAt, notifyRateWindow); err != nil {
+			p.API.LogWarn("Failed to release page", "error", err.Error())
+		}
+		p.writeNotifyResponse(w, t("action.notify.error", map[string]interface{}{"Username": username, "Error": err.Error()}))
+		return
+	}
+
+	p.API.LogInfo("User paged", "user_id", userID, "pd_user_id", pdUserID, "incident_id", incident.ID)
+
+	p.writeNotifyResponse(w, t("action.notify.paged", map[string]interface{}{"Username": username, "Number": incident.IncidentNumber, "URL": incident.HTMLURL}))
+}
+
+// writeNotifyResponse replaces the notify confirmation with a result message
+func (p *Plugin) writeNotifyResponse(w http.ResponseWriter, message string) {
+	response := &model.PostActionIntegrationResponse{
+		Update: &model.Post{
+			Message: message,
+			Props:   model.StringInterface{},
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"net/url"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// inviteOnCallResponders adds the users currently on call for the incident's escalation
+// policy to the channel the incident was posted in.
+func (p *Plugin) inviteOnCallResponders(incident pagerduty.Incident, channelID string) error {
+	users, err := p.getFirstLevelOnCallUsers(incident)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if _, appErr := p.API.AddChannelMember(channelID, user.Id); appErr != nil {
+			p.API.LogWarn("Failed to add on-call responder to channel", "user_id", user.Id, "channel_id", channelID, "error", appErr.Error())
+			continue
+		}
+
+		p.API.LogDebug("Added on-call responder to channel", "user_id", user.Id, "channel_id", channelID, "incident_id", incident.ID)
+	}
+
+	return nil
+}
+
+// notifyOnCallResponders sends the users currently on call for a high-urgency incident's
+// escalation policy a DM with the incident card, so they can acknowledge it right away.
+// Users in notified already got a DM about the incident and are skipped.
+func (p *Plugin) notifyOnCallResponders(incident pagerduty.Incident, postID string, notified map[string]bool) error {
+	if incident.Urgency != "high" {
+		return nil
+	}
+
+	users, err := p.getFirstLevelOnCallUsers(incident)
+	if err != nil {
+		return err
+	}
+
+	options := p.newIncidentPostOptions(incident)
+	for _, user := range users {
+		if user.IsBot || notified[user.Id] {
+			continue
+		}
+		notified[user.Id] = true
+
+		intro := i18n.ForUser(user)("dm.on_call", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL})
+		if err := p.sendIncidentDM(user, intro, incident, options, postID); err != nil {
+			p.API.LogWarn("Failed to notify on-call responder", "incident_id", incident.ID, "user_id", user.Id, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// getFirstLevelOnCallUsers gets the Mattermost accounts of the users on call for the first
+// level of an incident's escalation policy, who are paged when the incident triggers
+func (p *Plugin) getFirstLevelOnCallUsers(incident pagerduty.Incident) ([]*model.User, error) {
+	if incident.EscalationPolicy.ID == "" {
+		return nil, nil
+	}
+
+	params := url.Values{}
+	params.Add("escalation_policy_ids[]", incident.EscalationPolicy.ID)
+	params.Add("include[]", "users")
+
+	onCalls, err := p.getPagerDutyClient().ListOnCalls(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list on-calls")
+	}
+
+	var users []*model.User
+	found := map[string]bool{}
+	for _, onCall := range onCalls {
+		if onCall.EscalationLevel != 1 || found[onCall.User.ID] {
+			continue
+		}
+		found[onCall.User.ID] = true
+
+		user, err := p.getMattermostUser(onCall.User)
+		if err != nil {
+			p.API.LogWarn("Failed to find Mattermost user for on-call responder", "pd_user_id", onCall.User.ID, "error", err.Error())
+			continue
+		}
+		if user == nil {
+			p.API.LogDebug("No Mattermost user for on-call responder", "pd_user_id", onCall.User.ID)
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
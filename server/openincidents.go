@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// updateOpenIncidentIndex adds an incident to the open incident index of its channel while its
+// post shows it open, and removes it once it is resolved, merged or suppressed
+func (p *Plugin) updateOpenIncidentIndex(attachment *pagerduty.PostAttachment) error {
+	if attachment.ChannelID == "" {
+		return nil
+	}
+
+	open := attachment.Incident.Status != client.StatusResolved && attachment.MergedIntoID == ""
+	if open && !attachment.Suppressed && attachment.PostID != "" {
+		return p.kvstore.AddOpenIncident(attachment.ChannelID, attachment.ID, attachment.PostID)
+	}
+	return p.kvstore.RemoveOpenIncident(attachment.ChannelID, attachment.ID)
+}
+
+// indexOpenIncidents adds the incidents stored before the open incident index existed to it
+func (p *Plugin) indexOpenIncidents() error {
+	attachments, err := p.kvstore.GetIncidentAttachments()
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident attachments")
+	}
+
+	for _, attachment := range attachments {
+		if err := p.updateOpenIncidentIndex(attachment); err != nil {
+			return errors.Wrapf(err, "failed to index incident %s", attachment.ID)
+		}
+	}
+
+	return nil
+}
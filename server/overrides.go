@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/command"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// handleCancelOverride handles the Cancel button on an override listing. Only the user the
+// override puts on call or a system admin may cancel it.
+func (p *Plugin) handleCancelOverride(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	vars := mux.Vars(r)
+	scheduleID := vars["schedule_id"]
+	overrideID := vars["override_id"]
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+	t := i18n.ForUser(user)
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeActionResponse(w, &model.PostActionIntegrationResponse{EphemeralText: t("action.error.user_token")})
+		return
+	}
+
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		owner, err := p.isOverrideOwner(pdClient, user, scheduleID, overrideID)
+		if err != nil {
+			p.API.LogError("Failed to check the owner of override", "override_id", overrideID, "error", err.Error())
+			p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+				EphemeralText: t("action.error.cancel_override", map[string]interface{}{"ID": overrideID, "Error": err.Error()}),
+			})
+			return
+		}
+		if !owner {
+			p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+				EphemeralText: t("command.overrides.error.not_allowed", map[string]interface{}{"ID": overrideID}),
+			})
+			return
+		}
+	}
+
+	message := t("command.overrides.canceled", map[string]interface{}{"ID": overrideID})
+	if err := pdClient.DeleteOverride(scheduleID, overrideID); err != nil {
+		p.API.LogError("Failed to delete override", "error", err.Error())
+		message = t("action.error.cancel_override", map[string]interface{}{"ID": overrideID, "Error": err.Error()})
+	} else {
+		p.API.LogInfo("Override canceled", "schedule_id", scheduleID, "override_id", overrideID, "user_id", userID)
+	}
+
+	p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+		EphemeralText: message,
+	})
+}
+
+// isOverrideOwner tells whether the PagerDuty account of a user is the one an override puts on
+// call, looking among the overrides of the schedule that haven't ended
+func (p *Plugin) isOverrideOwner(pdClient client.PDClient, user *model.User, scheduleID, overrideID string) (bool, error) {
+	pdUser, err := p.getPagerDutyUser(user)
+	if err != nil {
+		return false, err
+	}
+	if pdUser == nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	overrides, err := pdClient.ListOverrides(scheduleID, now, now.Add(command.OverrideCancelLookahead))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get overrides")
+	}
+
+	for _, override := range overrides {
+		if override.ID == overrideID {
+			return override.User.ID == pdUser.ID, nil
+		}
+	}
+
+	return false, nil
+}
+
+// handleOverrideDialog handles the dialog of the override create command, creating the override
+// and confirming it to the user
+func (p *Plugin) handleOverrideDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	scheduleID, _ := request.Submission[command.OverrideDialogSchedule].(string)
+	pdUserID, _ := request.Submission[command.OverrideDialogUser].(string)
+	startDate, _ := request.Submission[command.OverrideDialogStartDate].(string)
+	startTime, _ := request.Submission[command.OverrideDialogStartTime].(string)
+	durationValue, _ := request.Submission[command.OverrideDialogDuration].(string)
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+	loc := timezone.ForUser(user)
+	t := i18n.ForUser(user)
+
+	if scheduleID == "" || pdUserID == "" {
+		p.writeDialogError(w, t("dialog.override.error.missing"))
+		return
+	}
+
+	start, err := time.ParseInLocation(command.OverrideDateLayout+" "+command.OverrideTimeLayout, strings.TrimSpace(startDate)+" "+startTime, loc)
+	if err != nil {
+		p.writeDialogErrors(w, map[string]string{command.OverrideDialogStartDate: t("dialog.override.error.invalid_date")})
+		return
+	}
+
+	duration, err := time.ParseDuration(durationValue)
+	if err != nil || duration <= 0 {
+		p.writeDialogErrors(w, map[string]string{command.OverrideDialogDuration: t("dialog.override.error.duration")})
+		return
+	}
+
+	end := start.Add(duration)
+	if !end.After(time.Now()) {
+		p.writeDialogErrors(w, map[string]string{command.OverrideDialogStartDate: t("dialog.override.error.over")})
+		return
+	}
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	override, err := pdClient.CreateOverride(scheduleID, pdUserID, start, end)
+	if err != nil {
+		p.API.LogError("Failed to create override", "error", err.Error(), "schedule_id", scheduleID)
+		p.writeDialogError(w, t("command.error.create_override", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	p.API.LogInfo("Override created from Mattermost", "schedule_id", scheduleID, "override_id", override.ID, "user_id", userID)
+
+	p.API.SendEphemeralPost(userID, &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: request.ChannelId,
+		Message: t("dialog.override.created", map[string]interface{}{
+			"ID":    override.ID,
+			"User":  override.User.DisplayName(),
+			"Start": timezone.Format(override.Start, loc),
+			"End":   timezone.Format(override.End, loc),
+		}),
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -16,49 +16,97 @@ import (
 	"golang.org/x/text/language"
 
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/pkg/errors"
 
 	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
 	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
 )
 
 const (
+	// ID of the plugin, matching plugin.json
+	pluginID = "com.github.mnzsyu.mattermost-pagerduty-plugin"
+
 	// Action identifiers
-	ActionAcknowledge = "acknowledge"
-	ActionResolve     = "resolve"
-	ActionReassign    = "reassign"
+	ActionAcknowledge  = "acknowledge"
+	ActionResolve      = "resolve"
+	ActionReassign     = "reassign"
+	ActionShowPayload  = "show_payload"
+	ActionSnooze       = "snooze"
+	ActionAddNote      = "add_note"
+	ActionEscalate     = "escalate"
+	ActionSetPriority  = "set_priority"
+	ActionResponders   = "add_responders"
+	ActionStatusUpdate = "status_update"
+	ActionRunWorkflow  = "run_workflow"
 
 	// PagerDuty webhook events
 	EventIncidentTriggered     = "incident.triggered"
 	EventIncidentAcknowledged  = "incident.acknowledged"
 	EventIncidentResolved      = "incident.resolved"
 	EventIncidentReassigned    = "incident.reassigned"
+	EventIncidentReopened      = "incident.reopened"
+	EventIncidentEscalated     = "incident.escalated"
+	EventIncidentDelegated     = "incident.delegated"
 	EventIncidentStatusUpdated = "incident.status_update_published"
 
-	// Constants for KV store keys
-	KeyIncidentAttachments = "incident_attachments:"
-
 	// Maximum number of incidents to fetch
 	MaxIncidents = 25
 )
 
-type PostActionOption struct {
-	Text  string `json:"text"`
-	Value string `json:"value"`
-}
-
 // initializePagerDutyClient initializes the PagerDuty client with the current configuration
 func (p *Plugin) initializePagerDutyClient() error {
 	config := p.getConfiguration()
 	if config.PagerDutyAPIKey == "" {
 		return errors.New("PagerDuty API key not configured")
 	}
-	p.pdClient = client.NewPagerDutyClient(config.PagerDutyAPIKey)
+
+	baseURL, err := config.pagerDutyAPIBaseURL()
+	if err != nil {
+		return err
+	}
+
+	pdClient := client.NewPagerDutyClient(config.PagerDutyAPIKey, baseURL)
+	p.setPagerDutyClients(pdClient, pdClient.WithRetryBudget(client.BackgroundRetryBudget))
 	return nil
 }
 
+// getPagerDutyClient gets the PagerDuty client of the current configuration under lock, since
+// OnConfigurationChange replaces it while commands, webhooks and the background job read it.
+// Its calls give up retrying quickly enough for webhooks, post actions and dialogs.
+func (p *Plugin) getPagerDutyClient() client.PDClient {
+	p.configurationLock.RLock()
+	defer p.configurationLock.RUnlock()
+
+	return p.pdClient
+}
+
+// getJobPagerDutyClient gets the PagerDuty client of the background job, whose calls keep
+// retrying through PagerDuty's rate limits for longer
+func (p *Plugin) getJobPagerDutyClient() client.PDClient {
+	p.configurationLock.RLock()
+	defer p.configurationLock.RUnlock()
+
+	return p.pdJobClient
+}
+
+// setPagerDutyClients replaces the PagerDuty clients under lock
+func (p *Plugin) setPagerDutyClients(pdClient, pdJobClient client.PDClient) {
+	p.configurationLock.Lock()
+	defer p.configurationLock.Unlock()
+
+	p.pdClient = pdClient
+	p.pdJobClient = pdJobClient
+}
+
 // HandleWebhook handles PagerDuty webhook requests - updated for V3 webhooks
 func (p *Plugin) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	// Count every delivery, including those rejected below
+	p.recordWebhookStat(kvstore.WebhookStatReceived)
+
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -84,6 +132,7 @@ func (p *Plugin) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		p.API.LogError("Failed to read webhook body", "error", err.Error())
+		p.recordWebhookStat(kvstore.WebhookStatFailed)
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
@@ -98,17 +147,35 @@ func (p *Plugin) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	var payload pagerduty.V3WebhookPayload
 	if err := json.Unmarshal(body, &payload); err != nil {
 		p.API.LogError("Failed to parse webhook payload", "error", err.Error())
+		p.recordWebhookStat(kvstore.WebhookStatFailed)
 		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
 		return
 	}
 
+	// Skip redeliveries of events that were already processed
+	eventID := payload.Event.ID
+	if eventID != "" {
+		firstDelivery, err := p.kvstore.MarkWebhookEventSeen(eventID)
+		if err != nil {
+			p.API.LogWarn("Failed to deduplicate webhook event", "error", err.Error(), "event_id", eventID)
+		} else if !firstDelivery {
+			p.API.LogDebug("Ignoring duplicate webhook event", "event_id", eventID)
+			p.recordWebhookStat(kvstore.WebhookStatDeduplicated)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
 	// Process the event
 	if err := p.processV3WebhookEvent(payload.Event); err != nil {
-		p.API.LogError("Failed to process webhook event", "error", err.Error(), "event_id", payload.Event.ID)
+		p.API.LogError("Failed to process webhook event", "error", err.Error(), "event_id", eventID)
+		p.recordWebhookStat(kvstore.WebhookStatFailed)
+		p.deadLetterWebhookEvent(eventID, body)
 		http.Error(w, "Failed to process event", http.StatusInternalServerError)
 		return
 	}
 
+	p.recordWebhookStat(kvstore.WebhookStatProcessed)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -162,8 +229,15 @@ func (p *Plugin) processWebhookMessage(message pagerduty.WebhookMessage) error {
 	incident := message.Incident
 	p.API.LogDebug("Processing incident", "id", incident.ID, "title", incident.Title)
 
+	// Servers of a cluster can receive deliveries for the same incident at once
+	unlock, err := p.lockIncident(incident.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Get the appropriate channel ID
-	channelID, err := p.getChannelID()
+	channelID, err := p.getIncidentChannelID(incident)
 	if err != nil {
 		p.API.LogError("Failed to get channel ID", "error", err.Error())
 		return errors.Wrap(err, "failed to get channel ID")
@@ -177,20 +251,110 @@ func (p *Plugin) processWebhookMessage(message pagerduty.WebhookMessage) error {
 		// Continue anyway - we'll create a new post
 	}
 
+	// Assignees of a resolved incident are notified again if it triggers again
+	var previousAssignments []pagerduty.Assignment
+	if attachment != nil && attachment.Incident.Status != client.StatusResolved {
+		previousAssignments = attachment.Incident.Assignments
+	}
+
 	switch message.Event {
-	case EventIncidentTriggered:
-		// Create a new post for triggered incidents
-		return p.handleTriggeredIncident(incident, channelID)
+	case EventIncidentTriggered, EventIncidentReopened:
+		switch {
+		case attachment != nil && attachment.MergedIntoID != "":
+			p.API.LogDebug("Ignoring update for merged incident", "incident_id", incident.ID, "merged_into_id", attachment.MergedIntoID)
+			return nil
+		case attachment != nil && attachment.ReopenedAsID != "":
+			p.API.LogDebug("Ignoring update for reopened incident", "incident_id", incident.ID, "reopened_as_id", attachment.ReopenedAsID)
+			return nil
+		case attachment != nil && message.Event == EventIncidentReopened:
+			// Reopened incidents are un-resolved on their existing post, which gets its action
+			// buttons back, so the thread carries on
+			if err := p.updateIncidentPost(incident, attachment); err != nil {
+				return err
+			}
+			p.postIncidentUpdateReply(message, attachment)
+		case attachment != nil && attachment.PostID != "" && attachment.Incident.Status == client.StatusResolved:
+			// A resolved incident that triggers again gets a fresh post instead of editing the resolved one
+			if err := p.handleReopenedIncident(incident, attachment, channelID); err != nil {
+				return err
+			}
+		case attachment != nil:
+			// Already posted, e.g. by the trigger command
+			if err := p.updateIncidentPost(incident, attachment); err != nil {
+				return err
+			}
+		default:
+			// Create a new post for triggered incidents
+			if err := p.handleTriggeredIncident(incident, channelID); err != nil {
+				return err
+			}
+		}
+
+		if p.getConfiguration().AutoInviteOnCall {
+			if err := p.inviteOnCallResponders(incident, channelID); err != nil {
+				p.API.LogWarn("Failed to invite on-call responders", "incident_id", incident.ID, "error", err.Error())
+			}
+		}
+
+		if message.Event == EventIncidentTriggered {
+			postID := p.getIncidentPostID(incident.ID)
+			notified := p.notifyAssignees(message, previousAssignments, postID)
+			if p.getConfiguration().NotifyOnCallHighUrgency {
+				if err := p.notifyOnCallResponders(incident, postID, notified); err != nil {
+					p.API.LogWarn("Failed to notify on-call responders", "incident_id", incident.ID, "error", err.Error())
+				}
+			}
+			p.notifyTeamMembers(incident, postID, notified)
+		} else {
+			p.notifyAssignees(message, previousAssignments, p.getIncidentPostID(incident.ID))
+		}
+
+		return nil
 
 	case EventIncidentAcknowledged, EventIncidentResolved,
-		EventIncidentReassigned, EventIncidentStatusUpdated:
+		EventIncidentReassigned, EventIncidentEscalated, EventIncidentDelegated, EventIncidentStatusUpdated:
 		// Update existing post if available
 		if attachment != nil {
-			return p.updateIncidentPost(incident, attachment)
+			if attachment.MergedIntoID != "" {
+				p.API.LogDebug("Ignoring update for merged incident", "incident_id", incident.ID, "merged_into_id", attachment.MergedIntoID)
+				return nil
+			}
+			if attachment.ReopenedAsID != "" {
+				p.API.LogDebug("Ignoring update for reopened incident", "incident_id", incident.ID, "reopened_as_id", attachment.ReopenedAsID)
+				return nil
+			}
+
+			if message.Event == EventIncidentResolved {
+				if reason := p.getMergeResolveReason(incident); reason != nil {
+					return p.handleMergedIncident(incident, attachment, reason)
+				}
+			}
+
+			if changesAssignees(message.Event) {
+				attachment.PagingWarnings = p.getPagingWarnings(incident)
+			}
+			if err := p.updateIncidentPost(incident, attachment); err != nil {
+				return err
+			}
+
+			// Delegations hand the incident over to another team, so they are always announced
+			if p.getConfiguration().ThreadedUpdates || message.Event == EventIncidentDelegated {
+				p.postIncidentUpdateReply(message, attachment)
+			}
+			if changesAssignees(message.Event) {
+				p.notifyAssignees(message, previousAssignments, p.getIncidentPostID(incident.ID))
+			}
+			return nil
 		}
 
 		// Create a new post if no existing post is found
-		return p.handleTriggeredIncident(incident, channelID)
+		if err := p.handleTriggeredIncident(incident, channelID); err != nil {
+			return err
+		}
+		if changesAssignees(message.Event) {
+			p.notifyAssignees(message, nil, p.getIncidentPostID(incident.ID))
+		}
+		return nil
 
 	default:
 		// Ignore unhandled event types
@@ -199,6 +363,11 @@ func (p *Plugin) processWebhookMessage(message pagerduty.WebhookMessage) error {
 	}
 }
 
+// changesAssignees tells whether an event assigns an incident to other responders
+func changesAssignees(event string) bool {
+	return event == EventIncidentReassigned || event == EventIncidentEscalated || event == EventIncidentDelegated
+}
+
 // processV3WebhookEvent processes a V3 webhook event
 func (p *Plugin) processV3WebhookEvent(event pagerduty.V3Event) error {
 	p.API.LogDebug("Processing webhook event", "event_type", event.EventType, "resource_type", event.ResourceType)
@@ -209,6 +378,16 @@ func (p *Plugin) processV3WebhookEvent(event pagerduty.V3Event) error {
 		return nil
 	}
 
+	// Notes are posted to the incident's thread rather than updating the incident post
+	if event.EventType == pagerduty.V3EventAnnotated {
+		return p.handleIncidentNote(event)
+	}
+
+	// Responder requests and replies are posted to the incident's thread as well
+	if event.EventType == pagerduty.V3EventResponderAdded || event.EventType == pagerduty.V3EventResponderReplied {
+		return p.handleIncidentResponder(event)
+	}
+
 	// Map V3 event_type to our internal event types
 	var messageEvent string
 	switch event.EventType {
@@ -220,6 +399,12 @@ func (p *Plugin) processV3WebhookEvent(event pagerduty.V3Event) error {
 		messageEvent = EventIncidentResolved
 	case "incident.reassigned":
 		messageEvent = EventIncidentReassigned
+	case "incident.reopened":
+		messageEvent = EventIncidentReopened
+	case "incident.escalated":
+		messageEvent = EventIncidentEscalated
+	case "incident.delegated":
+		messageEvent = EventIncidentDelegated
 	case "incident.status_update_published":
 		messageEvent = EventIncidentStatusUpdated
 	default:
@@ -232,44 +417,108 @@ func (p *Plugin) processV3WebhookEvent(event pagerduty.V3Event) error {
 		ID:       event.ID,
 		Event:    messageEvent,
 		Incident: event.Data,
+		Agent:    &event.Agent,
 	}
 
 	// Process the message
 	return p.processWebhookMessage(message)
 }
 
+// lockIncident locks an incident across the cluster, so only one server at a time creates or
+// updates its post. The returned function releases the lock.
+func (p *Plugin) lockIncident(incidentID string) (func(), error) {
+	mutex, err := cluster.NewMutex(p.API, "incident-"+incidentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create incident mutex")
+	}
+	mutex.Lock()
+
+	return mutex.Unlock, nil
+}
+
 // handleTriggeredIncident creates a new post for a triggered incident
 func (p *Plugin) handleTriggeredIncident(incident pagerduty.Incident, channelID string) error {
+	_, err := p.postIncident(incident, channelID, "")
+	return err
+}
+
+// postIncident creates a new post for an incident, linking back to the post of an earlier
+// occurrence of the incident if previousPostID is set. It returns nil if the post was suppressed.
+func (p *Plugin) postIncident(incident pagerduty.Incident, channelID, previousPostID string) (*pagerduty.PostAttachment, error) {
 	p.API.LogDebug("Handling triggered incident", "id", incident.ID, "title", incident.Title)
 
-	post := p.createIncidentPost(incident, channelID)
+	maintenanceMode := p.getMaintenanceMode(incident)
+	if maintenanceMode == kvstore.MaintenanceModeSuppress {
+		p.API.LogInfo("Suppressing incident post for service under maintenance", "incident_id", incident.ID, "service_id", incident.Service.ID)
+
+		// Remember the incident so later updates are suppressed too
+		attachment := &pagerduty.PostAttachment{
+			ID:         incident.ID,
+			ChannelID:  channelID,
+			Incident:   incident,
+			Suppressed: true,
+		}
+		if err := p.storeIncidentAttachment(attachment); err != nil {
+			return nil, errors.Wrap(err, "failed to store incident attachment")
+		}
+		return nil, nil
+	}
+
+	if maintenanceMode == kvstore.MaintenanceModeQuiet {
+		if quietChannelID, ok := p.getMaintenanceChannelID(); ok {
+			channelID = quietChannelID
+		} else {
+			p.API.LogWarn("No maintenance channel to post to, posting incident muted instead", "incident_id", incident.ID)
+		}
+	}
+
+	options := p.newIncidentPostOptions(incident)
+	options.Maintenance = maintenanceMode == kvstore.MaintenanceModeMute || maintenanceMode == kvstore.MaintenanceModeQuiet
+	options.PagingWarnings = p.getPagingWarnings(incident)
+	options.PreviousPostID = previousPostID
+
+	post := p.createIncidentPost(incident, channelID, options)
 	p.API.LogDebug("Created post for incident", "userId", post.UserId, "channelId", post.ChannelId)
 
 	createdPost, appErr := p.API.CreatePost(post)
 	if appErr != nil {
 		p.API.LogError("Failed to create post", "error", appErr.Error())
-		return errors.New("failed to create post: " + appErr.Error())
+		return nil, errors.New("failed to create post: " + appErr.Error())
 	}
 
 	p.API.LogInfo("Successfully posted incident to channel", "incident_id", incident.ID, "channel_id", channelID)
 
 	// Store the post ID for later updates
 	attachment := &pagerduty.PostAttachment{
-		ID:        incident.ID,
-		PostID:    createdPost.Id,
-		ChannelID: channelID,
-		Incident:  incident,
+		ID:             incident.ID,
+		PostID:         createdPost.Id,
+		ChannelID:      channelID,
+		Incident:       incident,
+		Maintenance:    options.Maintenance,
+		PagingWarnings: options.PagingWarnings,
+		PreviousPostID: previousPostID,
 	}
 
 	if err := p.storeIncidentAttachment(attachment); err != nil {
-		return errors.Wrap(err, "failed to store incident attachment")
+		return nil, errors.Wrap(err, "failed to store incident attachment")
 	}
 
-	return nil
+	if p.getConfiguration().AttachRawPayload {
+		if err := p.attachRawPayload(incident, createdPost); err != nil {
+			p.API.LogWarn("Failed to attach raw alert payload", "incident_id", incident.ID, "error", err.Error())
+		}
+	}
+
+	return attachment, nil
 }
 
 // updateIncidentPost updates an existing post with new incident information
 func (p *Plugin) updateIncidentPost(incident pagerduty.Incident, attachment *pagerduty.PostAttachment) error {
+	// Keep incidents suppressed during maintenance out of the channel
+	if attachment.Suppressed {
+		return p.saveIncidentState(incident, attachment)
+	}
+
 	// Get the existing post
 	post, appErr := p.API.GetPost(attachment.PostID)
 	if appErr != nil {
@@ -278,7 +527,7 @@ func (p *Plugin) updateIncidentPost(incident pagerduty.Incident, attachment *pag
 	}
 
 	// Update the post with new information
-	post.Props = p.createIncidentProps(incident)
+	p.renderIncidentPost(post, incident, attachment)
 
 	// Update the post
 	_, appErr = p.API.UpdatePost(post)
@@ -287,18 +536,87 @@ func (p *Plugin) updateIncidentPost(incident pagerduty.Incident, attachment *pag
 	}
 
 	// Update the stored attachment with the latest incident info
-	attachment.Incident = incident
-	if err := p.storeIncidentAttachment(attachment); err != nil {
+	return p.saveIncidentState(incident, attachment)
+}
+
+// saveIncidentState stores the latest state of an incident, along with the paging warnings
+// shown on its post, in the incident's attachment
+func (p *Plugin) saveIncidentState(incident pagerduty.Incident, attachment *pagerduty.PostAttachment) error {
+	saved, err := p.updateIncidentAttachment(incident.ID, func(stored *pagerduty.PostAttachment) {
+		stored.Incident = incident
+		stored.PagingWarnings = attachment.PagingWarnings
+	})
+	if err != nil {
 		return errors.Wrap(err, "failed to update incident attachment")
 	}
+	if saved != nil {
+		*attachment = *saved
+	}
 
 	return nil
 }
 
-// createIncidentPost creates a Mattermost post for an incident
-func (p *Plugin) createIncidentPost(incident pagerduty.Incident, channelID string) *model.Post {
-	props := p.createIncidentProps(incident)
+// renderIncidentPost sets the content of an existing incident post from the incident's latest state
+func (p *Plugin) renderIncidentPost(post *model.Post, incident pagerduty.Incident, attachment *pagerduty.PostAttachment) {
+	options := p.newIncidentPostOptions(incident)
+	options.Maintenance = attachment.Maintenance
+	options.PagingWarnings = attachment.PagingWarnings
+	options.PreviousPostID = attachment.PreviousPostID
+	options.Attributions = attachment.Attributions
+	p.setIncidentPostContent(post, incident, options)
+}
+
+// incidentPostOptions controls how an incident post is rendered
+type incidentPostOptions struct {
+	// Label is prefixed to the post title to tell incident streams apart
+	Label string
+
+	// Fields are the fields to show, or the default fields if empty
+	Fields []string
+
+	// ServiceInfo is shown while the incident is open for responders unfamiliar with the service
+	ServiceInfo *pagerduty.Service
+
+	// AlertDetails are the formatted details of the incident's first alert
+	AlertDetails string
 
+	// Maintenance renders the post in a muted style for services under maintenance
+	Maintenance bool
+
+	// PagingWarnings are shown while the incident is open to flag assignees that may not be paged
+	PagingWarnings []string
+
+	// PreviousPostID links a reopened incident back to the post of its earlier occurrence
+	PreviousPostID string
+
+	// SupersededByPostID marks the post of an earlier occurrence of a reopened incident
+	SupersededByPostID string
+
+	// MergedInto is a markdown link to the incident this incident was merged into
+	MergedInto string
+
+	// Attributions show who acknowledged or resolved the incident from Mattermost
+	Attributions []pagerduty.ActionAttribution
+}
+
+// showField checks whether a field is shown on the post
+func (o incidentPostOptions) showField(field string) bool {
+	fields := o.Fields
+	if len(fields) == 0 {
+		fields = kvstore.DefaultIncidentFields
+	}
+
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createIncidentPost creates a Mattermost post for an incident
+func (p *Plugin) createIncidentPost(incident pagerduty.Incident, channelID string, options incidentPostOptions) *model.Post {
 	// Create the post
 	userID := p.botUserID
 	if userID == "" {
@@ -306,58 +624,159 @@ func (p *Plugin) createIncidentPost(incident pagerduty.Incident, channelID strin
 		userID = "system"
 	}
 
-	return &model.Post{
+	post := &model.Post{
 		UserId:    userID,
 		ChannelId: channelID,
-		Props:     props,
+	}
+	p.setIncidentPostContent(post, incident, options)
+
+	return post
+}
+
+// setIncidentPostContent renders an incident into a post, as an interactive attachment or,
+// in markdown-only mode, as a plain markdown message
+func (p *Plugin) setIncidentPostContent(post *model.Post, incident pagerduty.Incident, options incidentPostOptions) {
+	attachment := p.createIncidentAttachment(incident, options)
+
+	if p.getConfiguration().MarkdownOnly {
+		post.Message = formatIncidentMarkdown(incident, attachment, p.displayLanguage())
+		post.Props = model.StringInterface{
+			"from_webhook": "true",
+		}
+		return
+	}
+
+	post.Message = ""
+	post.Props = model.StringInterface{
+		"attachments":  []*model.SlackAttachment{attachment},
+		"from_webhook": "true",
 	}
 }
 
-// createIncidentProps creates the props for an incident post
-func (p *Plugin) createIncidentProps(incident pagerduty.Incident) model.StringInterface {
+// createIncidentAttachment creates the message attachment of an incident post
+func (p *Plugin) createIncidentAttachment(incident pagerduty.Incident, options incidentPostOptions) *model.SlackAttachment {
+	t := p.displayLanguage()
+
 	// Format the attachments for the post
 	var fields []*model.SlackAttachmentField
 
 	// Add incident details as fields
-	fields = append(fields, &model.SlackAttachmentField{
-		Title: "Service",
-		Value: incident.Service.Name,
-		Short: true,
-	})
+	if options.showField(kvstore.IncidentFieldService) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.service"),
+			Value: incident.Service.Name,
+			Short: true,
+		})
+	}
 
-	fields = append(fields, &model.SlackAttachmentField{
-		Title: "Urgency",
-		Value: cases.Title(language.English).String(incident.Urgency),
-		Short: true,
-	})
+	if options.showField(kvstore.IncidentFieldUrgency) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.urgency"),
+			Value: cases.Title(language.English).String(incident.Urgency),
+			Short: true,
+		})
+	}
+
+	if incident.Priority != nil && options.showField(kvstore.IncidentFieldPriority) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.priority"),
+			Value: incident.Priority.Name,
+			Short: true,
+		})
+	}
 
 	// Add assignees
 	var assignees []string
 	for _, assignment := range incident.Assignments {
-		assignees = append(assignees, assignment.Assignee.Name)
+		assignees = append(assignees, p.formatPagerDutyUser(assignment.Assignee))
 	}
 
-	if len(assignees) > 0 {
+	if len(assignees) > 0 && options.showField(kvstore.IncidentFieldAssignees) {
 		fields = append(fields, &model.SlackAttachmentField{
-			Title: "Assigned To",
+			Title: t("incident.field.assignees"),
 			Value: strings.Join(assignees, ", "),
 			Short: true,
 		})
 	}
 
+	if incident.EscalationPolicy.Name != "" && options.showField(kvstore.IncidentFieldPolicy) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.escalation_policy"),
+			Value: formatEscalationPolicy(incident.EscalationPolicy),
+			Short: true,
+		})
+	}
+
+	// Introduce the service to responders unfamiliar with it
+	if about := formatServiceAbout(options.ServiceInfo, t); about != "" && incident.Status != client.StatusResolved && options.showField(kvstore.IncidentFieldAbout) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.about"),
+			Value: about,
+			Short: false,
+		})
+	}
+
+	// Warn about assignees that may not be paged
+	if len(options.PagingWarnings) > 0 && incident.Status != client.StatusResolved && options.showField(kvstore.IncidentFieldWarnings) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.warnings"),
+			Value: strings.Join(options.PagingWarnings, "\n"),
+			Short: false,
+		})
+	}
+
 	// Add created time
-	fields = append(fields, &model.SlackAttachmentField{
-		Title: "Created",
-		Value: incident.CreatedAt.Format(time.RFC3339),
-		Short: true,
-	})
+	if options.showField(kvstore.IncidentFieldCreated) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.created"),
+			Value: timezone.Format(incident.CreatedAt, p.displayTimezone()),
+			Short: true,
+		})
+	}
+
+	// Show who acted on the incident from Mattermost
+	if len(options.Attributions) > 0 {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.activity"),
+			Value: formatAttributions(options.Attributions, p.displayTimezone()),
+			Short: false,
+		})
+	}
 
 	// Add incident URL
-	fields = append(fields, &model.SlackAttachmentField{
-		Title: "Link",
-		Value: fmt.Sprintf("[View in PagerDuty](%s)", incident.HTMLURL),
-		Short: false,
-	})
+	if options.showField(kvstore.IncidentFieldLink) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.link"),
+			Value: fmt.Sprintf("[%s](%s)", t("incident.link.view"), incident.HTMLURL),
+			Short: false,
+		})
+	}
+
+	// Add alert details
+	if options.AlertDetails != "" && options.showField(kvstore.IncidentFieldDetails) {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: t("incident.field.details"),
+			Value: options.AlertDetails,
+			Short: false,
+		})
+	}
+
+	// Add the fields defined by admins, skipping those rendered empty
+	templates := p.getIncidentTemplates()
+	for _, field := range templates.fields {
+		value, err := renderIncidentTemplate(field.template, incident)
+		if err != nil {
+			p.API.LogWarn("Failed to render incident field", "incident_id", incident.ID, "error", err.Error())
+			continue
+		}
+		if value != "" {
+			fields = append(fields, &model.SlackAttachmentField{
+				Title: field.title,
+				Value: value,
+				Short: len(value) <= shortFieldLength,
+			})
+		}
+	}
 
 	// Determine color based on status and urgency
 	color := "#FFA500" // Default: orange
@@ -374,34 +793,77 @@ func (p *Plugin) createIncidentProps(incident pagerduty.Incident) model.StringIn
 		color = "#008000" // Green for resolved
 	}
 
+	title := fmt.Sprintf("[#%d] %s", incident.IncidentNumber, incident.Title)
+	if templates.title != nil {
+		if rendered, err := renderIncidentTemplate(templates.title, incident); err != nil {
+			p.API.LogWarn("Failed to render incident title", "incident_id", incident.ID, "error", err.Error())
+		} else if rendered != "" {
+			title = rendered
+		}
+	}
+
+	text := incident.Description
+	if templates.body != nil {
+		if rendered, err := renderIncidentTemplate(templates.body, incident); err != nil {
+			p.API.LogWarn("Failed to render incident body", "incident_id", incident.ID, "error", err.Error())
+		} else {
+			text = rendered
+		}
+	}
+
+	if options.Label != "" {
+		title = options.Label + " " + title
+	}
+
+	// Mute posts for services under maintenance
+	if options.Maintenance {
+		title = ":construction: " + title
+		color = "#A9A9A9" // Gray for maintenance
+	}
+
 	// Create the message attachment
 	attachment := &model.SlackAttachment{
-		Title:   fmt.Sprintf("[#%d] %s", incident.IncidentNumber, incident.Title),
-		Text:    incident.Description,
+		Title:   title,
+		Text:    text,
 		Color:   color,
 		Fields:  fields,
-		Actions: p.getIncidentActions(incident),
+		Actions: p.getIncidentActions(incident, t),
 	}
 
-	// Create post props
-	return model.StringInterface{
-		"attachments":  []*model.SlackAttachment{attachment},
-		"from_webhook": "true",
+	if options.Maintenance {
+		attachment.Footer = t("incident.footer.maintenance")
+	}
+
+	if options.PreviousPostID != "" {
+		attachment.Pretext = t("incident.pretext.reopened", map[string]interface{}{"URL": p.getPostPermalink(options.PreviousPostID)})
 	}
+
+	// A superseded post no longer tracks the incident, so it offers no actions
+	if options.SupersededByPostID != "" {
+		attachment.Pretext = t("incident.pretext.superseded", map[string]interface{}{"URL": p.getPostPermalink(options.SupersededByPostID)})
+		attachment.Color = "#A9A9A9"
+		attachment.Actions = nil
+	}
+
+	// A merged incident is tracked by the incident it was merged into, so it offers no actions
+	if options.MergedInto != "" {
+		attachment.Pretext = t("incident.pretext.merged", map[string]interface{}{"Incident": options.MergedInto})
+		attachment.Color = "#A9A9A9"
+		attachment.Actions = nil
+	}
+
+	return attachment
 }
 
-// getIncidentActions returns the available actions for an incident
-func (p *Plugin) getIncidentActions(incident pagerduty.Incident) []*model.PostAction {
+// getIncidentActions returns the available actions for an incident, named in the display language
+func (p *Plugin) getIncidentActions(incident pagerduty.Incident, t i18n.TranslateFunc) []*model.PostAction {
 	var actions []*model.PostAction
 
-	// Plugin ID constant
-	pluginID := "com.github.mnzsyu.mattermost-pagerduty-plugin"
-
 	// Only show acknowledge button for triggered incidents
 	if incident.Status == client.StatusTriggered {
 		actions = append(actions, &model.PostAction{
 			Id:    ActionAcknowledge,
-			Name:  "Acknowledge",
+			Name:  t("incident.action.acknowledge"),
 			Type:  "button",
 			Style: "primary",
 			Integration: &model.PostActionIntegration{
@@ -418,7 +880,7 @@ func (p *Plugin) getIncidentActions(incident pagerduty.Incident) []*model.PostAc
 	if incident.Status != client.StatusResolved {
 		actions = append(actions, &model.PostAction{
 			Id:    ActionResolve,
-			Name:  "Resolve",
+			Name:  t("incident.action.resolve"),
 			Type:  "button",
 			Style: "success",
 			Integration: &model.PostActionIntegration{
@@ -431,43 +893,157 @@ func (p *Plugin) getIncidentActions(incident pagerduty.Incident) []*model.PostAc
 		})
 	}
 
-	// Add reassign button for all incidents
+	// Show escalate button for open incidents
+	if incident.Status == client.StatusTriggered || incident.Status == client.StatusAcknowledged {
+		actions = append(actions, &model.PostAction{
+			Id:   ActionEscalate,
+			Name: t("incident.action.escalate"),
+			Type: "button",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/escalate", pluginID, incident.ID),
+				Context: map[string]interface{}{
+					"incident_id": incident.ID,
+					"action":      ActionEscalate,
+				},
+			},
+		})
+	}
+
+	// Offer snoozing acknowledged incidents
+	if incident.Status == client.StatusAcknowledged {
+		actions = append(actions, &model.PostAction{
+			Id:   ActionSnooze,
+			Name: t("incident.action.snooze"),
+			Type: "select",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/snooze", pluginID, incident.ID),
+				Context: map[string]interface{}{
+					"incident_id": incident.ID,
+					"action":      ActionSnooze,
+				},
+			},
+			Options: snoozeOptions(t),
+		})
+	}
+
+	// Offer reassigning open incidents to the users on call for them
+	if incident.Status != client.StatusResolved {
+		actions = append(actions, &model.PostAction{
+			Id:   ActionReassign,
+			Name: t("incident.action.reassign"),
+			Type: "select",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/reassign", pluginID, incident.ID),
+				Context: map[string]interface{}{
+					"incident_id": incident.ID,
+					"action":      ActionReassign,
+				},
+			},
+			Options: p.getReassignOptions(incident, t),
+		})
+	}
+
+	// Offer setting the priority of open incidents, if the account uses priorities
+	if incident.Status != client.StatusResolved {
+		if priorityOptions := p.getPriorityOptions(incident); len(priorityOptions) > 0 {
+			actions = append(actions, &model.PostAction{
+				Id:   ActionSetPriority,
+				Name: t("incident.action.set_priority"),
+				Type: "select",
+				Integration: &model.PostActionIntegration{
+					URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/priority", pluginID, incident.ID),
+					Context: map[string]interface{}{
+						"incident_id": incident.ID,
+						"action":      ActionSetPriority,
+					},
+				},
+				Options: priorityOptions,
+			})
+		}
+	}
+
+	// Offer running the incident workflows of the account on open incidents
+	if incident.Status != client.StatusResolved {
+		if workflowOptions := p.getWorkflowOptions(incident); len(workflowOptions) > 0 {
+			actions = append(actions, &model.PostAction{
+				Id:   ActionRunWorkflow,
+				Name: t("incident.action.run_workflow"),
+				Type: "select",
+				Integration: &model.PostActionIntegration{
+					URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/workflow", pluginID, incident.ID),
+					Context: map[string]interface{}{
+						"incident_id": incident.ID,
+						"action":      ActionRunWorkflow,
+					},
+				},
+				Options: workflowOptions,
+			})
+		}
+	}
+
+	// Offer paging additional help for open incidents
+	if incident.Status != client.StatusResolved {
+		actions = append(actions, &model.PostAction{
+			Id:   ActionResponders,
+			Name: t("incident.action.add_responders"),
+			Type: "button",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/responders", pluginID, incident.ID),
+				Context: map[string]interface{}{
+					"incident_id": incident.ID,
+					"action":      ActionResponders,
+				},
+			},
+		})
+	}
+
+	// Offer publishing status updates to the stakeholders of open incidents
+	if incident.Status != client.StatusResolved {
+		actions = append(actions, &model.PostAction{
+			Id:   ActionStatusUpdate,
+			Name: t("incident.action.status_update"),
+			Type: "button",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/status-update", pluginID, incident.ID),
+				Context: map[string]interface{}{
+					"incident_id": incident.ID,
+					"action":      ActionStatusUpdate,
+				},
+			},
+		})
+	}
+
+	// Add note button for all incidents
+	actions = append(actions, &model.PostAction{
+		Id:   ActionAddNote,
+		Name: t("incident.action.add_note"),
+		Type: "button",
+		Integration: &model.PostActionIntegration{
+			URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/note", pluginID, incident.ID),
+			Context: map[string]interface{}{
+				"incident_id": incident.ID,
+				"action":      ActionAddNote,
+			},
+		},
+	})
+
+	// Add show raw payload button for all incidents
 	actions = append(actions, &model.PostAction{
-		Id:   ActionReassign,
-		Name: "Reassign",
-		Type: "select",
+		Id:   ActionShowPayload,
+		Name: t("incident.action.show_payload"),
+		Type: "button",
 		Integration: &model.PostActionIntegration{
-			URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/reassign", pluginID, incident.ID),
+			URL: fmt.Sprintf("/plugins/%s/api/v1/incidents/%s/payload", pluginID, incident.ID),
 			Context: map[string]interface{}{
 				"incident_id": incident.ID,
-				"action":      ActionReassign,
+				"action":      ActionShowPayload,
 			},
 		},
-		// Use a dynamic JSON approach for options
-		DataSource: "custom",
-		Options:    []*model.PostActionOptions{}, // Empty options, will be filled by server response
 	})
 
 	return actions
 }
 
-// getUserOptions gets user options for the reassign dropdown
-// func (p *Plugin) getUserOptions() []*PostActionOption {
-// 	// This would normally fetch users from PagerDuty
-// 	// For now, returning a placeholder with an option to implement more later
-// 	return []*PostActionOption{
-// 		{
-// 			Text:  "Select User...",
-// 			Value: "",
-// 		},
-// 		{
-// 			Text:  "Get on-call users...",
-// 			Value: "fetch_users",
-// 		},
-// 	}
-// }
-
-// getChannelID gets the channel ID for posting alerts
 // getChannelID gets the channel ID for posting alerts
 func (p *Plugin) getChannelID() (string, error) {
 	config := p.getConfiguration()
@@ -479,6 +1055,15 @@ func (p *Plugin) getChannelID() (string, error) {
 		return "", errors.New("default channel not configured")
 	}
 
+	return p.findChannelID(channelValue)
+}
+
+// findChannelID finds a channel by ID, or by name or display name in any team
+func (p *Plugin) findChannelID(channelValue string) (string, error) {
+	if channelID := p.getCachedChannelID(channelValue); channelID != "" {
+		return channelID, nil
+	}
+
 	// Try to find the channel directly by ID first
 	channel, appErr := p.API.GetChannel(channelValue)
 	if appErr == nil {
@@ -486,7 +1071,17 @@ func (p *Plugin) getChannelID() (string, error) {
 		return channel.Id, nil
 	}
 
-	// If not found by ID, we need to search through all teams
+	channelID, err := p.searchChannelID(channelValue)
+	if err != nil {
+		return "", err
+	}
+
+	p.cacheChannelID(channelValue, channelID)
+	return channelID, nil
+}
+
+// searchChannelID searches the channels of all teams for a channel by name or display name
+func (p *Plugin) searchChannelID(channelValue string) (string, error) {
 	teams, appErr := p.API.GetTeams()
 	if appErr != nil {
 		return "", errors.Wrap(appErr, "failed to get teams")
@@ -497,7 +1092,7 @@ func (p *Plugin) getChannelID() (string, error) {
 		p.API.LogDebug("Searching for channel in team", "team_name", team.Name, "team_id", team.Id)
 
 		// Try exact match on channel name first
-		channel, appErr = p.API.GetChannelByName(team.Id, channelValue, false)
+		channel, appErr := p.API.GetChannelByName(team.Id, channelValue, false)
 		if appErr == nil {
 			p.API.LogDebug("Found channel by name in team", "channel_id", channel.Id, "team_id", team.Id)
 			return channel.Id, nil
@@ -523,41 +1118,64 @@ func (p *Plugin) getChannelID() (string, error) {
 	return "", errors.New("channel not found in any team: " + channelValue)
 }
 
-// storeIncidentAttachment stores the incident attachment in the KV store
-func (p *Plugin) storeIncidentAttachment(attachment *pagerduty.PostAttachment) error {
-	jsonData, err := json.Marshal(attachment)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal attachment")
+// getPostPermalink returns a permalink to a post
+func (p *Plugin) getPostPermalink(postID string) string {
+	siteURL := ""
+	if config := p.API.GetConfig(); config != nil && config.ServiceSettings.SiteURL != nil {
+		siteURL = strings.TrimSuffix(*config.ServiceSettings.SiteURL, "/")
 	}
 
-	key := KeyIncidentAttachments + attachment.ID
-	appErr := p.API.KVSet(key, jsonData)
-	if appErr != nil {
-		return errors.New("failed to store attachment in KV store: " + appErr.Error())
+	return fmt.Sprintf("%s/_redirect/pl/%s", siteURL, postID)
+}
+
+// storeIncidentAttachment stores the attachment of a newly posted incident in the KV store,
+// replacing any earlier attachment. Changes to a stored attachment use updateIncidentAttachment.
+func (p *Plugin) storeIncidentAttachment(attachment *pagerduty.PostAttachment) error {
+	trackResolution(attachment)
+
+	if err := p.kvstore.SaveIncidentAttachment(attachment); err != nil {
+		return err
 	}
 
+	if err := p.updateOpenIncidentIndex(attachment); err != nil {
+		p.API.LogWarn("Failed to update open incident index", "incident_id", attachment.ID, "error", err.Error())
+	}
 	return nil
 }
 
-// getIncidentAttachment gets the incident attachment from the KV store
-func (p *Plugin) getIncidentAttachment(incidentID string) (*pagerduty.PostAttachment, error) {
-	key := KeyIncidentAttachments + incidentID
-
-	data, appErr := p.API.KVGet(key)
-	if appErr != nil {
-		return nil, errors.New("failed to get attachment from KV store: " + appErr.Error())
+// updateIncidentAttachment atomically applies a change to the stored attachment of an incident,
+// so concurrent webhook deliveries for the same incident don't overwrite each other's changes.
+// The change may be applied more than once. It returns the saved attachment, or nil if the
+// incident has no attachment.
+func (p *Plugin) updateIncidentAttachment(incidentID string, change func(*pagerduty.PostAttachment)) (*pagerduty.PostAttachment, error) {
+	attachment, err := p.kvstore.UpdateIncidentAttachment(incidentID, func(attachment *pagerduty.PostAttachment) {
+		change(attachment)
+		trackResolution(attachment)
+	})
+	if err != nil || attachment == nil {
+		return nil, err
 	}
 
-	if data == nil {
-		return nil, nil
+	if err := p.updateOpenIncidentIndex(attachment); err != nil {
+		p.API.LogWarn("Failed to update open incident index", "incident_id", attachment.ID, "error", err.Error())
 	}
+	return attachment, nil
+}
 
-	var attachment pagerduty.PostAttachment
-	if err := json.Unmarshal(data, &attachment); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal attachment")
+// trackResolution records when an incident was resolved so it can be forgotten after the
+// retention period
+func trackResolution(attachment *pagerduty.PostAttachment) {
+	switch {
+	case attachment.Incident.Status != client.StatusResolved:
+		attachment.ResolvedAt = time.Time{}
+	case attachment.ResolvedAt.IsZero():
+		attachment.ResolvedAt = time.Now()
 	}
+}
 
-	return &attachment, nil
+// getIncidentAttachment gets the incident attachment from the KV store
+func (p *Plugin) getIncidentAttachment(incidentID string) (*pagerduty.PostAttachment, error) {
+	return p.kvstore.GetIncidentAttachment(incidentID)
 }
 
 // HandleIncidentAction handles incident action button clicks
@@ -582,16 +1200,58 @@ func (p *Plugin) HandleIncidentAction(w http.ResponseWriter, r *http.Request, in
 		http.Error(w, "Failed to get user", http.StatusInternalServerError)
 		return
 	}
+	t := i18n.ForUser(user)
+
+	// Act as the user if they connected their PagerDuty account
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+			EphemeralText: t("action.error.user_token"),
+		})
+		return
+	}
 
 	var status string
 	switch action {
 	case ActionAcknowledge:
 		status = client.StatusAcknowledged
 	case ActionResolve:
+		if p.getConfiguration().ConfirmResolve {
+			p.performOpenResolveDialog(w, incidentID, payload.TriggerID, t)
+			return
+		}
 		status = client.StatusResolved
 	case ActionReassign:
 		// Handle reassignment separately
-		p.performReassign(w, incidentID, payload.AssigneeID, user.Email)
+		assigneeID := payload.Context.SelectedOption
+		if assigneeID == "" {
+			assigneeID = payload.AssigneeID
+		}
+		p.performReassign(w, pdClient, incidentID, assigneeID, user)
+		return
+	case ActionShowPayload:
+		p.performShowPayload(w, incidentID, t)
+		return
+	case ActionEscalate:
+		p.performEscalate(w, pdClient, incidentID, user)
+		return
+	case ActionSetPriority:
+		p.performSetPriority(w, pdClient, incidentID, payload.Context.SelectedOption, user)
+		return
+	case ActionRunWorkflow:
+		p.performRunWorkflow(w, pdClient, incidentID, payload.Context.SelectedOption, user)
+		return
+	case ActionAddNote:
+		p.performOpenNoteDialog(w, incidentID, payload.TriggerID, t)
+		return
+	case ActionResponders:
+		p.performOpenRespondersDialog(w, incidentID, payload.TriggerID, t)
+		return
+	case ActionStatusUpdate:
+		p.performOpenStatusUpdateDialog(w, incidentID, payload.TriggerID, t)
+		return
+	case ActionSnooze:
+		p.performSnooze(w, pdClient, incidentID, payload.Context.SelectedOption, user)
 		return
 	default:
 		http.Error(w, "Invalid action", http.StatusBadRequest)
@@ -599,84 +1259,100 @@ func (p *Plugin) HandleIncidentAction(w http.ResponseWriter, r *http.Request, in
 	}
 
 	// Update the incident in PagerDuty
-	_, err := p.pdClient.UpdateIncident(incidentID, status, user.Email, "")
+	incident, err := pdClient.UpdateIncident(incidentID, status, user.Email, "")
 	if err != nil {
 		p.API.LogError("Failed to update incident", "error", err.Error())
-		http.Error(w, "Failed to update incident", http.StatusInternalServerError)
+		p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+			EphemeralText: t("action.error.update_incident", map[string]interface{}{"Error": err.Error()}),
+		})
 		return
 	}
 
-	// Return success
+	p.recordIncidentAction(incidentID, status, user)
+
+	// Show the new status right away instead of waiting for the webhook. The post is updated
+	// under the incident's lock rather than through the action response.
+	p.refreshIncidentPost(*incident)
+	p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+		EphemeralText: t("action.incident."+status, map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL}),
+	})
+}
+
+// writeActionResponse responds to a post action, with an ephemeral message for the user who
+// clicked it and optionally an update of the post
+func (p *Plugin) writeActionResponse(w http.ResponseWriter, response *model.PostActionIntegrationResponse) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(map[string]string{"status": "success"}); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		p.API.LogError("Failed to encode JSON response", "error", err.Error())
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
 	}
 }
 
-// performReassign handles reassigning an incident
-func (p *Plugin) performReassign(w http.ResponseWriter, incidentID, assigneeID, userEmail string) {
-	if assigneeID == "fetch_users" {
-		// Return a list of users
-		users, err := p.pdClient.ListUsers()
-		if err != nil {
-			p.API.LogError("Failed to list users", "error", err.Error())
-			http.Error(w, "Failed to list users", http.StatusInternalServerError)
-			return
-		}
+// refreshIncidentPost updates the post of an incident with its latest state and stores the
+// incident, under the incident's lock so it can't overwrite a concurrent webhook update. A state
+// older than the stored one, like an acknowledgement overtaken by a resolve, is left out.
+func (p *Plugin) refreshIncidentPost(incident pagerduty.Incident) {
+	unlock, err := p.lockIncident(incident.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to lock incident", "incident_id", incident.ID, "error", err.Error())
+		return
+	}
+	defer unlock()
 
-		var options []*PostActionOption
-		for _, user := range users {
-			options = append(options, &PostActionOption{
-				Text:  user.Name,
-				Value: user.ID,
-			})
-		}
+	attachment, err := p.getIncidentAttachment(incident.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to get incident attachment", "incident_id", incident.ID, "error", err.Error())
+		return
+	}
+	if attachment == nil || attachment.PostID == "" || attachment.Suppressed {
+		return
+	}
+	if attachment.Incident.LastStatusChangeAt.After(incident.LastStatusChangeAt) {
+		p.API.LogDebug("Ignoring stale incident state", "incident_id", incident.ID)
+		return
+	}
 
-		response := map[string]interface{}{
-			"update": map[string]interface{}{
-				"props": map[string]interface{}{
-					"attachments": []map[string]interface{}{
-						{
-							"actions": []map[string]interface{}{
-								{
-									"id":      ActionReassign,
-									"name":    "Reassign",
-									"type":    "select",
-									"options": options,
-								},
-							},
-						},
-					},
-				},
-			},
-		}
+	post, appErr := p.API.GetPost(attachment.PostID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to get incident post", "incident_id", incident.ID, "error", appErr.Error())
+		return
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			p.API.LogError("Failed to encode JSON response", "error", err.Error())
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
+	p.renderIncidentPost(post, incident, attachment)
+
+	if _, appErr := p.API.UpdatePost(post); appErr != nil {
+		p.API.LogWarn("Failed to update incident post", "incident_id", incident.ID, "error", appErr.Error())
+		return
+	}
+
+	if err := p.saveIncidentState(incident, attachment); err != nil {
+		p.API.LogWarn("Failed to update incident attachment", "incident_id", incident.ID, "error", err.Error())
+	}
+}
+
+// performReassign handles reassigning an incident
+func (p *Plugin) performReassign(w http.ResponseWriter, pdClient client.PDClient, incidentID, assigneeID string, user *model.User) {
+	if assigneeID == "" {
+		http.Error(w, "Missing assignee", http.StatusBadRequest)
 		return
 	}
 
 	// Assign the incident
-	_, err := p.pdClient.AssignIncident(incidentID, []string{assigneeID}, userEmail)
+	incident, err := pdClient.AssignIncident(incidentID, []string{assigneeID}, user.Email)
 	if err != nil {
 		p.API.LogError("Failed to assign incident", "error", err.Error())
-		http.Error(w, "Failed to assign incident", http.StatusInternalServerError)
+		p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+			EphemeralText: i18n.ForUser(user)("action.error.reassign_incident", map[string]interface{}{"Error": err.Error()}),
+		})
 		return
 	}
 
-	// Return success
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(map[string]string{"status": "success"}); err != nil {
-		p.API.LogError("Failed to encode JSON response", "error", err.Error())
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+	var assignees []string
+	for _, assignment := range incident.Assignments {
+		assignees = append(assignees, p.formatPagerDutyUser(assignment.Assignee))
 	}
+
+	p.refreshIncidentPost(*incident)
+	p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+		EphemeralText: i18n.ForUser(user)("action.incident.reassigned", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL, "Assignees": strings.Join(assignees, ", ")}),
+	})
 }
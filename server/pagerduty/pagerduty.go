@@ -2,6 +2,7 @@
 package pagerduty
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -13,6 +14,7 @@ type Incident struct {
 	Description        string           `json:"description"`
 	Status             string           `json:"status"`
 	Urgency            string           `json:"urgency"`
+	Priority           *Priority        `json:"priority,omitempty"`
 	CreatedAt          time.Time        `json:"created_at"`
 	Service            Service          `json:"service"`
 	Assignments        []Assignment     `json:"assignments"`
@@ -21,19 +23,225 @@ type Incident struct {
 	AlertCount         int              `json:"alert_count,omitempty"`
 	HTMLURL            string           `json:"html_url"`
 	EscalationPolicy   EscalationPolicy `json:"escalation_policy"`
+	ResolveReason      *ResolveReason   `json:"resolve_reason,omitempty"`
 }
 
-// EscalationPolicy represents a PagerDuty escalation policy
-type EscalationPolicy struct {
+// ResolveReason represents why a PagerDuty incident was resolved
+type ResolveReason struct {
+	Type     string            `json:"type"` // merge_resolve_reason when merged into another incident
+	Incident IncidentReference `json:"incident"`
+}
+
+// IncidentReference represents a reference to a PagerDuty incident
+type IncidentReference struct {
 	ID      string `json:"id"`
-	Name    string `json:"summary"`
+	Summary string `json:"summary"`
 	HTMLURL string `json:"html_url"`
 }
 
+// Priority represents a PagerDuty incident priority
+type Priority struct {
+	ID    string `json:"id"`
+	Name  string `json:"summary"`
+	Color string `json:"color,omitempty"`
+}
+
+// EscalationPolicy represents a PagerDuty escalation policy. Its rules are only set when the
+// policy is fetched itself, not when it is referenced by an incident or service.
+type EscalationPolicy struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"summary"`
+	HTMLURL         string           `json:"html_url"`
+	EscalationRules []EscalationRule `json:"escalation_rules,omitempty"`
+	NumLoops        int              `json:"num_loops,omitempty"`
+}
+
+// EscalationRule represents a level of an escalation policy
+type EscalationRule struct {
+	ID                       string             `json:"id"`
+	EscalationDelayInMinutes int                `json:"escalation_delay_in_minutes"`
+	Targets                  []EscalationTarget `json:"targets"`
+}
+
+// EscalationTarget represents a user or schedule paged at a level of an escalation policy
+type EscalationTarget struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"` // user_reference or schedule_reference
+	Summary string `json:"summary"`
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
+// Escalation target types
+const (
+	EscalationTargetUser     = "user_reference"
+	EscalationTargetSchedule = "schedule_reference"
+)
+
+// Schedule represents a PagerDuty schedule
+type Schedule struct {
+	ID       string `json:"id"`
+	Name     string `json:"summary"`
+	HTMLURL  string `json:"html_url"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Teams    []Team `json:"teams,omitempty"`
+
+	// FinalSchedule is the schedule with overrides applied, rendered when getting a schedule
+	// for a time range
+	FinalSchedule *RenderedSchedule `json:"final_schedule,omitempty"`
+}
+
+// RenderedSchedule represents the shifts of a schedule over a time range
+type RenderedSchedule struct {
+	Entries []ScheduleEntry `json:"rendered_schedule_entries"`
+}
+
+// ScheduleEntry represents a shift of a rendered schedule
+type ScheduleEntry struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	User  User      `json:"user"`
+}
+
+// Override represents a PagerDuty schedule override
+type Override struct {
+	ID    string    `json:"id,omitempty"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	User  User      `json:"user"`
+}
+
+// OnCall represents a PagerDuty on-call entry
+type OnCall struct {
+	EscalationPolicy EscalationPolicy `json:"escalation_policy"`
+	EscalationLevel  int              `json:"escalation_level"`
+	Schedule         *Schedule        `json:"schedule,omitempty"`
+	User             User             `json:"user"`
+	Start            *time.Time       `json:"start,omitempty"`
+	End              *time.Time       `json:"end,omitempty"`
+}
+
+// NextEscalationLevel returns the escalation level after the incident's current one, given the
+// on-calls of its escalation policy. PagerDuty doesn't report the current level of an incident,
+// so it is the highest level any assignee is on call for, or the first level if none is. It
+// returns false if the incident is already at the last level.
+func (i Incident) NextEscalationLevel(onCalls []OnCall) (int, bool) {
+	assignees := map[string]bool{}
+	for _, assignment := range i.Assignments {
+		assignees[assignment.Assignee.ID] = true
+	}
+
+	current, last := 1, 0
+	for _, onCall := range onCalls {
+		if onCall.EscalationPolicy.ID != i.EscalationPolicy.ID {
+			continue
+		}
+		if onCall.EscalationLevel > last {
+			last = onCall.EscalationLevel
+		}
+		if assignees[onCall.User.ID] && onCall.EscalationLevel > current {
+			current = onCall.EscalationLevel
+		}
+	}
+
+	if current >= last {
+		return 0, false
+	}
+	return current + 1, true
+}
+
 // Service represents a PagerDuty service
 type Service struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Summary          string            `json:"summary,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	HTMLURL          string            `json:"html_url,omitempty"`
+	Teams            []Team            `json:"teams,omitempty"`
+	EscalationPolicy *EscalationPolicy `json:"escalation_policy,omitempty"`
+}
+
+// DisplayName returns the service's name, falling back to the summary of a service reference
+func (s Service) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Summary
+}
+
+// Tag represents a PagerDuty tag
+type Tag struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// MaintenanceWindow represents a PagerDuty maintenance window
+type MaintenanceWindow struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Services    []Service `json:"services"`
+	HTMLURL     string    `json:"html_url"`
+}
+
+// Alert represents a PagerDuty alert of an incident
+type Alert struct {
+	ID       string     `json:"id"`
+	Summary  string     `json:"summary"`
+	Severity string     `json:"severity"`
+	Status   string     `json:"status"`
+	Body     *AlertBody `json:"body,omitempty"`
+}
+
+// AlertBody represents the body of a PagerDuty alert
+type AlertBody struct {
+	Type       string        `json:"type,omitempty"`
+	Contexts   []interface{} `json:"contexts,omitempty"`
+	Details    interface{}   `json:"details,omitempty"` // An object for Events API v2 custom details, or a string
+	CEFDetails interface{}   `json:"cef_details,omitempty"`
+}
+
+// Note represents a note added to a PagerDuty incident
+type Note struct {
+	ID        string    `json:"id"`
+	User      User      `json:"user"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ResponderRequest represents a request for additional responders to help with an incident
+type ResponderRequest struct {
+	Incident    IncidentReference        `json:"incident"`
+	Requester   User                     `json:"requester"`
+	RequestedAt time.Time                `json:"requested_at"`
+	Message     string                   `json:"message"`
+	Targets     []ResponderRequestTarget `json:"responder_request_targets"`
+}
+
+// ResponderRequestTarget wraps a user or escalation policy asked to respond to an incident
+type ResponderRequestTarget struct {
+	Target ResponderTarget `json:"responder_request_target"`
+}
+
+// ResponderTarget represents a user or escalation policy asked to respond to an incident
+type ResponderTarget struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"` // user_reference or escalation_policy_reference
+	Summary string `json:"summary,omitempty"`
+}
+
+// Responder target types
+const (
+	ResponderTargetUser             = "user_reference"
+	ResponderTargetEscalationPolicy = "escalation_policy_reference"
+)
+
+// StatusUpdate represents a status update sent to the stakeholders of a PagerDuty incident
+type StatusUpdate struct {
+	ID        string    `json:"id"`
+	Sender    User      `json:"sender"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // Assignment represents a PagerDuty incident assignment
@@ -44,9 +252,69 @@ type Assignment struct {
 
 // User represents a PagerDuty user
 type User struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email,omitempty"`
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	Summary           string             `json:"summary,omitempty"`
+	Type              string             `json:"type,omitempty"` // user or user_reference, as references may also be to services
+	Email             string             `json:"email,omitempty"`
+	Role              string             `json:"role,omitempty"`
+	HTMLURL           string             `json:"html_url,omitempty"`
+	Teams             []Team             `json:"teams,omitempty"`
+	ContactMethods    []ContactMethod    `json:"contact_methods,omitempty"`
+	NotificationRules []NotificationRule `json:"notification_rules,omitempty"`
+}
+
+// DisplayName returns the user's name, falling back to the summary of a user reference
+func (u User) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.Summary
+}
+
+// IncidentWorkflow represents a PagerDuty incident workflow, a sequence of automated actions
+// run on an incident
+type IncidentWorkflow struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	HTMLURL     string `json:"html_url,omitempty"`
+}
+
+// IncidentWorkflowInstance represents a run of an incident workflow on an incident
+type IncidentWorkflowInstance struct {
+	ID       string            `json:"id"`
+	Incident IncidentReference `json:"incident"`
+}
+
+// Team represents a PagerDuty team
+type Team struct {
+	ID          string `json:"id"`
+	Name        string `json:"summary"`
+	Description string `json:"description,omitempty"`
+	HTMLURL     string `json:"html_url,omitempty"`
+}
+
+// TeamMember represents a member of a PagerDuty team
+type TeamMember struct {
+	User User   `json:"user"`
+	Role string `json:"role"`
+}
+
+// NotificationRule represents a PagerDuty user notification rule
+type NotificationRule struct {
+	ID                  string        `json:"id"`
+	StartDelayInMinutes int           `json:"start_delay_in_minutes"`
+	Urgency             string        `json:"urgency"` // high, low, or any
+	ContactMethod       ContactMethod `json:"contact_method"`
+}
+
+// ContactMethod represents a PagerDuty user contact method
+type ContactMethod struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Label   string `json:"label,omitempty"`
+	Address string `json:"address,omitempty"`
 }
 
 // WebhookPayload represents the payload from PagerDuty webhook
@@ -67,8 +335,74 @@ type V3Event struct {
 	OccurredAt   string      `json:"occurred_at"`
 	Agent        V3Reference `json:"agent"`
 	Data         Incident    `json:"data"`
+
+	// Note is the data of incident.annotated events, which carry a note instead of an incident
+	Note *IncidentNote `json:"-"`
+
+	// Responder is the data of responder events, which carry a responder instead of an incident
+	Responder *IncidentResponder `json:"-"`
+}
+
+// Types of V3 events whose data is not an incident
+const (
+	V3EventAnnotated        = "incident.annotated"
+	V3EventResponderAdded   = "incident.responder.added"
+	V3EventResponderReplied = "incident.responder.replied"
+)
+
+// UnmarshalJSON decodes the event's data as a note for annotation events, or as an incident
+func (e *V3Event) UnmarshalJSON(data []byte) error {
+	type event V3Event
+	var raw struct {
+		event
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*e = V3Event(raw.event)
+	if len(raw.Data) == 0 {
+		return nil
+	}
+
+	switch e.EventType {
+	case V3EventAnnotated:
+		e.Note = &IncidentNote{}
+		return json.Unmarshal(raw.Data, e.Note)
+	case V3EventResponderAdded, V3EventResponderReplied:
+		e.Responder = &IncidentResponder{}
+		return json.Unmarshal(raw.Data, e.Responder)
+	}
+
+	return json.Unmarshal(raw.Data, &e.Data)
 }
 
+// IncidentNote represents the note of an incident.annotated V3 webhook event
+type IncidentNote struct {
+	ID       string            `json:"id"`
+	Content  string            `json:"content"`
+	Trimmed  bool              `json:"trimmed"`
+	Incident IncidentReference `json:"incident"`
+}
+
+// IncidentResponder represents the responder of incident.responder.added and
+// incident.responder.replied V3 webhook events
+type IncidentResponder struct {
+	Incident         IncidentReference `json:"incident"`
+	User             V3Reference       `json:"user"`
+	EscalationPolicy *V3Reference      `json:"escalation_policy"`
+	Message          string            `json:"message"`
+	State            string            `json:"state"`
+}
+
+// States of incident responders
+const (
+	ResponderStatePending  = "pending"
+	ResponderStateJoined   = "joined"
+	ResponderStateDeclined = "declined"
+)
+
 // V3Reference represents a PagerDuty V3 reference object
 type V3Reference struct {
 	HTMLURL string `json:"html_url"`
@@ -86,6 +420,9 @@ type WebhookMessage struct {
 	Incident   Incident               `json:"incident"`
 	LogEntries []LogEntry             `json:"log_entries,omitempty"`
 	Data       map[string]interface{} `json:"data,omitempty"`
+
+	// Agent is who or what caused the event, if known
+	Agent *V3Reference `json:"agent,omitempty"`
 }
 
 // LogEntry represents a PagerDuty log entry
@@ -105,10 +442,39 @@ type Channel struct {
 
 // PostAttachment is used to create Mattermost post attachments for incidents
 type PostAttachment struct {
-	ID        string   `json:"id"`
-	PostID    string   `json:"post_id"`
-	ChannelID string   `json:"channel_id"`
-	Incident  Incident `json:"incident"`
+	ID          string   `json:"id"`
+	PostID      string   `json:"post_id"`
+	ChannelID   string   `json:"channel_id"`
+	Incident    Incident `json:"incident"`
+	Maintenance bool     `json:"maintenance,omitempty"` // Posted while the service was under maintenance
+	Suppressed  bool     `json:"suppressed,omitempty"`  // Not posted because the service was under maintenance
+
+	// PagingWarnings lists reasons the assignees may not be reached by PagerDuty
+	PagingWarnings []string `json:"paging_warnings,omitempty"`
+
+	// PreviousPostID is the post of an earlier occurrence of a reopened incident
+	PreviousPostID string `json:"previous_post_id,omitempty"`
+
+	// MergedIntoID is the incident this incident was merged into. Merged incidents are no longer updated.
+	MergedIntoID string `json:"merged_into_id,omitempty"`
+
+	// ReopenedAsID is the incident triggered to reopen this resolved incident, which took over its
+	// post. The post is no longer updated for this incident.
+	ReopenedAsID string `json:"reopened_as_id,omitempty"`
+
+	// Attributions record who acknowledged or resolved the incident from Mattermost
+	Attributions []ActionAttribution `json:"attributions,omitempty"`
+
+	// ResolvedAt is when the incident was first stored as resolved, for the retention policy
+	ResolvedAt time.Time `json:"resolved_at,omitempty"`
+}
+
+// ActionAttribution records a Mattermost user changing the status of an incident
+type ActionAttribution struct {
+	Status   string    `json:"status"`
+	UserID   string    `json:"user_id"`
+	Username string    `json:"username"`
+	At       time.Time `json:"at"`
 }
 
 // IncidentActionPayload is the payload sent for incident actions
@@ -117,6 +483,63 @@ type IncidentActionPayload struct {
 	Action     string `json:"action"` // acknowledge, resolve, reassign
 	UserID     string `json:"user_id"`
 	AssigneeID string `json:"assignee_id,omitempty"` // Only used for reassign
+	TriggerID  string `json:"trigger_id,omitempty"`  // Used to open dialogs
+	PostID     string `json:"post_id,omitempty"`     // Post of the clicked button
+
+	// Context is the context of the post action, with the option picked in a select
+	Context struct {
+		SelectedOption string `json:"selected_option"`
+	} `json:"context"`
+}
+
+// AlertEvent represents an event sent to the PagerDuty Events API v2
+type AlertEvent struct {
+	RoutingKey  string             `json:"routing_key"`
+	EventAction string             `json:"event_action"` // trigger, acknowledge, resolve
+	DedupKey    string             `json:"dedup_key,omitempty"`
+	Payload     *AlertEventPayload `json:"payload,omitempty"`
+	Client      string             `json:"client,omitempty"`
+	ClientURL   string             `json:"client_url,omitempty"`
+}
+
+// AlertEventPayload is the payload of a triggered Events API v2 event
+type AlertEventPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"` // critical, error, warning, info
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// AlertEventResponse is the response from the PagerDuty Events API v2
+type AlertEventResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	DedupKey string `json:"dedup_key"`
+}
+
+// ChangeEvent represents a change event sent to the PagerDuty Change Events API
+type ChangeEvent struct {
+	RoutingKey string             `json:"routing_key"`
+	Payload    ChangeEventPayload `json:"payload"`
+	Links      []ChangeEventLink  `json:"links,omitempty"`
+}
+
+// ChangeEventPayload is the payload of a change event
+type ChangeEventPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source,omitempty"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// ChangeEventLink is a link shown with a change event
+type ChangeEventLink struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
 }
 
 // APIResponse is a generic response from PagerDuty API
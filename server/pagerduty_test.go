@@ -0,0 +1,206 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client/mocks"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// webhookStore keeps incident attachments, seen events, dead letters and statistics in memory
+// for the tests
+type webhookStore struct {
+	attachmentStore
+	seen        map[string]bool
+	deadLetters map[string][]byte
+	stats       map[string]int
+}
+
+func (s *webhookStore) GetSubscription(serviceID string) (*kvstore.Subscription, error) {
+	return &kvstore.Subscription{ServiceID: serviceID, ChannelID: "channel1"}, nil
+}
+
+func (s *webhookStore) MarkWebhookEventSeen(eventID string) (bool, error) {
+	if s.seen[eventID] {
+		return false, nil
+	}
+	s.seen[eventID] = true
+	return true, nil
+}
+
+func (s *webhookStore) UnmarkWebhookEventSeen(eventID string) error {
+	delete(s.seen, eventID)
+	return nil
+}
+
+func (s *webhookStore) SaveWebhookDeadLetter(eventID string, payload []byte) error {
+	s.deadLetters[eventID] = payload
+	return nil
+}
+
+func (s *webhookStore) IncrementWebhookStat(at time.Time, counter string) error {
+	s.stats[counter]++
+	return nil
+}
+
+func TestHandleWebhook(t *testing.T) {
+	triggered := &pagerduty.PostAttachment{
+		ID:        "I1",
+		PostID:    "post1",
+		ChannelID: "channel1",
+		Incident: pagerduty.Incident{
+			ID:             "I1",
+			IncidentNumber: 1,
+			Title:          "API is down",
+			Status:         client.StatusTriggered,
+			Service:        pagerduty.Service{ID: "S1"},
+		},
+	}
+	acknowledged := `{"event": {
+		"id": "E1",
+		"event_type": "incident.acknowledged",
+		"resource_type": "incident",
+		"data": {"id": "I1", "number": 1, "title": "API is down", "status": "acknowledged", "service": {"id": "S1"}}
+	}}`
+
+	setup := func(t *testing.T, attachments ...*pagerduty.PostAttachment) (*Plugin, *plugintest.API, *webhookStore) {
+		ctrl := gomock.NewController(t)
+		pdClient := mocks.NewMockPDClient(ctrl)
+		pdClient.EXPECT().ListIncidentAlerts(gomock.Any()).Return(nil, nil).AnyTimes()
+
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		for _, args := range [][]interface{}{{mock.Anything}, {mock.Anything, mock.Anything, mock.Anything}, {mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}, {mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}} {
+			api.On("LogDebug", args...).Maybe()
+			api.On("LogInfo", args...).Maybe()
+			api.On("LogWarn", args...).Maybe()
+			api.On("LogError", args...).Maybe()
+		}
+
+		store := &webhookStore{
+			attachmentStore: attachmentStore{attachments: map[string]*pagerduty.PostAttachment{}},
+			seen:            map[string]bool{},
+			deadLetters:     map[string][]byte{},
+			stats:           map[string]int{},
+		}
+		for _, attachment := range attachments {
+			copied := *attachment
+			store.attachments[attachment.ID] = &copied
+		}
+
+		p := &Plugin{pdClient: pdClient, kvstore: store}
+		p.SetAPI(api)
+		return p, api, store
+	}
+
+	deliver := func(p *Plugin, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		p.HandleWebhook(w, r)
+		return w
+	}
+
+	t.Run("updates the post of an incident", func(t *testing.T) {
+		p, api, store := setup(t, triggered)
+		api.On("GetPost", "post1").Return(&model.Post{Id: "post1", ChannelId: "channel1"}, nil)
+		api.On("UpdatePost", mock.Anything).Return(&model.Post{}, nil).Once()
+
+		w := deliver(p, acknowledged)
+		assert.Equal(t, http.StatusOK, w.Code)
+		api.AssertExpectations(t)
+
+		assert.Equal(t, client.StatusAcknowledged, store.attachments["I1"].Incident.Status)
+		assert.Equal(t, 1, store.stats[kvstore.WebhookStatReceived])
+		assert.Equal(t, 1, store.stats[kvstore.WebhookStatProcessed])
+	})
+
+	t.Run("ignores redeliveries", func(t *testing.T) {
+		p, api, store := setup(t, triggered)
+		store.seen["E1"] = true
+
+		w := deliver(p, acknowledged)
+		assert.Equal(t, http.StatusOK, w.Code)
+		api.AssertNotCalled(t, "UpdatePost", mock.Anything)
+
+		assert.Equal(t, client.StatusTriggered, store.attachments["I1"].Incident.Status)
+		assert.Equal(t, 1, store.stats[kvstore.WebhookStatDeduplicated])
+	})
+
+	t.Run("dead-letters events that fail to process", func(t *testing.T) {
+		p, api, store := setup(t, triggered)
+		api.On("GetPost", "post1").Return(&model.Post{Id: "post1", ChannelId: "channel1"}, nil)
+		api.On("UpdatePost", mock.Anything).Return(nil, &model.AppError{Message: "unavailable"})
+
+		w := deliver(p, acknowledged)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		assert.Contains(t, store.deadLetters, "E1")
+		assert.False(t, store.seen["E1"], "a redelivery is processed again")
+		assert.Equal(t, 1, store.stats[kvstore.WebhookStatFailed])
+	})
+
+	t.Run("rejects invalid payloads", func(t *testing.T) {
+		p, _, store := setup(t)
+
+		w := deliver(p, "{")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 1, store.stats[kvstore.WebhookStatFailed])
+	})
+
+	t.Run("ignores events of other resources", func(t *testing.T) {
+		p, _, store := setup(t)
+
+		w := deliver(p, `{"event": {"id": "E2", "event_type": "service.updated", "resource_type": "service"}}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, store.stats[kvstore.WebhookStatProcessed])
+	})
+}
+
+func TestProcessWebhookMessage(t *testing.T) {
+	setup := func(t *testing.T, attachment *pagerduty.PostAttachment) (*Plugin, *plugintest.API) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+		store := &webhookStore{attachmentStore: attachmentStore{attachments: map[string]*pagerduty.PostAttachment{attachment.ID: attachment}}}
+
+		p := &Plugin{pdClient: mocks.NewMockPDClient(gomock.NewController(t)), kvstore: store}
+		p.SetAPI(api)
+		return p, api
+	}
+
+	resolved := pagerduty.WebhookMessage{
+		Event:    EventIncidentResolved,
+		Incident: pagerduty.Incident{ID: "I1", Status: client.StatusResolved, Service: pagerduty.Service{ID: "S1"}},
+	}
+
+	t.Run("ignores updates of merged incidents", func(t *testing.T) {
+		p, api := setup(t, &pagerduty.PostAttachment{ID: "I1", PostID: "post1", MergedIntoID: "I2"})
+
+		require.NoError(t, p.processWebhookMessage(resolved))
+		api.AssertNotCalled(t, "GetPost", mock.Anything)
+		api.AssertNotCalled(t, "UpdatePost", mock.Anything)
+	})
+
+	t.Run("ignores updates of reopened incidents", func(t *testing.T) {
+		p, api := setup(t, &pagerduty.PostAttachment{ID: "I1", PostID: "post1", ReopenedAsID: "I2"})
+
+		require.NoError(t, p.processWebhookMessage(resolved))
+		api.AssertNotCalled(t, "GetPost", mock.Anything)
+		api.AssertNotCalled(t, "UpdatePost", mock.Anything)
+	})
+}
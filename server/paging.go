@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// pagingDelayWarningMinutes is the first-notification delay above which an assignee is
+// considered unlikely to be paged in time
+const pagingDelayWarningMinutes = 15
+
+// getPagingWarnings checks the assignees' contact methods and notification rules and
+// returns a warning for each assignee PagerDuty is unlikely to reach in time
+func (p *Plugin) getPagingWarnings(incident pagerduty.Incident) []string {
+	if !p.getConfiguration().WarnUnreachableAssignees {
+		return nil
+	}
+
+	t := p.displayLanguage()
+	var warnings []string
+	for _, assignment := range incident.Assignments {
+		user, err := p.getPagerDutyUserByID(assignment.Assignee.ID)
+		if err != nil {
+			p.API.LogWarn("Failed to get assignee", "pd_user_id", assignment.Assignee.ID, "error", err.Error())
+			continue
+		}
+
+		if warning := pagingWarning(user, incident.Urgency, t); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings
+}
+
+// pagingWarning returns why a user is unlikely to be paged for an incident of the given
+// urgency, or an empty string if their setup looks fine
+func pagingWarning(user *pagerduty.User, urgency string, t i18n.TranslateFunc) string {
+	if len(user.ContactMethods) == 0 {
+		return t("incident.warning.no_contact_methods", map[string]interface{}{"User": user.DisplayName()})
+	}
+
+	firstDelay := -1
+	for _, rule := range user.NotificationRules {
+		if rule.Urgency != urgency && rule.Urgency != "any" {
+			continue
+		}
+		if firstDelay == -1 || rule.StartDelayInMinutes < firstDelay {
+			firstDelay = rule.StartDelayInMinutes
+		}
+	}
+
+	if firstDelay == -1 {
+		return t("incident.warning.no_notification_rules."+urgency, map[string]interface{}{"User": user.DisplayName()})
+	}
+
+	if firstDelay > pagingDelayWarningMinutes {
+		return t("incident.warning.notification_delay", map[string]interface{}{"User": user.DisplayName(), "Minutes": firstDelay})
+	}
+
+	return ""
+}
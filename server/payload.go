@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// attachRawPayload uploads the full body of an incident's triggering alert as a JSON file
+// to the thread of the incident's post
+func (p *Plugin) attachRawPayload(incident pagerduty.Incident, post *model.Post) error {
+	alerts, err := p.getPagerDutyClient().ListIncidentAlerts(incident.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident alerts")
+	}
+
+	if len(alerts) == 0 || alerts[0].Body == nil {
+		return errors.New("the incident has no alert payload")
+	}
+
+	data, err := json.MarshalIndent(alerts[0].Body, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode alert payload")
+	}
+
+	fileName := fmt.Sprintf("incident-%d-alert.json", incident.IncidentNumber)
+	fileInfo, err := p.client.File.Upload(bytes.NewReader(data), fileName, post.ChannelId)
+	if err != nil {
+		return errors.Wrap(err, "failed to upload alert payload")
+	}
+
+	if _, appErr := p.API.CreatePost(&model.Post{
+		UserId:    p.botUserID,
+		ChannelId: post.ChannelId,
+		RootId:    post.Id,
+		Message:   p.displayLanguage()("thread.raw_payload", map[string]interface{}{"Number": incident.IncidentNumber}),
+		FileIds:   []string{fileInfo.Id},
+	}); appErr != nil {
+		return errors.New("failed to create post: " + appErr.Error())
+	}
+
+	return nil
+}
+
+// performShowPayload handles the "Show raw payload" action of an incident post
+func (p *Plugin) performShowPayload(w http.ResponseWriter, incidentID string, t i18n.TranslateFunc) {
+	text := t("action.incident.payload_attached")
+
+	attachment, err := p.getIncidentAttachment(incidentID)
+	switch {
+	case err != nil:
+		p.API.LogError("Failed to get incident attachment", "error", err.Error())
+		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		return
+	case attachment == nil || attachment.PostID == "":
+		text = t("action.error.no_post_for_payload")
+	default:
+		post, appErr := p.API.GetPost(attachment.PostID)
+		if appErr != nil {
+			p.API.LogError("Failed to get incident post", "error", appErr.Error())
+			http.Error(w, "Failed to get incident post", http.StatusInternalServerError)
+			return
+		}
+
+		if err := p.attachRawPayload(attachment.Incident, post); err != nil {
+			p.API.LogWarn("Failed to attach raw alert payload", "incident_id", incidentID, "error", err.Error())
+			text = t("action.error.attach_payload", map[string]interface{}{"Error": err.Error()})
+		}
+	}
+
+	p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+		EphemeralText: text,
+	})
+}
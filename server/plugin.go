@@ -7,6 +7,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/pkg/errors"
 
 	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
@@ -27,18 +28,37 @@ type Plugin struct {
 	// commandHandler is the handler for slash commands.
 	commandHandler command.Command
 
-	// pdClient is the PagerDuty API client.
-	pdClient *client.PagerDutyClient
+	// pdClient is the PagerDuty API client. Consult getPagerDutyClient and setPagerDutyClients
+	// for usage.
+	pdClient client.PDClient
+
+	// pdJobClient is the PagerDuty API client of the background job, with a longer retry
+	// budget. Consult getJobPagerDutyClient and setPagerDutyClients for usage.
+	pdJobClient client.PDClient
 
 	// botUserID is the ID of the bot user.
 	botUserID string
 
-	// configurationLock synchronizes access to the configuration.
+	// backgroundJob is the periodic job for scheduled checks.
+	backgroundJob *cluster.Job
+
+	// configurationLock synchronizes access to the configuration, its incident templates and the
+	// PagerDuty clients.
 	configurationLock sync.RWMutex
 
 	// configuration is the active plugin configuration. Consult getConfiguration and
 	// setConfiguration for usage.
 	configuration *configuration
+
+	// incidentTemplates are the incident post templates of the configuration, parsed once when
+	// it changes rather than on every post
+	incidentTemplates incidentTemplates
+
+	// channelIDsLock synchronizes access to channelIDs.
+	channelIDsLock sync.RWMutex
+
+	// channelIDs caches the IDs of channels configured by name.
+	channelIDs map[string]string
 }
 
 // OnActivate is invoked when the plugin is activated. If an error is returned, the plugin will be deactivated.
@@ -48,6 +68,15 @@ func (p *Plugin) OnActivate() error {
 	// Initialize KV store client
 	p.kvstore = kvstore.NewKVStore(p.client)
 
+	if err := p.ensureEncryptionKey(); err != nil {
+		return errors.Wrap(err, "failed to generate encryption key")
+	}
+
+	// Upgrade data stored by earlier versions of the plugin
+	if err := p.migrate(); err != nil {
+		return errors.Wrap(err, "failed to migrate plugin data")
+	}
+
 	// Try to ensure bot exists, but continue even if it fails
 	botUserID, err := p.ensureBotExists()
 	if err != nil {
@@ -64,11 +93,17 @@ func (p *Plugin) OnActivate() error {
 	}
 
 	// Register slash commands - still useful even without bot
-	p.commandHandler = command.NewCommandHandler(p.client, p.pdClient, p.botUserID, "com.github.mnzsyu.mattermost-pagerduty-plugin")
+	p.commandHandler = command.NewCommandHandler(p.client, p.getPagerDutyClient, p.kvstore, p.botUserID, pluginID, p.displayTimezone, p.displayLanguage, p.getServiceRoutingKey, p.getUserToken)
 	if err := p.commandHandler.Register(); err != nil {
 		return errors.Wrap(err, "failed to register commands")
 	}
 
+	job, err := cluster.Schedule(p.API, "BackgroundJob", cluster.MakeWaitForRoundedInterval(jobInterval), p.runJob)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule background job")
+	}
+	p.backgroundJob = job
+
 	return nil
 }
 
@@ -90,7 +125,11 @@ func (p *Plugin) ensureBotExists() (string, error) {
 
 // OnDeactivate is invoked when the plugin is deactivated.
 func (p *Plugin) OnDeactivate() error {
-	// Perform any cleanup here
+	if p.backgroundJob != nil {
+		if err := p.backgroundJob.Close(); err != nil {
+			p.API.LogError("Failed to close background job", "error", err.Error())
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	// missedIncidentsLookback is how far back the polling fallback looks for incidents
+	missedIncidentsLookback = time.Hour
+
+	// missedIncidentsGracePeriod gives the webhook of a new incident time to arrive before
+	// the incident is considered missed
+	missedIncidentsGracePeriod = time.Minute
+)
+
+// checkMissedIncidents polls PagerDuty for recent incidents and backfills the posts of
+// incidents whose webhooks never arrived, or updates posts whose status is out of date
+func (p *Plugin) checkMissedIncidents() error {
+	now := time.Now()
+	params := url.Values{}
+	params.Set("since", now.Add(-missedIncidentsLookback).Format(time.RFC3339))
+	params.Set("until", now.Add(-missedIncidentsGracePeriod).Format(time.RFC3339))
+
+	incidents, err := p.getJobPagerDutyClient().ListIncidents(params)
+	if err != nil {
+		return errors.Wrap(err, "failed to list incidents")
+	}
+
+	for _, incident := range incidents {
+		if err := p.backfillIncident(incident); err != nil {
+			p.API.LogWarn("Failed to backfill incident", "incident_id", incident.ID, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// backfillIncident posts an incident whose webhooks never arrived, or updates its post if it is
+// out of date
+func (p *Plugin) backfillIncident(incident pagerduty.Incident) error {
+	// A webhook for the incident may be processed at the same time
+	unlock, err := p.lockIncident(incident.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	attachment, err := p.getIncidentAttachment(incident.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident attachment")
+	}
+
+	if attachment != nil {
+		if attachment.MergedIntoID != "" || attachment.ReopenedAsID != "" || !incidentChanged(attachment.Incident, incident) {
+			return nil
+		}
+
+		p.API.LogInfo("Updating incident post missed by webhooks", "incident_id", incident.ID, "status", incident.Status)
+		return p.updateIncidentPost(incident, attachment)
+	}
+
+	// Incidents resolved by merging are shown on the post of the incident they were merged into
+	if incident.Status == client.StatusResolved && p.getMergeResolveReason(incident) != nil {
+		return nil
+	}
+
+	channelID, err := p.getIncidentChannelID(incident)
+	if err != nil {
+		return errors.Wrap(err, "failed to get channel ID")
+	}
+
+	p.API.LogInfo("Posting incident missed by webhooks", "incident_id", incident.ID)
+	return p.handleTriggeredIncident(incident, channelID)
+}
+
+// resyncOpenIncidentPosts fetches the live state of every incident whose post shows it open,
+// and updates the posts that are out of date because webhooks were missed
+func (p *Plugin) resyncOpenIncidentPosts() error {
+	attachments, err := p.kvstore.GetIncidentAttachments()
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident attachments")
+	}
+
+	for _, attachment := range attachments {
+		if attachment.Incident.Status == client.StatusResolved || attachment.MergedIntoID != "" {
+			continue
+		}
+
+		incident, err := p.getJobPagerDutyClient().GetIncident(attachment.ID)
+		if err != nil {
+			p.API.LogWarn("Failed to get incident", "incident_id", attachment.ID, "error", err.Error())
+			continue
+		}
+
+		if !incidentChanged(attachment.Incident, *incident) {
+			continue
+		}
+
+		p.API.LogInfo("Resynchronizing out of date incident post", "incident_id", incident.ID, "status", incident.Status)
+
+		if incident.Status == client.StatusResolved {
+			if reason := p.getMergeResolveReason(*incident); reason != nil {
+				if err := p.handleMergedIncident(*incident, attachment, reason); err != nil {
+					p.API.LogWarn("Failed to update merged incident post", "incident_id", incident.ID, "error", err.Error())
+				}
+				continue
+			}
+		}
+
+		if err := p.updateIncidentPost(*incident, attachment); err != nil {
+			p.API.LogWarn("Failed to update incident post", "incident_id", incident.ID, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// incidentChanged reports whether the fields shown on an incident post differ between two
+// states of the incident
+func incidentChanged(stored, live pagerduty.Incident) bool {
+	if stored.Status != live.Status || stored.Urgency != live.Urgency || stored.Title != live.Title {
+		return true
+	}
+
+	storedPriority, livePriority := "", ""
+	if stored.Priority != nil {
+		storedPriority = stored.Priority.ID
+	}
+	if live.Priority != nil {
+		livePriority = live.Priority.ID
+	}
+	if storedPriority != livePriority {
+		return true
+	}
+
+	assigneeIDs := func(incident pagerduty.Incident) []string {
+		var ids []string
+		for _, assignment := range incident.Assignments {
+			ids = append(ids, assignment.Assignee.ID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	return !slices.Equal(assigneeIDs(stored), assigneeIDs(live))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// getPriorities gets the incident priorities of the PagerDuty account, cached for an hour
+func (p *Plugin) getPriorities() ([]pagerduty.Priority, error) {
+	priorities, err := p.kvstore.GetCachedPriorities()
+	if err != nil {
+		p.API.LogWarn("Failed to get cached priorities", "error", err.Error())
+	}
+	if priorities != nil {
+		return priorities, nil
+	}
+
+	priorities, err = p.getPagerDutyClient().ListPriorities()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list priorities")
+	}
+
+	if err := p.kvstore.SaveCachedPriorities(priorities); err != nil {
+		p.API.LogWarn("Failed to cache priorities", "error", err.Error())
+	}
+
+	return priorities, nil
+}
+
+// getPriorityOptions gets the priorities an incident can be set to, which are none if priorities
+// are disabled for the account
+func (p *Plugin) getPriorityOptions(incident pagerduty.Incident) []*model.PostActionOptions {
+	priorities, err := p.getPriorities()
+	if err != nil {
+		p.API.LogWarn("Failed to get priorities for priority options", "incident_id", incident.ID, "error", err.Error())
+		return nil
+	}
+
+	var options []*model.PostActionOptions
+	for _, priority := range priorities {
+		options = append(options, &model.PostActionOptions{Text: priority.Name, Value: priority.ID})
+	}
+	return options
+}
+
+// performSetPriority sets the priority of an incident and refreshes its post
+func (p *Plugin) performSetPriority(w http.ResponseWriter, pdClient client.PDClient, incidentID, priorityID string, user *model.User) {
+	if priorityID == "" {
+		http.Error(w, "Missing priority", http.StatusBadRequest)
+		return
+	}
+
+	t := i18n.ForUser(user)
+	var text string
+	incident, err := pdClient.SetIncidentPriority(incidentID, priorityID, user.Email)
+	if err != nil {
+		p.API.LogError("Failed to set incident priority", "incident_id", incidentID, "error", err.Error())
+		text = t("action.error.set_priority", map[string]interface{}{"Error": err.Error()})
+	} else {
+		text = t("action.incident.priority_updated")
+		if incident.Priority != nil {
+			text = t("action.incident.priority_set", map[string]interface{}{"Priority": incident.Priority.Name})
+		}
+
+		attachment, err := p.getIncidentAttachment(incidentID)
+		if err != nil {
+			p.API.LogWarn("Failed to get incident attachment", "incident_id", incidentID, "error", err.Error())
+		} else if attachment != nil && attachment.PostID != "" {
+			if err := p.updateIncidentPost(*incident, attachment); err != nil {
+				p.API.LogWarn("Failed to update incident post", "incident_id", incidentID, "error", err.Error())
+			}
+		}
+	}
+
+	p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+		EphemeralText: text,
+	})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// getReassignOptions gets the users an incident can be reassigned to: the users on call for its
+// escalation policy and the users its levels target directly, by level, then the user who last
+// changed its status. Current assignees are left out.
+func (p *Plugin) getReassignOptions(incident pagerduty.Incident, t i18n.TranslateFunc) []*model.PostActionOptions {
+	assigned := map[string]bool{}
+	for _, assignment := range incident.Assignments {
+		assigned[assignment.Assignee.ID] = true
+	}
+
+	options := []*model.PostActionOptions{}
+	addOption := func(user pagerduty.User, text string) {
+		if user.ID == "" || assigned[user.ID] {
+			return
+		}
+		assigned[user.ID] = true
+		options = append(options, &model.PostActionOptions{Text: text, Value: user.ID})
+	}
+
+	if incident.EscalationPolicy.ID != "" {
+		onCalls, err := p.getPolicyOnCalls(incident.EscalationPolicy.ID)
+		if err != nil {
+			p.API.LogWarn("Failed to list on-calls for reassign options", "incident_id", incident.ID, "error", err.Error())
+		}
+
+		sort.SliceStable(onCalls, func(i, j int) bool {
+			return onCalls[i].EscalationLevel < onCalls[j].EscalationLevel
+		})
+
+		for _, onCall := range onCalls {
+			addOption(onCall.User, t("incident.reassign.on_call", map[string]interface{}{"User": onCall.User.DisplayName(), "Level": onCall.EscalationLevel}))
+		}
+
+		// Users targeted by a level are paged whether or not they are listed as on call
+		if policy := p.getEscalationPolicy(incident.EscalationPolicy.ID); policy != nil {
+			for i, rule := range policy.EscalationRules {
+				for _, target := range rule.Targets {
+					if target.Type == pagerduty.EscalationTargetUser {
+						addOption(pagerduty.User{ID: target.ID, Summary: target.Summary}, t("incident.reassign.level", map[string]interface{}{"User": target.Summary, "Level": i + 1}))
+					}
+				}
+			}
+		}
+	}
+
+	if incident.LastStatusChangeBy.Type == "user_reference" || incident.LastStatusChangeBy.Type == "user" {
+		addOption(incident.LastStatusChangeBy, t("incident.reassign.last_responder", map[string]interface{}{"User": incident.LastStatusChangeBy.DisplayName()}))
+	}
+
+	return options
+}
+
+// getPolicyOnCalls gets the on-calls of an escalation policy, from the cache if possible, as
+// they are offered on every render of an open incident post
+func (p *Plugin) getPolicyOnCalls(policyID string) ([]pagerduty.OnCall, error) {
+	onCalls, err := p.kvstore.GetCachedPolicyOnCalls(policyID)
+	if err != nil {
+		p.API.LogWarn("Failed to get cached on-calls", "escalation_policy_id", policyID, "error", err.Error())
+	}
+	if onCalls != nil {
+		return onCalls, nil
+	}
+
+	params := url.Values{}
+	params.Add("escalation_policy_ids[]", policyID)
+
+	onCalls, err = p.getPagerDutyClient().ListOnCalls(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list on-calls")
+	}
+
+	if err := p.kvstore.SaveCachedPolicyOnCalls(policyID, onCalls); err != nil {
+		p.API.LogWarn("Failed to cache on-calls", "escalation_policy_id", policyID, "error", err.Error())
+	}
+
+	return onCalls, nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/command"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// handleReopenedIncident creates a fresh post for a resolved incident that triggered again,
+// linking back to the earlier thread, and marks the earlier post as superseded.
+func (p *Plugin) handleReopenedIncident(incident pagerduty.Incident, previous *pagerduty.PostAttachment, channelID string) error {
+	p.API.LogInfo("Incident reopened", "incident_id", incident.ID, "previous_post_id", previous.PostID)
+
+	attachment, err := p.postIncident(incident, channelID, previous.PostID)
+	if err != nil {
+		return err
+	}
+
+	// Suppressed during maintenance, the earlier post stays as it is
+	if attachment == nil {
+		return nil
+	}
+
+	post, appErr := p.API.GetPost(previous.PostID)
+	if appErr != nil {
+		// The earlier post might have been deleted
+		p.API.LogWarn("Failed to get earlier incident post", "post_id", previous.PostID, "error", appErr.Error())
+		return nil
+	}
+
+	options := p.newIncidentPostOptions(previous.Incident)
+	options.Maintenance = previous.Maintenance
+	options.PreviousPostID = previous.PreviousPostID
+	options.SupersededByPostID = attachment.PostID
+	p.setIncidentPostContent(post, previous.Incident, options)
+
+	if _, appErr := p.API.UpdatePost(post); appErr != nil {
+		return errors.New("failed to mark earlier post as superseded: " + appErr.Error())
+	}
+
+	return nil
+}
+
+// handleReopenDialog handles the dialog of the reopen command, triggering a new incident linked
+// to the resolved one, which takes over the resolved incident's post with its action buttons back
+func (p *Plugin) handleReopenDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	incidentID := request.State
+	reason, _ := request.Submission[command.ReopenDialogReason].(string)
+	reason = strings.TrimSpace(reason)
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+	t := i18n.ForUser(user)
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	resolved, err := pdClient.GetIncident(incidentID)
+	if err != nil {
+		p.API.LogError("Failed to get incident", "incident_id", incidentID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.reopen.error.reopen", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+	if resolved.Status != client.StatusResolved {
+		p.writeDialogError(w, t("command.reopen.error.not_resolved", map[string]interface{}{
+			"Number": resolved.IncidentNumber,
+			"URL":    resolved.HTMLURL,
+			"Status": strings.ToLower(t("command.list.status." + resolved.Status)),
+		}))
+		return
+	}
+
+	incident, err := pdClient.ReopenIncident(*resolved, reason, user.Email)
+	if err != nil {
+		p.API.LogError("Failed to reopen incident", "incident_id", incidentID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.reopen.error.reopen", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	p.API.LogInfo("Incident reopened from Mattermost", "incident_id", incidentID, "reopened_incident_id", incident.ID, "user_id", userID)
+
+	p.recordIncidentAction(incidentID, "reopened", user)
+
+	attachment, err := p.reopenIncidentPost(*incident, incidentID, request.ChannelId)
+	if err != nil {
+		p.API.LogError("Failed to post reopened incident", "incident_id", incident.ID, "error", err.Error())
+	}
+
+	// Link the resolved incident to the new one in PagerDuty too, and announce the reopen in the
+	// thread the new incident carries on
+	note := fmt.Sprintf("Reopened as incident #%d: %s", incident.IncidentNumber, incident.HTMLURL)
+	if reason != "" {
+		note += "\n\nReason: " + reason
+	}
+	added, err := pdClient.AddNote(incidentID, note, user.Email)
+	if err != nil {
+		p.API.LogWarn("Failed to add reopen note", "incident_id", incidentID, "error", err.Error())
+	} else if attachment != nil && attachment.PostID != "" {
+		if err := p.postIncidentNote(attachment, added.ID, "@"+user.Username, note); err != nil {
+			p.API.LogWarn("Failed to post reopen note", "incident_id", incident.ID, "error", err.Error())
+		}
+	}
+
+	p.API.SendEphemeralPost(userID, &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: request.ChannelId,
+		Message:   t("dialog.reopen.reopened", map[string]interface{}{"Number": resolved.IncidentNumber, "NewNumber": incident.IncidentNumber, "URL": incident.HTMLURL}),
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// reopenIncidentPost hands the post of a resolved incident over to the incident reopening it. The
+// post shows the new incident, triggered with its action buttons back, and later updates of the
+// resolved incident leave it alone. Without a post of the resolved incident, the new incident is
+// posted like a triggered incident. It returns the new incident's attachment, or nil if the post
+// was suppressed.
+func (p *Plugin) reopenIncidentPost(incident pagerduty.Incident, resolvedID, channelID string) (*pagerduty.PostAttachment, error) {
+	// The incident's triggered webhook may arrive while it is being posted here
+	unlock, err := p.lockIncident(incident.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock incident")
+	}
+	defer unlock()
+
+	if attachment, err := p.getIncidentAttachment(incident.ID); err == nil && attachment != nil {
+		// Already posted from the webhook
+		return attachment, nil
+	}
+
+	previous, err := p.getIncidentAttachment(resolvedID)
+	if err != nil {
+		p.API.LogWarn("Failed to get resolved incident attachment", "incident_id", resolvedID, "error", err.Error())
+	}
+
+	var post *model.Post
+	if previous != nil && previous.PostID != "" && !previous.Suppressed && previous.ReopenedAsID == "" {
+		var appErr *model.AppError
+		if post, appErr = p.API.GetPost(previous.PostID); appErr != nil {
+			// The resolved incident's post might have been deleted
+			p.API.LogWarn("Failed to get resolved incident post", "post_id", previous.PostID, "error", appErr.Error())
+		}
+	}
+
+	if post == nil {
+		if routedChannelID, err := p.getIncidentChannelID(incident); err == nil {
+			channelID = routedChannelID
+		}
+		return p.postIncident(incident, channelID, "")
+	}
+
+	attachment := &pagerduty.PostAttachment{
+		ID:             incident.ID,
+		PostID:         previous.PostID,
+		ChannelID:      previous.ChannelID,
+		Incident:       incident,
+		Maintenance:    previous.Maintenance,
+		PagingWarnings: p.getPagingWarnings(incident),
+		PreviousPostID: previous.PreviousPostID,
+	}
+	if err := p.storeIncidentAttachment(attachment); err != nil {
+		return nil, errors.Wrap(err, "failed to store incident attachment")
+	}
+
+	if _, err := p.updateIncidentAttachment(resolvedID, func(stored *pagerduty.PostAttachment) {
+		stored.ReopenedAsID = incident.ID
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to update resolved incident attachment")
+	}
+
+	p.renderIncidentPost(post, incident, attachment)
+	if _, appErr := p.API.UpdatePost(post); appErr != nil {
+		return nil, errors.New("failed to update incident post: " + appErr.Error())
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client/mocks"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// attachmentStore keeps incident attachments in memory for the tests
+type attachmentStore struct {
+	kvstore.KVStore
+	attachments map[string]*pagerduty.PostAttachment
+}
+
+func (s *attachmentStore) GetIncidentAttachment(incidentID string) (*pagerduty.PostAttachment, error) {
+	return s.attachments[incidentID], nil
+}
+
+func (s *attachmentStore) SaveIncidentAttachment(attachment *pagerduty.PostAttachment) error {
+	s.attachments[attachment.ID] = attachment
+	return nil
+}
+
+func (s *attachmentStore) UpdateIncidentAttachment(incidentID string, change func(*pagerduty.PostAttachment)) (*pagerduty.PostAttachment, error) {
+	attachment := s.attachments[incidentID]
+	if attachment != nil {
+		change(attachment)
+	}
+	return attachment, nil
+}
+
+func (s *attachmentStore) AddOpenIncident(channelID, incidentID, postID string) error {
+	return nil
+}
+
+func (s *attachmentStore) RemoveOpenIncident(channelID, incidentID string) error {
+	return nil
+}
+
+func (s *attachmentStore) GetSubscription(serviceID string) (*kvstore.Subscription, error) {
+	return nil, nil
+}
+
+func (s *attachmentStore) GetCachedService(serviceID string) (*pagerduty.Service, error) {
+	return &pagerduty.Service{ID: serviceID, Name: "API"}, nil
+}
+
+func (s *attachmentStore) GetCachedPriorities() ([]pagerduty.Priority, error) {
+	return []pagerduty.Priority{}, nil
+}
+
+func (s *attachmentStore) GetCachedIncidentWorkflows() ([]pagerduty.IncidentWorkflow, error) {
+	return []pagerduty.IncidentWorkflow{}, nil
+}
+
+func TestReopenIncidentPost(t *testing.T) {
+	resolved := &pagerduty.PostAttachment{
+		ID:        "I1",
+		PostID:    "post1",
+		ChannelID: "channel1",
+		Incident: pagerduty.Incident{
+			ID:             "I1",
+			IncidentNumber: 41,
+			Status:         client.StatusResolved,
+		},
+	}
+	incident := pagerduty.Incident{
+		ID:             "I2",
+		IncidentNumber: 42,
+		Title:          "API is down",
+		Status:         client.StatusTriggered,
+		Service:        pagerduty.Service{ID: "S1"},
+	}
+
+	setup := func(t *testing.T, attachments ...*pagerduty.PostAttachment) (*Plugin, *plugintest.API, *attachmentStore) {
+		ctrl := gomock.NewController(t)
+		pdClient := mocks.NewMockPDClient(ctrl)
+		pdClient.EXPECT().ListIncidentAlerts(gomock.Any()).Return(nil, nil).AnyTimes()
+
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+		store := &attachmentStore{attachments: map[string]*pagerduty.PostAttachment{}}
+		for _, attachment := range attachments {
+			copied := *attachment
+			store.attachments[attachment.ID] = &copied
+		}
+
+		p := &Plugin{pdClient: pdClient, kvstore: store}
+		p.SetAPI(api)
+		return p, api, store
+	}
+
+	t.Run("takes over the resolved incident's post with its buttons", func(t *testing.T) {
+		p, api, store := setup(t, resolved)
+		api.On("GetPost", "post1").Return(&model.Post{Id: "post1", ChannelId: "channel1"}, nil)
+
+		var updated *model.Post
+		api.On("UpdatePost", mock.Anything).Run(func(args mock.Arguments) {
+			updated = args.Get(0).(*model.Post)
+		}).Return(&model.Post{}, nil)
+
+		attachment, err := p.reopenIncidentPost(incident, "I1", "channel2")
+		require.NoError(t, err)
+
+		require.NotNil(t, attachment)
+		assert.Equal(t, "post1", attachment.PostID)
+		assert.Equal(t, "channel1", attachment.ChannelID)
+		assert.Equal(t, "I2", store.attachments["I1"].ReopenedAsID)
+
+		require.NotNil(t, updated)
+		assert.Equal(t, "post1", updated.Id)
+		attachments := updated.Attachments()
+		require.Len(t, attachments, 1)
+		var actionIDs []string
+		for _, action := range attachments[0].Actions {
+			actionIDs = append(actionIDs, action.Id)
+		}
+		assert.Contains(t, actionIDs, ActionAcknowledge)
+		assert.Contains(t, actionIDs, ActionResolve)
+	})
+
+	t.Run("leaves an incident posted from the webhook alone", func(t *testing.T) {
+		posted := &pagerduty.PostAttachment{ID: "I2", PostID: "post2", ChannelID: "channel1", Incident: incident}
+		p, api, store := setup(t, resolved, posted)
+
+		attachment, err := p.reopenIncidentPost(incident, "I1", "channel2")
+		require.NoError(t, err)
+
+		assert.Equal(t, "post2", attachment.PostID)
+		assert.Empty(t, store.attachments["I1"].ReopenedAsID)
+		api.AssertNotCalled(t, "UpdatePost", mock.Anything)
+	})
+}
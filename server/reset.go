@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/command"
+)
+
+// handleResetDialog handles the confirmation dialog of the reset command
+func (p *Plugin) handleResetDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var state command.ResetState
+	if err := json.Unmarshal([]byte(request.State), &state); err != nil {
+		http.Error(w, "Invalid dialog state", http.StatusBadRequest)
+		return
+	}
+
+	t := p.translate(userID)
+	message := t("dialog.reset.done")
+	if err := p.resetPluginData(state.DeletePosts); err != nil {
+		p.API.LogError("Failed to reset plugin data", "error", err.Error())
+		message = t("dialog.reset.error", map[string]interface{}{"Error": err.Error()})
+	} else {
+		p.API.LogInfo("Plugin data reset", "user_id", userID, "delete_posts", state.DeletePosts)
+	}
+
+	p.API.SendEphemeralPost(userID, &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: request.ChannelId,
+		Message:   message,
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// resetPluginData deletes all data stored by the plugin and, if deletePosts is set, the posts of
+// tracked incidents. Posts are deleted first, while the incidents are still tracked.
+func (p *Plugin) resetPluginData(deletePosts bool) error {
+	if deletePosts {
+		attachments, err := p.kvstore.GetIncidentAttachments()
+		if err != nil {
+			return err
+		}
+
+		for _, attachment := range attachments {
+			if attachment.PostID == "" || attachment.Suppressed {
+				continue
+			}
+			if appErr := p.API.DeletePost(attachment.PostID); appErr != nil {
+				p.API.LogWarn("Failed to delete incident post", "post_id", attachment.PostID, "error", appErr.Error())
+			}
+		}
+	}
+
+	return p.kvstore.DeleteAll()
+}
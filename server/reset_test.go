@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// resetStore keeps incident attachments in memory and records whether all data was deleted
+type resetStore struct {
+	kvstore.KVStore
+	attachments []*pagerduty.PostAttachment
+	deletedAll  bool
+}
+
+func (s *resetStore) GetIncidentAttachments() ([]*pagerduty.PostAttachment, error) {
+	return s.attachments, nil
+}
+
+func (s *resetStore) DeleteAll() error {
+	s.deletedAll = true
+	return nil
+}
+
+func TestHandleResetDialog(t *testing.T) {
+	setup := func(t *testing.T, admin bool) (*Plugin, *plugintest.API, *resetStore) {
+		api := &plugintest.API{}
+		api.On("HasPermissionTo", "user1", model.PermissionManageSystem).Return(admin)
+		api.On("GetUser", "user1").Return(&model.User{Id: "user1", Locale: "en"}, nil).Maybe()
+		api.On("SendEphemeralPost", "user1", mock.Anything).Return(&model.Post{}).Maybe()
+		api.On("LogInfo", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+		store := &resetStore{attachments: []*pagerduty.PostAttachment{
+			{ID: "I1", PostID: "post1"},
+			{ID: "I2", PostID: "post2"},
+			{ID: "I3", Suppressed: true},
+		}}
+
+		p := &Plugin{kvstore: store, botUserID: "bot"}
+		p.SetAPI(api)
+		return p, api, store
+	}
+
+	// submit submits the reset dialog as user1
+	submit := func(p *Plugin, request model.SubmitDialogRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(request)
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reset", bytes.NewReader(body))
+		r.Header.Set("Mattermost-User-ID", "user1")
+		w := httptest.NewRecorder()
+		p.handleResetDialog(w, r)
+		return w
+	}
+
+	t.Run("deletes the incident posts and all data", func(t *testing.T) {
+		p, api, store := setup(t, true)
+		api.On("DeletePost", "post1").Return(nil).Once()
+		api.On("DeletePost", "post2").Return(nil).Once()
+
+		w := submit(p, model.SubmitDialogRequest{ChannelId: "channel1", State: `{"delete_posts": true}`})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.True(t, store.deletedAll)
+		api.AssertExpectations(t)
+		api.AssertNumberOfCalls(t, "DeletePost", 2)
+	})
+
+	t.Run("keeps the posts unless asked", func(t *testing.T) {
+		p, api, store := setup(t, true)
+
+		w := submit(p, model.SubmitDialogRequest{ChannelId: "channel1", State: `{"delete_posts": false}`})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.True(t, store.deletedAll)
+		api.AssertNotCalled(t, "DeletePost", mock.Anything)
+	})
+
+	t.Run("refuses users who aren't system admins", func(t *testing.T) {
+		p, api, store := setup(t, false)
+
+		w := submit(p, model.SubmitDialogRequest{ChannelId: "channel1", State: `{"delete_posts": true}`})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		assert.False(t, store.deletedAll)
+		api.AssertNotCalled(t, "DeletePost", mock.Anything)
+	})
+
+	t.Run("does nothing when canceled", func(t *testing.T) {
+		p, api, store := setup(t, true)
+
+		w := submit(p, model.SubmitDialogRequest{Cancelled: true, State: `{"delete_posts": true}`})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.False(t, store.deletedAll)
+		api.AssertNotCalled(t, "DeletePost", mock.Anything)
+	})
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+)
+
+// Name of the resolution note element of the resolve dialog
+const resolveDialogNote = "resolution_note"
+
+// performOpenResolveDialog opens the dialog confirming that an incident should be resolved
+func (p *Plugin) performOpenResolveDialog(w http.ResponseWriter, incidentID, triggerID string, t i18n.TranslateFunc) {
+	title := t("dialog.resolve.title")
+	if attachment, err := p.getIncidentAttachment(incidentID); err == nil && attachment != nil {
+		title = t("dialog.resolve.title_number", map[string]interface{}{"Number": attachment.Incident.IncidentNumber})
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/incidents/resolve", pluginID),
+		Dialog: model.Dialog{
+			Title:            title,
+			IntroductionText: t("dialog.resolve.introduction"),
+			SubmitLabel:      t("incident.action.resolve"),
+			State:            incidentID,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: t("dialog.resolve.note"),
+					Name:        resolveDialogNote,
+					Type:        "textarea",
+					Optional:    true,
+					MaxLength:   noteDialogMaxLength,
+					HelpText:    t("dialog.resolve.note_help"),
+				},
+			},
+		},
+	}); appErr != nil {
+		p.API.LogError("Failed to open resolve dialog", "error", appErr.Error())
+		http.Error(w, "Failed to open dialog", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&model.PostActionIntegrationResponse{}); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
+
+// handleResolveDialog handles the resolve dialog, resolving the incident with the optional
+// resolution note
+func (p *Plugin) handleResolveDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	incidentID := request.State
+	note, _ := request.Submission[resolveDialogNote].(string)
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+	t := i18n.ForUser(user)
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	if note = strings.TrimSpace(note); note != "" {
+		if _, err := pdClient.AddNote(incidentID, note, user.Email); err != nil {
+			p.API.LogError("Failed to add resolution note", "incident_id", incidentID, "error", err.Error())
+			p.writeDialogError(w, t("dialog.resolve.error.add_note", map[string]interface{}{"Error": err.Error()}))
+			return
+		}
+	}
+
+	incident, err := pdClient.UpdateIncident(incidentID, client.StatusResolved, user.Email, "")
+	if err != nil {
+		p.API.LogError("Failed to resolve incident", "incident_id", incidentID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.resolve.error.resolve", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	p.recordIncidentAction(incidentID, client.StatusResolved, user)
+
+	// Show the new status right away instead of waiting for the webhook
+	p.refreshIncidentPost(*incident)
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	// Names of the elements of the responders dialog
+	respondersDialogUser    = "user_id"
+	respondersDialogPolicy  = "escalation_policy_id"
+	respondersDialogMessage = "message"
+
+	// defaultResponderMessage is sent to the requested responders if the user gives no message
+	defaultResponderMessage = "Please help with this incident."
+)
+
+// performOpenRespondersDialog opens the dialog to request additional responders for an incident
+// from its post, offering the PagerDuty users and escalation policies of the account
+func (p *Plugin) performOpenRespondersDialog(w http.ResponseWriter, incidentID, triggerID string, t i18n.TranslateFunc) {
+	users, err := p.getPagerDutyClient().ListUsers()
+	if err != nil {
+		p.API.LogError("Failed to list users", "error", err.Error())
+		p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+			EphemeralText: t("dialog.responders.error.get_users", map[string]interface{}{"Error": err.Error()}),
+		})
+		return
+	}
+
+	policies, err := p.getPagerDutyClient().ListEscalationPolicies(nil)
+	if err != nil {
+		p.API.LogError("Failed to list escalation policies", "error", err.Error())
+		p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+			EphemeralText: t("dialog.responders.error.get_policies", map[string]interface{}{"Error": err.Error()}),
+		})
+		return
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return strings.ToLower(users[i].DisplayName()) < strings.ToLower(users[j].DisplayName())
+	})
+	userOptions := make([]*model.PostActionOptions, 0, len(users))
+	for _, user := range users {
+		userOptions = append(userOptions, &model.PostActionOptions{Text: user.DisplayName(), Value: user.ID})
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return strings.ToLower(policies[i].Name) < strings.ToLower(policies[j].Name)
+	})
+	policyOptions := make([]*model.PostActionOptions, 0, len(policies))
+	for _, policy := range policies {
+		policyOptions = append(policyOptions, &model.PostActionOptions{Text: policy.Name, Value: policy.ID})
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/incidents/responders", pluginID),
+		Dialog: model.Dialog{
+			Title:            t("incident.action.add_responders"),
+			IntroductionText: t("dialog.responders.introduction"),
+			SubmitLabel:      t("dialog.responders.submit"),
+			State:            incidentID,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: t("dialog.responders.user"),
+					Name:        respondersDialogUser,
+					Type:        "select",
+					Optional:    true,
+					Options:     userOptions,
+				},
+				{
+					DisplayName: t("incident.field.escalation_policy"),
+					Name:        respondersDialogPolicy,
+					Type:        "select",
+					Optional:    true,
+					Options:     policyOptions,
+				},
+				{
+					DisplayName: t("dialog.responders.message"),
+					Name:        respondersDialogMessage,
+					Type:        "textarea",
+					Optional:    true,
+					MaxLength:   noteDialogMaxLength,
+					Placeholder: defaultResponderMessage,
+					HelpText:    t("dialog.responders.message_help"),
+				},
+			},
+		},
+	}); appErr != nil {
+		p.API.LogError("Failed to open responders dialog", "error", appErr.Error())
+		http.Error(w, "Failed to open dialog", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&model.PostActionIntegrationResponse{}); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
+
+// handleRespondersDialog handles the responders dialog, requesting the chosen user and
+// escalation policy to respond to the incident
+func (p *Plugin) handleRespondersDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	incidentID := request.State
+	responderID, _ := request.Submission[respondersDialogUser].(string)
+	policyID, _ := request.Submission[respondersDialogPolicy].(string)
+	message, _ := request.Submission[respondersDialogMessage].(string)
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+	t := i18n.ForUser(user)
+
+	var targets []pagerduty.ResponderTarget
+	if responderID != "" {
+		targets = append(targets, pagerduty.ResponderTarget{ID: responderID, Type: pagerduty.ResponderTargetUser})
+	}
+	if policyID != "" {
+		targets = append(targets, pagerduty.ResponderTarget{ID: policyID, Type: pagerduty.ResponderTargetEscalationPolicy})
+	}
+	if len(targets) == 0 {
+		p.writeDialogErrors(w, map[string]string{
+			respondersDialogUser:   t("dialog.responders.error.no_target"),
+			respondersDialogPolicy: t("dialog.responders.error.no_target"),
+		})
+		return
+	}
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	text, err := p.requestResponders(pdClient, incidentID, user, targets, message)
+	if err != nil {
+		p.API.LogError("Failed to request responders", "incident_id", incidentID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.responders.error.request", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	p.API.SendEphemeralPost(userID, &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: request.ChannelId,
+		Message:   text,
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// requestResponders asks users and escalation policies to help with an incident on behalf of a
+// user and posts the request in the incident thread, returning a message for the user
+func (p *Plugin) requestResponders(pdClient client.PDClient, incidentID string, user *model.User, targets []pagerduty.ResponderTarget, message string) (string, error) {
+	requester, err := p.getPagerDutyUser(user)
+	if err != nil {
+		return "", err
+	}
+	if requester == nil {
+		return "", errors.Errorf("no PagerDuty user found with the email %s", user.Email)
+	}
+
+	if message = strings.TrimSpace(message); message == "" {
+		message = defaultResponderMessage
+	}
+
+	responderRequest, err := pdClient.RequestResponders(incidentID, requester.ID, message, targets, user.Email)
+	if err != nil {
+		return "", err
+	}
+
+	p.API.LogInfo("Responders requested from Mattermost", "incident_id", incidentID, "user_id", user.Id, "targets", len(targets))
+
+	var names []string
+	for _, target := range responderRequest.Targets {
+		if target.Target.Summary != "" {
+			names = append(names, target.Target.Summary)
+		}
+	}
+	requested := func(t i18n.TranslateFunc) string {
+		if len(names) == 0 {
+			return t("thread.responders.additional")
+		}
+		return "**" + strings.Join(names, "**, **") + "**"
+	}
+
+	attachment, err := p.getIncidentAttachment(incidentID)
+	switch {
+	case err != nil:
+		p.API.LogWarn("Failed to get incident attachment", "incident_id", incidentID, "error", err.Error())
+	case attachment == nil || attachment.Suppressed || attachment.PostID == "":
+		p.API.LogDebug("Not posting responder request of an incident without a post", "incident_id", incidentID)
+	default:
+		quoted := "> " + strings.ReplaceAll(message, "\n", "\n> ")
+		if _, appErr := p.API.CreatePost(&model.Post{
+			UserId:    p.botUserID,
+			ChannelId: attachment.ChannelID,
+			RootId:    attachment.PostID,
+			Message:   p.displayLanguage()("thread.responders.requested_help", map[string]interface{}{"Username": user.Username, "Responders": requested(p.displayLanguage()), "Message": quoted}),
+		}); appErr != nil {
+			p.API.LogWarn("Failed to post responder request", "incident_id", incidentID, "error", appErr.Error())
+			break
+		}
+		for _, target := range responderRequest.Targets {
+			if err := p.kvstore.MarkResponderRequestPosted(incidentID, target.Target.ID); err != nil {
+				p.API.LogWarn("Failed to mark responder request as posted", "incident_id", incidentID, "error", err.Error())
+			}
+		}
+	}
+
+	t := i18n.ForUser(user)
+	return t("action.incident.responders_requested", map[string]interface{}{"Responders": requested(t)}), nil
+}
+
+// handleIncidentResponder posts the request of a responder to help with an incident, and their
+// reply, as a reply in the thread of the incident's post, so the channel sees who is pulled in
+func (p *Plugin) handleIncidentResponder(event pagerduty.V3Event) error {
+	responder := event.Responder
+	if responder == nil || responder.Incident.ID == "" || responder.User.ID == "" {
+		p.API.LogInfo("Ignoring responder event without a responder", "event_id", event.ID)
+		return nil
+	}
+
+	attachment, err := p.getIncidentAttachment(responder.Incident.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident attachment")
+	}
+	if attachment == nil || attachment.PostID == "" || attachment.Suppressed {
+		p.API.LogDebug("Ignoring responder of an incident without a post", "incident_id", responder.Incident.ID)
+		return nil
+	}
+
+	name := p.formatPagerDutyUser(pagerduty.User{
+		ID:      responder.User.ID,
+		Summary: responder.User.Summary,
+		HTMLURL: responder.User.HTMLURL,
+	})
+
+	t := p.displayLanguage()
+	var text string
+	switch {
+	case event.EventType == pagerduty.V3EventResponderAdded:
+		// Requests made from Mattermost were already posted
+		targetIDs := []string{responder.User.ID}
+		if responder.EscalationPolicy != nil {
+			targetIDs = append(targetIDs, responder.EscalationPolicy.ID)
+		}
+		for _, targetID := range targetIDs {
+			posted, err := p.kvstore.IsResponderRequestPosted(responder.Incident.ID, targetID)
+			if err != nil {
+				p.API.LogWarn("Failed to get posted responder request", "incident_id", responder.Incident.ID, "error", err.Error())
+			}
+			if posted {
+				return nil
+			}
+		}
+
+		text = t("thread.responders.requested", map[string]interface{}{"Name": name})
+		if responder.EscalationPolicy != nil && responder.EscalationPolicy.Summary != "" {
+			text += " " + t("thread.responders.through", map[string]interface{}{"Policy": responder.EscalationPolicy.Summary})
+		}
+		if event.Agent.Summary != "" {
+			text += " " + t("thread.by", map[string]interface{}{"Agent": event.Agent.Summary})
+		}
+		if message := strings.TrimSpace(responder.Message); message != "" {
+			text += ":\n> " + strings.ReplaceAll(message, "\n", "\n> ")
+		}
+	case responder.State == pagerduty.ResponderStateJoined:
+		text = t("thread.responders.accepted", map[string]interface{}{"Name": name})
+	case responder.State == pagerduty.ResponderStateDeclined:
+		text = t("thread.responders.declined", map[string]interface{}{"Name": name})
+	default:
+		p.API.LogDebug("Ignoring responder reply", "incident_id", responder.Incident.ID, "state", responder.State)
+		return nil
+	}
+
+	if _, appErr := p.API.CreatePost(&model.Post{
+		UserId:    p.botUserID,
+		ChannelId: attachment.ChannelID,
+		RootId:    attachment.PostID,
+		Message:   text,
+	}); appErr != nil {
+		return errors.New("failed to post responder: " + appErr.Error())
+	}
+
+	return nil
+}
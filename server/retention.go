@@ -0,0 +1,43 @@
+package main
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+)
+
+// cleanupResolvedIncidents deletes the stored attachments of incidents resolved longer ago than
+// the configured retention period, so the KV store doesn't grow unbounded. The posts are kept,
+// but are no longer updated.
+func (p *Plugin) cleanupResolvedIncidents() error {
+	retentionDays := p.getConfiguration().ResolvedIncidentRetentionDays
+	if retentionDays <= 0 {
+		return nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	attachments, err := p.kvstore.GetIncidentAttachments()
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident attachments")
+	}
+
+	for _, attachment := range attachments {
+		if attachment.Incident.Status != client.StatusResolved || attachment.ResolvedAt.IsZero() {
+			continue
+		}
+
+		if attachment.ResolvedAt.After(cutoff) {
+			continue
+		}
+
+		if err := p.kvstore.DeleteIncidentAttachment(attachment.ID); err != nil {
+			p.API.LogWarn("Failed to delete incident attachment", "incident_id", attachment.ID, "error", err.Error())
+			continue
+		}
+		p.API.LogDebug("Deleted attachment of resolved incident past retention", "incident_id", attachment.ID)
+	}
+
+	return nil
+}
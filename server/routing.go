@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// serviceChannelRoutes are the parsed service channel routes of the configuration
+type serviceChannelRoutes struct {
+	// channels maps service IDs to channel IDs or names
+	channels map[string]string
+
+	// problems describes the lines that could not be parsed
+	problems []string
+}
+
+// parseServiceChannelRoutes parses routing rules given as one SERVICE_ID=channel rule per line.
+// Blank lines and lines starting with # are skipped.
+func parseServiceChannelRoutes(value string) serviceChannelRoutes {
+	channels, problems := parseServiceRules(value, "SERVICE_ID=channel", func(channel string) string {
+		return strings.TrimPrefix(channel, "~")
+	})
+
+	return serviceChannelRoutes{channels: channels, problems: problems}
+}
+
+// parseServiceRules parses one SERVICE_ID=value rule per line, cleaning each value, and describes
+// the lines that could not be parsed with the expected format. Blank lines and lines starting
+// with # are skipped.
+func parseServiceRules(value, format string, clean func(string) string) (map[string]string, []string) {
+	rules := map[string]string{}
+	var problems []string
+
+	for i, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		serviceID, ruleValue, ok := strings.Cut(line, "=")
+		serviceID = strings.TrimSpace(serviceID)
+		ruleValue = clean(strings.TrimSpace(ruleValue))
+		if !ok || serviceID == "" || ruleValue == "" {
+			problems = append(problems, fmt.Sprintf("line %d: expected %s, got %q", i+1, format, line))
+			continue
+		}
+
+		rules[serviceID] = ruleValue
+	}
+
+	return rules, problems
+}
+
+// getRoutedChannelID gets the channel a service's incidents are routed to, by a route managed with
+// the route command or else by the configuration. It returns false if the service has no route or
+// the routed channel can't be found. Subscriptions take precedence over both.
+func (p *Plugin) getRoutedChannelID(serviceID string) (string, bool) {
+	route, err := p.kvstore.GetChannelRoute(serviceID)
+	if err != nil {
+		p.API.LogWarn("Failed to get channel route, using configured routes", "service_id", serviceID, "error", err.Error())
+	} else if route != nil {
+		return route.ChannelID, true
+	}
+
+	channel, ok := parseServiceChannelRoutes(p.getConfiguration().ServiceChannelRoutes).channels[serviceID]
+	if !ok {
+		return "", false
+	}
+
+	channelID, err := p.findChannelID(channel)
+	if err != nil {
+		p.API.LogWarn("Failed to find routed channel, using default channel", "service_id", serviceID, "channel", channel, "error", err.Error())
+		return "", false
+	}
+
+	return channelID, true
+}
+
+// parseServiceRoutingKeys parses the Events API v2 routing keys of services, given as one
+// SERVICE_ID=routing_key rule per line, and describes the lines that could not be parsed
+func parseServiceRoutingKeys(value string) (map[string]string, []string) {
+	return parseServiceRules(value, "SERVICE_ID=routing_key", strings.TrimSpace)
+}
+
+// getServiceRoutingKey gets the Events API v2 routing key of a service from the configuration.
+// It returns false if the service has no routing key.
+func (p *Plugin) getServiceRoutingKey(serviceID string) (string, bool) {
+	keys, _ := parseServiceRoutingKeys(p.getConfiguration().ServiceRoutingKeys)
+	routingKey, ok := keys[serviceID]
+	return routingKey, ok
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServiceChannelRoutes(t *testing.T) {
+	t.Run("valid rules", func(t *testing.T) {
+		routes := parseServiceChannelRoutes("PABC123=payments-oncall\n\n# storage team\n PDEF456 = ~storage \n")
+		require.Empty(t, routes.problems)
+
+		assert.Equal(t, map[string]string{
+			"PABC123": "payments-oncall",
+			"PDEF456": "storage",
+		}, routes.channels)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		routes := parseServiceChannelRoutes("PABC123\n=alerts\nPDEF456=\nPGHI789=ops")
+		assert.Len(t, routes.problems, 3)
+		assert.Equal(t, map[string]string{"PGHI789": "ops"}, routes.channels)
+	})
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// Maximum length of a service description shown on incident posts
+const maxServiceDescriptionLength = 200
+
+var urlPattern = regexp.MustCompile(`https?://[^\s)>\]]+`)
+
+// getServiceInfo gets a service's details, cached to avoid a PagerDuty request per incident post
+func (p *Plugin) getServiceInfo(serviceID string) *pagerduty.Service {
+	service, err := p.kvstore.GetCachedService(serviceID)
+	if err != nil {
+		p.API.LogWarn("Failed to get cached service", "service_id", serviceID, "error", err.Error())
+	}
+	if service != nil {
+		return service
+	}
+
+	service, err = p.getPagerDutyClient().GetService(serviceID)
+	if err != nil {
+		p.API.LogWarn("Failed to get service", "service_id", serviceID, "error", err.Error())
+		return nil
+	}
+
+	if err := p.kvstore.SaveCachedService(service); err != nil {
+		p.API.LogWarn("Failed to cache service", "service_id", serviceID, "error", err.Error())
+	}
+
+	return service
+}
+
+// getEscalationPolicy gets an escalation policy with its levels, from the cache if possible.
+// It returns nil if the policy can't be fetched.
+func (p *Plugin) getEscalationPolicy(policyID string) *pagerduty.EscalationPolicy {
+	policy, err := p.kvstore.GetCachedEscalationPolicy(policyID)
+	if err != nil {
+		p.API.LogWarn("Failed to get cached escalation policy", "escalation_policy_id", policyID, "error", err.Error())
+	}
+	if policy != nil {
+		return policy
+	}
+
+	policy, err = p.getPagerDutyClient().GetEscalationPolicy(policyID)
+	if err != nil {
+		p.API.LogWarn("Failed to get escalation policy", "escalation_policy_id", policyID, "error", err.Error())
+		return nil
+	}
+
+	if err := p.kvstore.SaveCachedEscalationPolicy(policy); err != nil {
+		p.API.LogWarn("Failed to cache escalation policy", "escalation_policy_id", policyID, "error", err.Error())
+	}
+
+	return policy
+}
+
+// formatServiceAbout formats a short "About this service" line with the service's description,
+// teams and escalation policy, and a runbook link if the description has one
+func formatServiceAbout(service *pagerduty.Service, t i18n.TranslateFunc) string {
+	if service == nil {
+		return ""
+	}
+
+	var parts []string
+
+	description := strings.Join(strings.Fields(service.Description), " ")
+	if runes := []rune(description); len(runes) > maxServiceDescriptionLength {
+		description = string(runes[:maxServiceDescriptionLength]) + "…"
+	}
+	if description != "" {
+		parts = append(parts, description)
+	}
+
+	if len(service.Teams) > 0 {
+		teams := make([]string, 0, len(service.Teams))
+		for _, team := range service.Teams {
+			teams = append(teams, team.Name)
+		}
+		parts = append(parts, t("incident.about.team", map[string]interface{}{"Teams": strings.Join(teams, ", ")}))
+	}
+
+	if service.EscalationPolicy != nil && service.EscalationPolicy.Name != "" {
+		parts = append(parts, t("incident.about.escalation_policy", map[string]interface{}{"Policy": service.EscalationPolicy.Name}))
+	}
+
+	if runbook := findRunbookURL(service.Description); runbook != "" {
+		parts = append(parts, fmt.Sprintf("[%s](%s)", t("incident.about.runbook"), runbook))
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// findRunbookURL finds a runbook link in a service description: a URL on a line
+// mentioning a runbook, or a URL that looks like a runbook itself
+func findRunbookURL(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		lineMentionsRunbook := strings.Contains(strings.ToLower(line), "runbook")
+		for _, url := range urlPattern.FindAllString(line, -1) {
+			if lineMentionsRunbook || strings.Contains(strings.ToLower(url), "runbook") {
+				return url
+			}
+		}
+	}
+
+	return ""
+}
+
+// formatEscalationPolicy formats an escalation policy as a link to PagerDuty if its URL is known
+func formatEscalationPolicy(policy pagerduty.EscalationPolicy) string {
+	if policy.HTMLURL == "" {
+		return policy.Name
+	}
+	return fmt.Sprintf("[%s](%s)", policy.Name, policy.HTMLURL)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// shiftReminderLeadTimes parses the configured comma-separated lead times of shift reminders,
+// sorted from shortest to longest
+func (c *configuration) shiftReminderLeadTimes() ([]time.Duration, error) {
+	var leadTimes []time.Duration
+	for _, value := range strings.Split(c.ShiftReminderLeadTimes, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		leadTime, err := time.ParseDuration(value)
+		if err != nil || leadTime <= 0 {
+			return nil, errors.Errorf("invalid shift reminder lead time %s, use durations such as 1h or 24h", value)
+		}
+		leadTimes = append(leadTimes, leadTime)
+	}
+
+	slices.Sort(leadTimes)
+	return leadTimes, nil
+}
+
+// checkShiftReminders DMs users before their on-call shifts start. A shift that is already
+// closer than a lead time gets only the reminder of the shortest lead time it is within.
+func (p *Plugin) checkShiftReminders() error {
+	leadTimes, err := p.getConfiguration().shiftReminderLeadTimes()
+	if err != nil {
+		return err
+	}
+	if len(leadTimes) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	params := url.Values{}
+	params.Set("since", now.UTC().Format(time.RFC3339))
+	params.Set("until", now.Add(leadTimes[len(leadTimes)-1]).UTC().Format(time.RFC3339))
+
+	onCalls, err := p.getJobPagerDutyClient().ListOnCalls(params)
+	if err != nil {
+		return errors.Wrap(err, "failed to list on-calls")
+	}
+
+	for _, onCall := range onCalls {
+		// Only scheduled shifts that haven't started yet get reminders
+		if onCall.Schedule == nil || onCall.Start == nil || !onCall.Start.After(now) {
+			continue
+		}
+
+		index := slices.IndexFunc(leadTimes, func(leadTime time.Duration) bool {
+			return !onCall.Start.Add(-leadTime).After(now)
+		})
+		if index < 0 {
+			continue
+		}
+
+		// The same shift is listed once per escalation level it covers
+		reminderKey := fmt.Sprintf("%s-%s-%d-%s", onCall.User.ID, onCall.Schedule.ID, onCall.Start.Unix(), leadTimes[index])
+		sent, err := p.kvstore.MarkShiftReminderSent(reminderKey)
+		if err != nil {
+			p.API.LogWarn("Failed to mark shift reminder as sent", "pd_user_id", onCall.User.ID, "error", err.Error())
+			continue
+		}
+		if !sent {
+			continue
+		}
+
+		user, err := p.getMattermostUser(onCall.User)
+		if err != nil {
+			p.API.LogWarn("Failed to find Mattermost user for on-call shift", "pd_user_id", onCall.User.ID, "error", err.Error())
+			continue
+		}
+		if user == nil {
+			continue
+		}
+
+		loc := timezone.ForUser(user)
+		t := i18n.ForUser(user)
+		end := t("shift_reminder.until_further_notice")
+		if onCall.End != nil {
+			end = t("shift_reminder.until", map[string]interface{}{"End": timezone.Format(*onCall.End, loc)})
+		}
+		message := t("shift_reminder.message", map[string]interface{}{
+			"Schedule": onCall.Schedule.Name,
+			"URL":      onCall.Schedule.HTMLURL,
+			"In":       formatLeadTime(onCall.Start.Sub(now), t),
+			"Start":    timezone.Format(*onCall.Start, loc),
+			"End":      end,
+		})
+
+		channel, appErr := p.API.GetDirectChannel(p.botUserID, user.Id)
+		if appErr != nil {
+			p.API.LogWarn("Failed to get direct channel", "user_id", user.Id, "error", appErr.Error())
+			continue
+		}
+
+		if _, appErr := p.API.CreatePost(&model.Post{
+			UserId:    p.botUserID,
+			ChannelId: channel.Id,
+			Message:   message,
+		}); appErr != nil {
+			p.API.LogWarn("Failed to send shift reminder", "user_id", user.Id, "error", appErr.Error())
+		}
+	}
+
+	return nil
+}
+
+// formatLeadTime formats the time until a shift starts in days, hours or minutes
+func formatLeadTime(d time.Duration, t i18n.TranslateFunc) string {
+	switch {
+	case d >= 48*time.Hour:
+		return t("shift_reminder.days", map[string]interface{}{"Count": int(d.Round(24*time.Hour).Hours() / 24)})
+	case d >= 90*time.Minute:
+		return t("shift_reminder.hours", map[string]interface{}{"Count": int(d.Round(time.Hour).Hours())})
+	default:
+		return t("shift_reminder.minutes", map[string]interface{}{"Count": max(int(d.Round(time.Minute).Minutes()), 1)})
+	}
+}
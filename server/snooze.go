@@ -0,0 +1,145 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+// expiredSnoozeRetryPeriod is how long after a snooze ended its handling is retried
+const expiredSnoozeRetryPeriod = 24 * time.Hour
+
+// snoozeOptions returns the durations offered by the snooze select on acknowledged incident posts
+func snoozeOptions(t i18n.TranslateFunc) []*model.PostActionOptions {
+	return []*model.PostActionOptions{
+		{Text: t("incident.snooze.15m"), Value: "15m"},
+		{Text: t("incident.snooze.1h"), Value: "1h"},
+		{Text: t("incident.snooze.4h"), Value: "4h"},
+		{Text: t("incident.snooze.24h"), Value: "24h"},
+	}
+}
+
+// checkExpiredSnoozes notifies users whose snoozed incidents returned to triggered
+// when the snooze ended, so snoozes don't silently turn into forgotten incidents.
+func (p *Plugin) checkExpiredSnoozes() error {
+	snoozes, err := p.kvstore.GetSnoozes()
+	if err != nil {
+		return errors.Wrap(err, "failed to get snoozes")
+	}
+
+	now := time.Now()
+	for _, snooze := range snoozes {
+		if snooze.Until.After(now) {
+			continue
+		}
+
+		// Give up on snoozes of incidents that are gone, or that kept failing for too long after
+		// they ended, instead of retrying them on every run
+		if err := p.handleExpiredSnooze(snooze); errors.Is(err, client.ErrIncidentNotFound) {
+			p.API.LogInfo("Dropping snooze of missing incident", "incident_id", snooze.IncidentID)
+		} else if err != nil && now.Sub(snooze.Until) < expiredSnoozeRetryPeriod {
+			p.API.LogWarn("Failed to handle expired snooze", "incident_id", snooze.IncidentID, "error", err.Error())
+			continue
+		} else if err != nil {
+			p.API.LogWarn("Dropping expired snooze after repeated failures", "incident_id", snooze.IncidentID, "error", err.Error())
+		}
+
+		if err := p.kvstore.DeleteSnooze(snooze.IncidentID); err != nil {
+			p.API.LogWarn("Failed to delete snooze", "incident_id", snooze.IncidentID, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// handleExpiredSnooze posts a thread reply and DMs the snoozer if the incident is triggered again
+func (p *Plugin) handleExpiredSnooze(snooze *kvstore.Snooze) error {
+	incident, err := p.getJobPagerDutyClient().GetIncident(snooze.IncidentID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get incident")
+	}
+
+	// Acknowledged again or resolved in the meantime, nothing was forgotten
+	if incident.Status != client.StatusTriggered {
+		return nil
+	}
+
+	data := map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL, "Title": incident.Title}
+
+	attachment, err := p.getIncidentAttachment(incident.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to get incident attachment", "incident_id", incident.ID, "error", err.Error())
+	}
+
+	if attachment != nil && attachment.PostID != "" {
+		if err := p.updateIncidentPost(*incident, attachment); err != nil {
+			p.API.LogWarn("Failed to update incident post", "incident_id", incident.ID, "error", err.Error())
+		}
+
+		if _, appErr := p.API.CreatePost(&model.Post{
+			UserId:    p.botUserID,
+			ChannelId: attachment.ChannelID,
+			RootId:    attachment.PostID,
+			Message:   p.displayLanguage()("thread.snooze_ended", data),
+		}); appErr != nil {
+			p.API.LogWarn("Failed to post snooze reply", "incident_id", incident.ID, "error", appErr.Error())
+		}
+	}
+
+	channel, appErr := p.API.GetDirectChannel(p.botUserID, snooze.UserID)
+	if appErr != nil {
+		return errors.New("failed to get direct channel: " + appErr.Error())
+	}
+
+	if _, appErr := p.API.CreatePost(&model.Post{
+		UserId:    p.botUserID,
+		ChannelId: channel.Id,
+		Message:   p.translate(snooze.UserID)("thread.snooze_ended", data),
+	}); appErr != nil {
+		return errors.New("failed to notify snoozer: " + appErr.Error())
+	}
+
+	return nil
+}
+
+// performSnooze snoozes an incident for the duration picked on its post, and tracks the
+// snooze so the user is notified if the incident is still open when the snooze ends
+func (p *Plugin) performSnooze(w http.ResponseWriter, pdClient client.PDClient, incidentID, durationValue string, user *model.User) {
+	duration, err := time.ParseDuration(durationValue)
+	if err != nil || duration <= 0 {
+		http.Error(w, "Invalid snooze duration", http.StatusBadRequest)
+		return
+	}
+
+	t := i18n.ForUser(user)
+	var text string
+	if _, err := pdClient.SnoozeIncident(incidentID, duration, user.Email); errors.Is(err, client.ErrNotAcknowledged) {
+		text = t("command.snooze.error.not_acknowledged")
+	} else if err != nil {
+		p.API.LogError("Failed to snooze incident", "error", err.Error())
+		text = t("action.error.snooze", map[string]interface{}{"Error": err.Error()})
+	} else {
+		until := time.Now().Add(duration)
+		text = t("action.incident.snoozed", map[string]interface{}{"Until": timezone.Format(until, timezone.ForUser(user))})
+
+		if err := p.kvstore.SaveSnooze(&kvstore.Snooze{
+			IncidentID: incidentID,
+			UserID:     user.Id,
+			Until:      until,
+		}); err != nil {
+			p.API.LogWarn("Failed to save snooze", "incident_id", incidentID, "error", err.Error())
+			text = t("command.snooze.error.track", map[string]interface{}{"Error": err.Error()})
+		}
+	}
+
+	p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+		EphemeralText: text,
+	})
+}
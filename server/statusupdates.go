@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// Name of the message element of the status update dialog
+const statusUpdateDialogMessage = "message"
+
+// performOpenStatusUpdateDialog opens the dialog to publish a status update of an incident from
+// its post
+func (p *Plugin) performOpenStatusUpdateDialog(w http.ResponseWriter, incidentID, triggerID string, t i18n.TranslateFunc) {
+	if appErr := p.API.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/incidents/status-updates", pluginID),
+		Dialog: model.Dialog{
+			Title:       t("dialog.status_update.title"),
+			SubmitLabel: t("dialog.status_update.submit"),
+			State:       incidentID,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: t("dialog.status_update.message"),
+					Name:        statusUpdateDialogMessage,
+					Type:        "textarea",
+					MaxLength:   noteDialogMaxLength,
+					HelpText:    t("dialog.status_update.help"),
+				},
+			},
+		},
+	}); appErr != nil {
+		p.API.LogError("Failed to open status update dialog", "error", appErr.Error())
+		http.Error(w, "Failed to open dialog", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&model.PostActionIntegrationResponse{}); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
+
+// handleStatusUpdateDialog handles the status update dialog, publishing the status update in
+// PagerDuty and posting it in the incident thread
+func (p *Plugin) handleStatusUpdateDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+	t := i18n.ForUser(user)
+
+	incidentID := request.State
+	message, _ := request.Submission[statusUpdateDialogMessage].(string)
+	if strings.TrimSpace(message) == "" {
+		p.writeDialogError(w, t("dialog.status_update.error.empty"))
+		return
+	}
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	statusUpdate, err := pdClient.CreateStatusUpdate(incidentID, message, user.Email)
+	if err != nil {
+		p.API.LogError("Failed to publish status update", "incident_id", incidentID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.status_update.error.publish", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	attachment, err := p.getIncidentAttachment(incidentID)
+	switch {
+	case err != nil:
+		p.API.LogWarn("Failed to get incident attachment", "incident_id", incidentID, "error", err.Error())
+	case attachment == nil || attachment.Suppressed || attachment.PostID == "":
+		p.API.LogDebug("Not posting status update of an incident without a post", "incident_id", incidentID)
+	default:
+		if err := p.postIncidentStatusUpdate(attachment, statusUpdate.ID, "@"+user.Username, message); err != nil {
+			p.API.LogWarn("Failed to post status update", "incident_id", incidentID, "error", err.Error())
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// postLatestStatusUpdate posts the latest status update of an incident, published in PagerDuty,
+// as a reply in the thread of the incident's post. It returns false if the status update could
+// not be fetched.
+func (p *Plugin) postLatestStatusUpdate(incidentID string, attachment *pagerduty.PostAttachment) bool {
+	updates, err := p.getPagerDutyClient().ListIncidentStatusUpdates(incidentID)
+	if err != nil {
+		p.API.LogWarn("Failed to list status updates", "incident_id", incidentID, "error", err.Error())
+		return false
+	}
+	if len(updates) == 0 {
+		return false
+	}
+
+	latest := updates[0]
+	if err := p.postIncidentStatusUpdate(attachment, latest.ID, p.formatPagerDutyUser(latest.Sender), latest.Message); err != nil {
+		p.API.LogWarn("Failed to post status update", "incident_id", incidentID, "error", err.Error())
+	}
+
+	return true
+}
+
+// postIncidentStatusUpdate posts a status update as a reply in the thread of the incident's post,
+// unless it was already posted
+func (p *Plugin) postIncidentStatusUpdate(attachment *pagerduty.PostAttachment, statusUpdateID, author, message string) error {
+	if statusUpdateID != "" {
+		posted, err := p.kvstore.MarkStatusUpdatePosted(statusUpdateID)
+		if err != nil {
+			return errors.Wrap(err, "failed to mark status update as posted")
+		}
+		if !posted {
+			p.API.LogDebug("Ignoring status update already posted", "status_update_id", statusUpdateID)
+			return nil
+		}
+	}
+
+	quoted := "> " + strings.ReplaceAll(strings.TrimSpace(message), "\n", "\n> ")
+	if _, appErr := p.API.CreatePost(&model.Post{
+		UserId:    p.botUserID,
+		ChannelId: attachment.ChannelID,
+		RootId:    attachment.PostID,
+		Message:   p.displayLanguage()("thread.status_update", map[string]interface{}{"Author": author, "Message": quoted}),
+	}); appErr != nil {
+		return errors.New("failed to post status update: " + appErr.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyChannelID = "channel_id-"
+
+	// Cached channel IDs are resolved again after this long, in case a channel was renamed
+	channelIDCacheExpiry = 24 * time.Hour
+)
+
+// GetCachedChannelID gets the cached ID of a channel configured by name, returning an empty
+// string if it isn't cached
+func (kv Client) GetCachedChannelID(channelName string) (string, error) {
+	var channelID string
+	if err := kv.client.KV.Get(keyChannelID+channelName, &channelID); err != nil {
+		return "", errors.Wrap(err, "failed to get cached channel ID")
+	}
+	return channelID, nil
+}
+
+// SaveCachedChannelID caches the ID of a channel configured by name
+func (kv Client) SaveCachedChannelID(channelName, channelID string) error {
+	if _, err := kv.client.KV.Set(keyChannelID+channelName, channelID, pluginapi.SetExpiry(channelIDCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached channel ID")
+	}
+	return nil
+}
+
+// DeleteCachedChannelIDs deletes all cached channel IDs
+func (kv Client) DeleteCachedChannelIDs() error {
+	keys, err := kv.listKeysWithPrefix(keyChannelID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list cached channel IDs")
+	}
+
+	for _, key := range keys {
+		if err := kv.client.KV.Delete(key); err != nil {
+			return errors.Wrap(err, "failed to delete cached channel ID")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyDigestPosted = "digest_posted-"
+
+	// Digest markers are kept past the day they cover so a digest is never posted twice
+	digestPostedExpiry = 48 * time.Hour
+)
+
+// MarkDigestPosted records that the daily digest of a day is being posted, returning false if
+// it was already posted, so that only one server of a cluster posts it
+func (kv Client) MarkDigestPosted(day string) (bool, error) {
+	saved, err := kv.client.KV.Set(keyDigestPosted+day, true, pluginapi.SetAtomic(nil), pluginapi.SetExpiry(digestPostedExpiry))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark digest as posted")
+	}
+	return saved, nil
+}
@@ -0,0 +1,98 @@
+package kvstore
+
+import (
+	"encoding/json"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	keyIncidentAttachment = "incident_attachments:"
+
+	// Number of attempts to update an incident attachment changed concurrently
+	incidentAttachmentRetries = 5
+)
+
+// GetIncidentAttachment gets the post attachment of an incident, returning nil if it doesn't exist
+func (kv Client) GetIncidentAttachment(incidentID string) (*pagerduty.PostAttachment, error) {
+	var attachment *pagerduty.PostAttachment
+	if err := kv.client.KV.Get(keyIncidentAttachment+incidentID, &attachment); err != nil {
+		return nil, errors.Wrap(err, "failed to get incident attachment")
+	}
+	return attachment, nil
+}
+
+// GetIncidentAttachments gets the post attachments of all incidents
+func (kv Client) GetIncidentAttachments() ([]*pagerduty.PostAttachment, error) {
+	keys, err := kv.listKeysWithPrefix(keyIncidentAttachment)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list incident attachments")
+	}
+
+	attachments := make([]*pagerduty.PostAttachment, 0, len(keys))
+	for _, key := range keys {
+		var attachment *pagerduty.PostAttachment
+		if err := kv.client.KV.Get(key, &attachment); err != nil {
+			return nil, errors.Wrap(err, "failed to get incident attachment")
+		}
+		if attachment != nil {
+			attachments = append(attachments, attachment)
+		}
+	}
+
+	return attachments, nil
+}
+
+// SaveIncidentAttachment saves the post attachment of an incident
+func (kv Client) SaveIncidentAttachment(attachment *pagerduty.PostAttachment) error {
+	if _, err := kv.client.KV.Set(keyIncidentAttachment+attachment.ID, attachment); err != nil {
+		return errors.Wrap(err, "failed to save incident attachment")
+	}
+	return nil
+}
+
+// UpdateIncidentAttachment applies a change to the stored post attachment of an incident with
+// a compare-and-set, reapplying the change to the latest attachment if it was changed
+// concurrently. It returns the saved attachment, or nil if the incident has no attachment.
+func (kv Client) UpdateIncidentAttachment(incidentID string, change func(*pagerduty.PostAttachment)) (*pagerduty.PostAttachment, error) {
+	key := keyIncidentAttachment + incidentID
+	for i := 0; i < incidentAttachmentRetries; i++ {
+		var oldValue []byte
+		if err := kv.client.KV.Get(key, &oldValue); err != nil {
+			return nil, errors.Wrap(err, "failed to get incident attachment")
+		}
+		if len(oldValue) == 0 {
+			return nil, nil
+		}
+
+		var attachment *pagerduty.PostAttachment
+		if err := json.Unmarshal(oldValue, &attachment); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal incident attachment")
+		}
+		if attachment == nil {
+			return nil, nil
+		}
+		change(attachment)
+
+		saved, err := kv.client.KV.Set(key, attachment, pluginapi.SetAtomic(oldValue))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to save incident attachment")
+		}
+		if saved {
+			return attachment, nil
+		}
+	}
+
+	return nil, errors.New("failed to update incident attachment: too many concurrent updates")
+}
+
+// DeleteIncidentAttachment deletes the post attachment of an incident
+func (kv Client) DeleteIncidentAttachment(incidentID string) error {
+	if err := kv.client.KV.Delete(keyIncidentAttachment + incidentID); err != nil {
+		return errors.Wrap(err, "failed to delete incident attachment")
+	}
+	return nil
+}
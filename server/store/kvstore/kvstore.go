@@ -1,6 +1,127 @@
 package kvstore
 
+import (
+	"time"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
 type KVStore interface {
 	// Define your methods here. This package is used to access the KVStore pluginapi methods.
 	GetTemplateData(userID string) (string, error)
+
+	// Incident post attachments
+	GetIncidentAttachment(incidentID string) (*pagerduty.PostAttachment, error)
+	GetIncidentAttachments() ([]*pagerduty.PostAttachment, error)
+	SaveIncidentAttachment(attachment *pagerduty.PostAttachment) error
+	UpdateIncidentAttachment(incidentID string, change func(*pagerduty.PostAttachment)) (*pagerduty.PostAttachment, error)
+	DeleteIncidentAttachment(incidentID string) error
+
+	// Open incidents posted in each channel
+	GetOpenIncidents(channelID string) (OpenIncidents, error)
+	AddOpenIncident(channelID, incidentID, postID string) error
+	RemoveOpenIncident(channelID, incidentID string) error
+
+	// Version of the schema of the stored data
+	GetSchemaVersion() (int, error)
+	SaveSchemaVersion(version int) error
+
+	// Webhook deliveries
+	MarkWebhookEventSeen(eventID string) (bool, error)
+	UnmarkWebhookEventSeen(eventID string) error
+	SaveWebhookDeadLetter(eventID string, payload []byte) error
+	IncrementWebhookStat(at time.Time, counter string) error
+	GetWebhookStats(since time.Time) (WebhookStats, error)
+
+	// Incident notes, status updates and responder requests posted in incident threads
+	MarkNotePosted(noteID string) (bool, error)
+	MarkStatusUpdatePosted(statusUpdateID string) (bool, error)
+	MarkResponderRequestPosted(incidentID, targetID string) error
+	IsResponderRequestPosted(incidentID, targetID string) (bool, error)
+
+	// User settings
+	GetUserSettings(userID string) (*UserSettings, error)
+	SaveUserSettings(userID string, settings *UserSettings) error
+
+	// PagerDuty user API tokens of connected users
+	GetUserToken(userID string) (string, error)
+	GetUserTokens() (map[string]string, error)
+	SaveUserToken(userID, token string) error
+	DeleteUserToken(userID string) error
+
+	// Shift swaps
+	GetShiftSwap(swapID string) (*ShiftSwap, error)
+	SaveShiftSwap(swap *ShiftSwap) error
+	DeleteShiftSwap(swapID string) error
+
+	// Subscriptions
+	GetSubscription(serviceID string) (*Subscription, error)
+	GetSubscriptions() ([]*Subscription, error)
+	SaveSubscription(subscription *Subscription) error
+	DeleteSubscription(serviceID string) error
+
+	// Service channel routes managed with the route command
+	GetChannelRoute(serviceID string) (*ChannelRoute, error)
+	GetChannelRoutes() ([]*ChannelRoute, error)
+	SaveChannelRoute(route *ChannelRoute) error
+	DeleteChannelRoute(serviceID string) error
+
+	// Snoozes
+	GetSnoozes() ([]*Snooze, error)
+	SaveSnooze(snooze *Snooze) error
+	DeleteSnooze(incidentID string) error
+
+	// Service cache
+	GetCachedService(serviceID string) (*pagerduty.Service, error)
+	SaveCachedService(service *pagerduty.Service) error
+	GetCachedPriorities() ([]pagerduty.Priority, error)
+	SaveCachedPriorities(priorities []pagerduty.Priority) error
+	GetCachedEscalationPolicy(policyID string) (*pagerduty.EscalationPolicy, error)
+	SaveCachedEscalationPolicy(policy *pagerduty.EscalationPolicy) error
+	GetCachedIncidentWorkflows() ([]pagerduty.IncidentWorkflow, error)
+	SaveCachedIncidentWorkflows(workflows []pagerduty.IncidentWorkflow) error
+	GetCachedMaintenanceWindows(serviceID string) ([]pagerduty.MaintenanceWindow, error)
+	SaveCachedMaintenanceWindows(serviceID string, windows []pagerduty.MaintenanceWindow) error
+	DeleteCachedMaintenanceWindows(serviceID string) error
+	GetCachedServices() ([]pagerduty.Service, error)
+	SaveCachedServices(services []pagerduty.Service) error
+	GetCachedSchedules() ([]pagerduty.Schedule, error)
+	SaveCachedSchedules(schedules []pagerduty.Schedule) error
+	GetCachedOpenIncidents() ([]pagerduty.Incident, error)
+	SaveCachedOpenIncidents(incidents []pagerduty.Incident) error
+	GetCachedPagerDutyUser(pdUserID string) (*pagerduty.User, error)
+	SaveCachedPagerDutyUser(user *pagerduty.User) error
+	GetCachedPolicyOnCalls(policyID string) ([]pagerduty.OnCall, error)
+	SaveCachedPolicyOnCalls(policyID string, onCalls []pagerduty.OnCall) error
+
+	// Channel ID cache
+	GetCachedChannelID(channelName string) (string, error)
+	SaveCachedChannelID(channelName, channelID string) error
+	DeleteCachedChannelIDs() error
+
+	// Mattermost to PagerDuty user mappings, matched by email or set by an admin
+	GetUserMappingByMattermostID(userID string) (*UserMapping, error)
+	GetUserMappingByPagerDutyID(pdUserID string) (*UserMapping, error)
+	SaveUserMapping(mapping *UserMapping) error
+	GetManualUserMappings() ([]*UserMapping, error)
+	SaveManualUserMapping(mapping *UserMapping) error
+	DeleteManualUserMapping(userID string) error
+
+	// Daily digests
+	MarkDigestPosted(day string) (bool, error)
+
+	// On-call shift reminders
+	MarkShiftReminderSent(reminderKey string) (bool, error)
+
+	// Pages sent with the notify command, for rate limiting
+	ReserveRecentPage(userID string, at time.Time, limit int, window time.Duration) (bool, error)
+	ReleaseRecentPage(userID string, at time.Time, window time.Duration) error
+
+	// Maintenance windows
+	GetActiveMaintenanceWindows() (map[string]pagerduty.MaintenanceWindow, error)
+	SaveActiveMaintenanceWindows(windows map[string]pagerduty.MaintenanceWindow) error
+
+	// All plugin data, for resetting the plugin
+	ListKeys() ([]string, error)
+	DeleteAll() error
 }
@@ -0,0 +1,26 @@
+package kvstore
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const keyActiveMaintenanceWindows = "active_maintenance_windows"
+
+// GetActiveMaintenanceWindows gets the maintenance windows announced as started, keyed by ID
+func (kv Client) GetActiveMaintenanceWindows() (map[string]pagerduty.MaintenanceWindow, error) {
+	windows := map[string]pagerduty.MaintenanceWindow{}
+	if err := kv.client.KV.Get(keyActiveMaintenanceWindows, &windows); err != nil {
+		return nil, errors.Wrap(err, "failed to get active maintenance windows")
+	}
+	return windows, nil
+}
+
+// SaveActiveMaintenanceWindows saves the maintenance windows announced as started
+func (kv Client) SaveActiveMaintenanceWindows(windows map[string]pagerduty.MaintenanceWindow) error {
+	if _, err := kv.client.KV.Set(keyActiveMaintenanceWindows, windows); err != nil {
+		return errors.Wrap(err, "failed to save active maintenance windows")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyPostedNote         = "posted_note-"
+	keyPostedStatusUpdate = "posted_status_update-"
+	keyPostedResponder    = "posted_responder-"
+
+	// Posted note IDs are remembered this long, covering the delay of the webhook of a note
+	postedNoteExpiry = 24 * time.Hour
+)
+
+// MarkNotePosted records an incident note as posted in its incident thread. It returns false
+// if the note was already posted, as notes added from Mattermost also arrive by webhook.
+func (kv Client) MarkNotePosted(noteID string) (bool, error) {
+	saved, err := kv.client.KV.Set(keyPostedNote+noteID, true, pluginapi.SetAtomic(nil), pluginapi.SetExpiry(postedNoteExpiry))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark note as posted")
+	}
+	return saved, nil
+}
+
+// MarkStatusUpdatePosted records an incident status update as posted in its incident thread. It
+// returns false if the status update was already posted, as updates published from Mattermost
+// also arrive by webhook.
+func (kv Client) MarkStatusUpdatePosted(statusUpdateID string) (bool, error) {
+	saved, err := kv.client.KV.Set(keyPostedStatusUpdate+statusUpdateID, true, pluginapi.SetAtomic(nil), pluginapi.SetExpiry(postedNoteExpiry))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark status update as posted")
+	}
+	return saved, nil
+}
+
+// MarkResponderRequestPosted records that the request of a user or escalation policy to respond
+// to an incident was posted in its incident thread, as requests made from Mattermost also arrive
+// by webhook
+func (kv Client) MarkResponderRequestPosted(incidentID, targetID string) error {
+	if _, err := kv.client.KV.Set(keyPostedResponder+incidentID+"-"+targetID, true, pluginapi.SetExpiry(postedNoteExpiry)); err != nil {
+		return errors.Wrap(err, "failed to mark responder request as posted")
+	}
+	return nil
+}
+
+// IsResponderRequestPosted tells whether the request of a user or escalation policy to respond
+// to an incident was already posted in its incident thread
+func (kv Client) IsResponderRequestPosted(incidentID, targetID string) (bool, error) {
+	var posted bool
+	if err := kv.client.KV.Get(keyPostedResponder+incidentID+"-"+targetID, &posted); err != nil {
+		return false, errors.Wrap(err, "failed to get posted responder request")
+	}
+	return posted, nil
+}
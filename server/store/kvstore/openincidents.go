@@ -0,0 +1,87 @@
+package kvstore
+
+import (
+	"encoding/json"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyOpenIncidents = "open_incidents-"
+
+	// Number of attempts to update an open incident index changed concurrently
+	openIncidentsRetries = 5
+)
+
+// OpenIncidents maps the IDs of the open incidents posted in a channel to the IDs of their posts
+type OpenIncidents map[string]string
+
+// GetOpenIncidents gets the open incidents posted in a channel
+func (kv Client) GetOpenIncidents(channelID string) (OpenIncidents, error) {
+	incidents := OpenIncidents{}
+	if err := kv.client.KV.Get(keyOpenIncidents+channelID, &incidents); err != nil {
+		return nil, errors.Wrap(err, "failed to get open incidents")
+	}
+	return incidents, nil
+}
+
+// AddOpenIncident adds an open incident to the index of the channel it is posted in
+func (kv Client) AddOpenIncident(channelID, incidentID, postID string) error {
+	return kv.updateOpenIncidents(channelID, func(incidents OpenIncidents) bool {
+		if incidents[incidentID] == postID {
+			return false
+		}
+		incidents[incidentID] = postID
+		return true
+	})
+}
+
+// RemoveOpenIncident removes an incident from the index of a channel
+func (kv Client) RemoveOpenIncident(channelID, incidentID string) error {
+	return kv.updateOpenIncidents(channelID, func(incidents OpenIncidents) bool {
+		if _, ok := incidents[incidentID]; !ok {
+			return false
+		}
+		delete(incidents, incidentID)
+		return true
+	})
+}
+
+// updateOpenIncidents applies a change to the index of a channel, retrying if the index is
+// changed concurrently. The change returns false if it left the index unchanged.
+func (kv Client) updateOpenIncidents(channelID string, change func(OpenIncidents) bool) error {
+	key := keyOpenIncidents + channelID
+	for i := 0; i < openIncidentsRetries; i++ {
+		var oldValue []byte
+		if err := kv.client.KV.Get(key, &oldValue); err != nil {
+			return errors.Wrap(err, "failed to get open incidents")
+		}
+
+		incidents := OpenIncidents{}
+		if len(oldValue) > 0 {
+			if err := json.Unmarshal(oldValue, &incidents); err != nil {
+				return errors.Wrap(err, "failed to unmarshal open incidents")
+			}
+		}
+		if !change(incidents) {
+			return nil
+		}
+
+		var saved bool
+		var err error
+		if len(incidents) == 0 {
+			saved, err = kv.client.KV.Set(key, nil, pluginapi.SetAtomic(oldValue))
+		} else {
+			saved, err = kv.client.KV.Set(key, incidents, pluginapi.SetAtomic(oldValue))
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to save open incidents")
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return errors.New("failed to update open incidents: too many concurrent updates")
+}
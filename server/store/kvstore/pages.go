@@ -0,0 +1,84 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyRecentPages = "recent_pages-"
+
+	// Number of attempts to update the recent pages of a user
+	recentPagesRetries = 5
+)
+
+// ReserveRecentPage records that a user pages someone at the given time with the notify command,
+// unless they already paged limit people within the rate limit window. The check and the update
+// are a single compare-and-set, so concurrent pages can't both take the last slot. It returns
+// whether the page was recorded.
+func (kv Client) ReserveRecentPage(userID string, at time.Time, limit int, window time.Duration) (bool, error) {
+	reserved := false
+	err := kv.updateRecentPages(userID, window, func(pages []time.Time) []time.Time {
+		var recent []time.Time
+		for _, page := range pages {
+			if page.After(at.Add(-window)) {
+				recent = append(recent, page)
+			}
+		}
+
+		reserved = len(recent) < limit
+		if reserved {
+			recent = append(recent, at)
+		}
+		return recent
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return reserved, nil
+}
+
+// ReleaseRecentPage removes a page recorded with ReserveRecentPage, when paging failed
+func (kv Client) ReleaseRecentPage(userID string, at time.Time, window time.Duration) error {
+	return kv.updateRecentPages(userID, window, func(pages []time.Time) []time.Time {
+		for i, page := range pages {
+			if page.Equal(at) {
+				return append(pages[:i], pages[i+1:]...)
+			}
+		}
+		return pages
+	})
+}
+
+// updateRecentPages applies a change to the recent pages of a user with a compare-and-set,
+// reapplying it to the latest pages if they were changed concurrently
+func (kv Client) updateRecentPages(userID string, window time.Duration, change func([]time.Time) []time.Time) error {
+	key := keyRecentPages + userID
+	for i := 0; i < recentPagesRetries; i++ {
+		var oldValue []byte
+		if err := kv.client.KV.Get(key, &oldValue); err != nil {
+			return errors.Wrap(err, "failed to get recent pages")
+		}
+
+		var pages []time.Time
+		if len(oldValue) > 0 {
+			if err := json.Unmarshal(oldValue, &pages); err != nil {
+				return errors.Wrap(err, "failed to unmarshal recent pages")
+			}
+		}
+
+		saved, err := kv.client.KV.Set(key, change(pages), pluginapi.SetAtomic(oldValue), pluginapi.SetExpiry(window))
+		if err != nil {
+			return errors.Wrap(err, "failed to save recent pages")
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return errors.New("failed to update recent pages: too many concurrent updates")
+}
@@ -0,0 +1,25 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyShiftReminder = "shift_reminder-"
+
+	// Shift reminder markers outlive the longest sensible lead time
+	shiftReminderExpiry = 8 * 24 * time.Hour
+)
+
+// MarkShiftReminderSent records that a shift reminder is being sent, returning false if it
+// was already sent, so that each reminder is sent once across the cluster
+func (kv Client) MarkShiftReminderSent(reminderKey string) (bool, error) {
+	saved, err := kv.client.KV.Set(keyShiftReminder+reminderKey, true, pluginapi.SetAtomic(nil), pluginapi.SetExpiry(shiftReminderExpiry))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark shift reminder as sent")
+	}
+	return saved, nil
+}
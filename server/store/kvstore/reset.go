@@ -0,0 +1,22 @@
+package kvstore
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ListKeys lists all keys stored by the plugin
+func (kv Client) ListKeys() ([]string, error) {
+	keys, err := kv.listKeysWithPrefix("")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list keys")
+	}
+	return keys, nil
+}
+
+// DeleteAll deletes all data stored by the plugin
+func (kv Client) DeleteAll() error {
+	if err := kv.client.KV.DeleteAll(); err != nil {
+		return errors.Wrap(err, "failed to delete all keys")
+	}
+	return nil
+}
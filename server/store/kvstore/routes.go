@@ -0,0 +1,65 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const keyChannelRoute = "channel_route-"
+
+// ChannelRoute routes the incidents of a PagerDuty service to a Mattermost channel. Routes are
+// managed with the route command and take precedence over the routes of the configuration.
+type ChannelRoute struct {
+	ServiceID   string    `json:"service_id"`
+	ServiceName string    `json:"service_name"`
+	ChannelID   string    `json:"channel_id"`
+	CreatorID   string    `json:"creator_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetChannelRoute gets the route of a service, returning nil if there is none
+func (kv Client) GetChannelRoute(serviceID string) (*ChannelRoute, error) {
+	var route *ChannelRoute
+	if err := kv.client.KV.Get(keyChannelRoute+serviceID, &route); err != nil {
+		return nil, errors.Wrap(err, "failed to get channel route")
+	}
+	return route, nil
+}
+
+// GetChannelRoutes gets all routes
+func (kv Client) GetChannelRoutes() ([]*ChannelRoute, error) {
+	keys, err := kv.listKeysWithPrefix(keyChannelRoute)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list channel routes")
+	}
+
+	routes := make([]*ChannelRoute, 0, len(keys))
+	for _, key := range keys {
+		var route *ChannelRoute
+		if err := kv.client.KV.Get(key, &route); err != nil {
+			return nil, errors.Wrap(err, "failed to get channel route")
+		}
+		if route != nil {
+			routes = append(routes, route)
+		}
+	}
+
+	return routes, nil
+}
+
+// SaveChannelRoute saves a route, replacing any existing one for the service
+func (kv Client) SaveChannelRoute(route *ChannelRoute) error {
+	if _, err := kv.client.KV.Set(keyChannelRoute+route.ServiceID, route); err != nil {
+		return errors.Wrap(err, "failed to save channel route")
+	}
+	return nil
+}
+
+// DeleteChannelRoute deletes the route of a service
+func (kv Client) DeleteChannelRoute(serviceID string) error {
+	if err := kv.client.KV.Delete(keyChannelRoute + serviceID); err != nil {
+		return errors.Wrap(err, "failed to delete channel route")
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package kvstore
+
+import (
+	"github.com/pkg/errors"
+)
+
+const keySchemaVersion = "schema_version"
+
+// GetSchemaVersion gets the version of the schema the stored plugin data is in, which is 0 for
+// data stored before the schema was versioned
+func (kv Client) GetSchemaVersion() (int, error) {
+	var version int
+	if err := kv.client.KV.Get(keySchemaVersion, &version); err != nil {
+		return 0, errors.Wrap(err, "failed to get schema version")
+	}
+	return version, nil
+}
+
+// SaveSchemaVersion saves the version of the schema the stored plugin data is in
+func (kv Client) SaveSchemaVersion(version int) error {
+	if _, err := kv.client.KV.Set(keySchemaVersion, version); err != nil {
+		return errors.Wrap(err, "failed to save schema version")
+	}
+	return nil
+}
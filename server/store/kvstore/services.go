@@ -0,0 +1,243 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	keyService          = "service-"
+	keyPriorities       = "priorities"
+	keyEscalationPolicy = "escalation_policy-"
+	keyWorkflows        = "incident_workflows"
+	keyMaintenance      = "maintenance_windows-"
+	keyServiceList      = "services"
+	keyScheduleList     = "schedules"
+	keyOpenIncidentList = "open_incidents"
+	keyPagerDutyUser    = "pagerduty_user-"
+	keyPolicyOnCalls    = "policy_oncalls-"
+
+	// Cached services, priorities, escalation policies and workflows are refreshed from PagerDuty
+	// after this long
+	serviceCacheExpiry = time.Hour
+
+	// Cached maintenance windows are refreshed sooner, as windows are started on short notice
+	maintenanceCacheExpiry = 2 * time.Minute
+
+	// Cached open incidents, suggested by the slash command autocomplete, go stale within minutes
+	openIncidentsCacheExpiry = time.Minute
+
+	// Cached PagerDuty users, looked up for every assignee of every incident update, are refreshed
+	// soon enough to pick up changed contact methods and notification rules
+	pagerDutyUserCacheExpiry = 10 * time.Minute
+
+	// Cached on-calls of escalation policies, offered to reassign incidents to on every render of
+	// an incident post, are refreshed soon enough to follow shift changes
+	onCallsCacheExpiry = 2 * time.Minute
+)
+
+// GetCachedService gets a cached PagerDuty service, returning nil if it isn't cached
+func (kv Client) GetCachedService(serviceID string) (*pagerduty.Service, error) {
+	var service *pagerduty.Service
+	if err := kv.client.KV.Get(keyService+serviceID, &service); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached service")
+	}
+	return service, nil
+}
+
+// SaveCachedService caches a PagerDuty service
+func (kv Client) SaveCachedService(service *pagerduty.Service) error {
+	if _, err := kv.client.KV.Set(keyService+service.ID, service, pluginapi.SetExpiry(serviceCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached service")
+	}
+	return nil
+}
+
+// GetCachedPriorities gets the cached incident priorities, returning nil if they aren't cached
+func (kv Client) GetCachedPriorities() ([]pagerduty.Priority, error) {
+	var priorities []pagerduty.Priority
+	if err := kv.client.KV.Get(keyPriorities, &priorities); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached priorities")
+	}
+	return priorities, nil
+}
+
+// SaveCachedPriorities caches the incident priorities
+func (kv Client) SaveCachedPriorities(priorities []pagerduty.Priority) error {
+	if priorities == nil {
+		priorities = []pagerduty.Priority{}
+	}
+	if _, err := kv.client.KV.Set(keyPriorities, priorities, pluginapi.SetExpiry(serviceCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached priorities")
+	}
+	return nil
+}
+
+// GetCachedEscalationPolicy gets a cached escalation policy, returning nil if it isn't cached
+func (kv Client) GetCachedEscalationPolicy(policyID string) (*pagerduty.EscalationPolicy, error) {
+	var policy *pagerduty.EscalationPolicy
+	if err := kv.client.KV.Get(keyEscalationPolicy+policyID, &policy); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached escalation policy")
+	}
+	return policy, nil
+}
+
+// SaveCachedEscalationPolicy caches an escalation policy
+func (kv Client) SaveCachedEscalationPolicy(policy *pagerduty.EscalationPolicy) error {
+	if _, err := kv.client.KV.Set(keyEscalationPolicy+policy.ID, policy, pluginapi.SetExpiry(serviceCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached escalation policy")
+	}
+	return nil
+}
+
+// GetCachedIncidentWorkflows gets the cached incident workflows, returning nil if they aren't cached
+func (kv Client) GetCachedIncidentWorkflows() ([]pagerduty.IncidentWorkflow, error) {
+	var workflows []pagerduty.IncidentWorkflow
+	if err := kv.client.KV.Get(keyWorkflows, &workflows); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached incident workflows")
+	}
+	return workflows, nil
+}
+
+// SaveCachedIncidentWorkflows caches the incident workflows
+func (kv Client) SaveCachedIncidentWorkflows(workflows []pagerduty.IncidentWorkflow) error {
+	if workflows == nil {
+		workflows = []pagerduty.IncidentWorkflow{}
+	}
+	if _, err := kv.client.KV.Set(keyWorkflows, workflows, pluginapi.SetExpiry(serviceCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached incident workflows")
+	}
+	return nil
+}
+
+// GetCachedMaintenanceWindows gets the cached ongoing maintenance windows of a service, returning
+// nil if they aren't cached
+func (kv Client) GetCachedMaintenanceWindows(serviceID string) ([]pagerduty.MaintenanceWindow, error) {
+	var windows []pagerduty.MaintenanceWindow
+	if err := kv.client.KV.Get(keyMaintenance+serviceID, &windows); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached maintenance windows")
+	}
+	return windows, nil
+}
+
+// SaveCachedMaintenanceWindows caches the ongoing maintenance windows of a service
+func (kv Client) SaveCachedMaintenanceWindows(serviceID string, windows []pagerduty.MaintenanceWindow) error {
+	if windows == nil {
+		windows = []pagerduty.MaintenanceWindow{}
+	}
+	if _, err := kv.client.KV.Set(keyMaintenance+serviceID, windows, pluginapi.SetExpiry(maintenanceCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached maintenance windows")
+	}
+	return nil
+}
+
+// DeleteCachedMaintenanceWindows deletes the cached maintenance windows of a service, so a window
+// just started takes effect right away
+func (kv Client) DeleteCachedMaintenanceWindows(serviceID string) error {
+	if err := kv.client.KV.Delete(keyMaintenance + serviceID); err != nil {
+		return errors.Wrap(err, "failed to delete cached maintenance windows")
+	}
+	return nil
+}
+
+// GetCachedServices gets the cached list of all services, returning nil if it isn't cached
+func (kv Client) GetCachedServices() ([]pagerduty.Service, error) {
+	var services []pagerduty.Service
+	if err := kv.client.KV.Get(keyServiceList, &services); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached services")
+	}
+	return services, nil
+}
+
+// SaveCachedServices caches the list of all services
+func (kv Client) SaveCachedServices(services []pagerduty.Service) error {
+	if services == nil {
+		services = []pagerduty.Service{}
+	}
+	if _, err := kv.client.KV.Set(keyServiceList, services, pluginapi.SetExpiry(serviceCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached services")
+	}
+	return nil
+}
+
+// GetCachedSchedules gets the cached list of all schedules, returning nil if it isn't cached
+func (kv Client) GetCachedSchedules() ([]pagerduty.Schedule, error) {
+	var schedules []pagerduty.Schedule
+	if err := kv.client.KV.Get(keyScheduleList, &schedules); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached schedules")
+	}
+	return schedules, nil
+}
+
+// SaveCachedSchedules caches the list of all schedules
+func (kv Client) SaveCachedSchedules(schedules []pagerduty.Schedule) error {
+	if schedules == nil {
+		schedules = []pagerduty.Schedule{}
+	}
+	if _, err := kv.client.KV.Set(keyScheduleList, schedules, pluginapi.SetExpiry(serviceCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached schedules")
+	}
+	return nil
+}
+
+// GetCachedOpenIncidents gets the cached list of open incidents, returning nil if it isn't cached
+func (kv Client) GetCachedOpenIncidents() ([]pagerduty.Incident, error) {
+	var incidents []pagerduty.Incident
+	if err := kv.client.KV.Get(keyOpenIncidentList, &incidents); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached open incidents")
+	}
+	return incidents, nil
+}
+
+// SaveCachedOpenIncidents caches the list of open incidents
+func (kv Client) SaveCachedOpenIncidents(incidents []pagerduty.Incident) error {
+	if incidents == nil {
+		incidents = []pagerduty.Incident{}
+	}
+	if _, err := kv.client.KV.Set(keyOpenIncidentList, incidents, pluginapi.SetExpiry(openIncidentsCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached open incidents")
+	}
+	return nil
+}
+
+// GetCachedPagerDutyUser gets a cached PagerDuty user, returning nil if it isn't cached
+func (kv Client) GetCachedPagerDutyUser(pdUserID string) (*pagerduty.User, error) {
+	var user *pagerduty.User
+	if err := kv.client.KV.Get(keyPagerDutyUser+pdUserID, &user); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached PagerDuty user")
+	}
+	return user, nil
+}
+
+// SaveCachedPagerDutyUser caches a PagerDuty user with their contact methods and notification rules
+func (kv Client) SaveCachedPagerDutyUser(user *pagerduty.User) error {
+	if _, err := kv.client.KV.Set(keyPagerDutyUser+user.ID, user, pluginapi.SetExpiry(pagerDutyUserCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached PagerDuty user")
+	}
+	return nil
+}
+
+// GetCachedPolicyOnCalls gets the cached on-calls of an escalation policy, returning nil if they
+// aren't cached
+func (kv Client) GetCachedPolicyOnCalls(policyID string) ([]pagerduty.OnCall, error) {
+	var onCalls []pagerduty.OnCall
+	if err := kv.client.KV.Get(keyPolicyOnCalls+policyID, &onCalls); err != nil {
+		return nil, errors.Wrap(err, "failed to get cached on-calls")
+	}
+	return onCalls, nil
+}
+
+// SaveCachedPolicyOnCalls caches the on-calls of an escalation policy
+func (kv Client) SaveCachedPolicyOnCalls(policyID string, onCalls []pagerduty.OnCall) error {
+	if onCalls == nil {
+		onCalls = []pagerduty.OnCall{}
+	}
+	if _, err := kv.client.KV.Set(keyPolicyOnCalls+policyID, onCalls, pluginapi.SetExpiry(onCallsCacheExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save cached on-calls")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package kvstore
+
+import (
+	"github.com/pkg/errors"
+)
+
+const keyUserSettings = "user_settings-"
+
+// UserSettings holds a user's personal plugin preferences
+type UserSettings struct {
+	// EphemeralResponses shows list/get output only to the invoking user by default
+	EphemeralResponses bool `json:"ephemeral_responses"`
+
+	// NotifyOnAssignment DMs the user when an incident is assigned to them
+	NotifyOnAssignment bool `json:"notify_on_assignment"`
+
+	// NotifyOnHighUrgency DMs the user when a high-urgency incident triggers on a service of
+	// one of their PagerDuty teams
+	NotifyOnHighUrgency bool `json:"notify_on_high_urgency"`
+
+	// NotifyOnEscalation DMs the user when an incident is escalated to them
+	NotifyOnEscalation bool `json:"notify_on_escalation"`
+}
+
+// DefaultUserSettings returns the settings of a user who hasn't changed any
+func DefaultUserSettings() *UserSettings {
+	return &UserSettings{
+		NotifyOnAssignment: true,
+		NotifyOnEscalation: true,
+	}
+}
+
+// GetUserSettings gets a user's settings, returning the defaults if none are saved
+func (kv Client) GetUserSettings(userID string) (*UserSettings, error) {
+	// Settings missing from the saved JSON keep their defaults
+	settings := DefaultUserSettings()
+	if err := kv.client.KV.Get(keyUserSettings+userID, settings); err != nil {
+		return nil, errors.Wrap(err, "failed to get user settings")
+	}
+	return settings, nil
+}
+
+// SaveUserSettings saves a user's settings
+func (kv Client) SaveUserSettings(userID string, settings *UserSettings) error {
+	if _, err := kv.client.KV.Set(keyUserSettings+userID, settings); err != nil {
+		return errors.Wrap(err, "failed to save user settings")
+	}
+	return nil
+}
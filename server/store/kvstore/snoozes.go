@@ -0,0 +1,53 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const keySnooze = "snooze-"
+
+// Snooze tracks an incident snoozed from Mattermost until the snooze ends
+type Snooze struct {
+	IncidentID string    `json:"incident_id"`
+	UserID     string    `json:"user_id"`
+	Until      time.Time `json:"until"`
+}
+
+// GetSnoozes gets all tracked snoozes
+func (kv Client) GetSnoozes() ([]*Snooze, error) {
+	keys, err := kv.listKeysWithPrefix(keySnooze)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list snoozes")
+	}
+
+	snoozes := make([]*Snooze, 0, len(keys))
+	for _, key := range keys {
+		var snooze *Snooze
+		if err := kv.client.KV.Get(key, &snooze); err != nil {
+			return nil, errors.Wrap(err, "failed to get snooze")
+		}
+		if snooze != nil {
+			snoozes = append(snoozes, snooze)
+		}
+	}
+
+	return snoozes, nil
+}
+
+// SaveSnooze saves a snooze, replacing any existing one for the incident
+func (kv Client) SaveSnooze(snooze *Snooze) error {
+	if _, err := kv.client.KV.Set(keySnooze+snooze.IncidentID, snooze); err != nil {
+		return errors.Wrap(err, "failed to save snooze")
+	}
+	return nil
+}
+
+// DeleteSnooze deletes the snooze for an incident
+func (kv Client) DeleteSnooze(incidentID string) error {
+	if err := kv.client.KV.Delete(keySnooze + incidentID); err != nil {
+		return errors.Wrap(err, "failed to delete snooze")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package kvstore
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	keySubscription = "subscription-"
+
+	// Number of keys fetched per page when listing keys
+	listKeysPerPage = 1000
+)
+
+// How incidents are posted while their service is under maintenance
+const (
+	MaintenanceModeNormal   = "normal"
+	MaintenanceModeMute     = "mute"
+	MaintenanceModeSuppress = "suppress"
+	MaintenanceModeQuiet    = "quiet" // Posted muted in the configured maintenance channel instead
+)
+
+// Fields that can be shown on incident posts
+const (
+	IncidentFieldService   = "service"
+	IncidentFieldUrgency   = "urgency"
+	IncidentFieldPriority  = "priority"
+	IncidentFieldAssignees = "assignees"
+	IncidentFieldPolicy    = "escalation_policy"
+	IncidentFieldAbout     = "about"
+	IncidentFieldWarnings  = "warnings"
+	IncidentFieldCreated   = "created"
+	IncidentFieldLink      = "link"
+	IncidentFieldDetails   = "details"
+)
+
+// IncidentFields lists the fields that can be shown on incident posts, in display order
+var IncidentFields = []string{
+	IncidentFieldService,
+	IncidentFieldUrgency,
+	IncidentFieldPriority,
+	IncidentFieldAssignees,
+	IncidentFieldPolicy,
+	IncidentFieldAbout,
+	IncidentFieldWarnings,
+	IncidentFieldCreated,
+	IncidentFieldLink,
+	IncidentFieldDetails,
+}
+
+// DefaultIncidentFields are the fields shown on incident posts unless a subscription chooses others
+var DefaultIncidentFields = []string{
+	IncidentFieldService,
+	IncidentFieldUrgency,
+	IncidentFieldPriority,
+	IncidentFieldAssignees,
+	IncidentFieldPolicy,
+	IncidentFieldAbout,
+	IncidentFieldWarnings,
+	IncidentFieldCreated,
+	IncidentFieldLink,
+}
+
+// Subscription routes the incidents of a PagerDuty service to a Mattermost channel
+type Subscription struct {
+	ServiceID   string    `json:"service_id"`
+	ServiceName string    `json:"service_name"`
+	ChannelID   string    `json:"channel_id"`
+	CreatorID   string    `json:"creator_id"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Tag is the PagerDuty tag the service was subscribed through, if any
+	Tag string `json:"tag,omitempty"`
+
+	// Label is prefixed to incident post titles, e.g. "[EU-Prod]"
+	Label string `json:"label,omitempty"`
+
+	// Fields are the fields shown on incident posts, or the default fields if empty
+	Fields []string `json:"fields,omitempty"`
+
+	// MaintenanceMode controls how incidents are posted during maintenance windows
+	MaintenanceMode string `json:"maintenance_mode,omitempty"`
+}
+
+// GetSubscription gets the subscription for a service, returning nil if there is none
+func (kv Client) GetSubscription(serviceID string) (*Subscription, error) {
+	var subscription *Subscription
+	if err := kv.client.KV.Get(keySubscription+serviceID, &subscription); err != nil {
+		return nil, errors.Wrap(err, "failed to get subscription")
+	}
+	return subscription, nil
+}
+
+// GetSubscriptions gets all subscriptions
+func (kv Client) GetSubscriptions() ([]*Subscription, error) {
+	keys, err := kv.listKeysWithPrefix(keySubscription)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list subscriptions")
+	}
+
+	subscriptions := make([]*Subscription, 0, len(keys))
+	for _, key := range keys {
+		var subscription *Subscription
+		if err := kv.client.KV.Get(key, &subscription); err != nil {
+			return nil, errors.Wrap(err, "failed to get subscription")
+		}
+		if subscription != nil {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// SaveSubscription saves a subscription, replacing any existing one for the service
+func (kv Client) SaveSubscription(subscription *Subscription) error {
+	if _, err := kv.client.KV.Set(keySubscription+subscription.ServiceID, subscription); err != nil {
+		return errors.Wrap(err, "failed to save subscription")
+	}
+	return nil
+}
+
+// DeleteSubscription deletes the subscription for a service
+func (kv Client) DeleteSubscription(serviceID string) error {
+	if err := kv.client.KV.Delete(keySubscription + serviceID); err != nil {
+		return errors.Wrap(err, "failed to delete subscription")
+	}
+	return nil
+}
+
+// listKeysWithPrefix lists all keys with the given prefix
+func (kv Client) listKeysWithPrefix(prefix string) ([]string, error) {
+	var matching []string
+	for page := 0; ; page++ {
+		keys, err := kv.client.KV.ListKeys(page, listKeysPerPage)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, prefix) {
+				matching = append(matching, key)
+			}
+		}
+
+		if len(keys) < listKeysPerPage {
+			return matching, nil
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyShiftSwap = "shift_swap-"
+
+	// Pending swap proposals expire if the teammate never answers
+	shiftSwapExpiry = 14 * 24 * time.Hour
+)
+
+// ShiftSwap is a proposal to swap on-call shifts between two users on a schedule
+type ShiftSwap struct {
+	ID         string `json:"id"`
+	ScheduleID string `json:"schedule_id"`
+
+	RequesterUserID     string    `json:"requester_user_id"`
+	RequesterPDUserID   string    `json:"requester_pd_user_id"`
+	RequesterShiftStart time.Time `json:"requester_shift_start"`
+	RequesterShiftEnd   time.Time `json:"requester_shift_end"`
+
+	TargetUserID     string    `json:"target_user_id"`
+	TargetPDUserID   string    `json:"target_pd_user_id"`
+	TargetShiftStart time.Time `json:"target_shift_start"`
+	TargetShiftEnd   time.Time `json:"target_shift_end"`
+
+	// RequesterOverrideID is the override covering the requester's shift, once created, so an
+	// accepted swap whose second override failed is completed without creating it again
+	RequesterOverrideID string `json:"requester_override_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetShiftSwap gets a pending shift swap, returning nil if it doesn't exist
+func (kv Client) GetShiftSwap(swapID string) (*ShiftSwap, error) {
+	var swap *ShiftSwap
+	if err := kv.client.KV.Get(keyShiftSwap+swapID, &swap); err != nil {
+		return nil, errors.Wrap(err, "failed to get shift swap")
+	}
+	return swap, nil
+}
+
+// SaveShiftSwap saves a pending shift swap
+func (kv Client) SaveShiftSwap(swap *ShiftSwap) error {
+	if _, err := kv.client.KV.Set(keyShiftSwap+swap.ID, swap, pluginapi.SetExpiry(shiftSwapExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save shift swap")
+	}
+	return nil
+}
+
+// DeleteShiftSwap deletes a shift swap once it has been answered
+func (kv Client) DeleteShiftSwap(swapID string) error {
+	if err := kv.client.KV.Delete(keyShiftSwap + swapID); err != nil {
+		return errors.Wrap(err, "failed to delete shift swap")
+	}
+	return nil
+}
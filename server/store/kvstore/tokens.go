@@ -0,0 +1,55 @@
+package kvstore
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const keyUserToken = "user_token-"
+
+// GetUserToken gets the encrypted PagerDuty user API token a user connected, returning an empty string if none
+func (kv Client) GetUserToken(userID string) (string, error) {
+	var token string
+	if err := kv.client.KV.Get(keyUserToken+userID, &token); err != nil {
+		return "", errors.Wrap(err, "failed to get user token")
+	}
+	return token, nil
+}
+
+// GetUserTokens gets the stored PagerDuty user API tokens, by user ID
+func (kv Client) GetUserTokens() (map[string]string, error) {
+	keys, err := kv.listKeysWithPrefix(keyUserToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list user tokens")
+	}
+
+	tokens := make(map[string]string, len(keys))
+	for _, key := range keys {
+		var token string
+		if err := kv.client.KV.Get(key, &token); err != nil {
+			return nil, errors.Wrap(err, "failed to get user token")
+		}
+		if token != "" {
+			tokens[strings.TrimPrefix(key, keyUserToken)] = token
+		}
+	}
+
+	return tokens, nil
+}
+
+// SaveUserToken saves the encrypted PagerDuty user API token of a user
+func (kv Client) SaveUserToken(userID, token string) error {
+	if _, err := kv.client.KV.Set(keyUserToken+userID, token); err != nil {
+		return errors.Wrap(err, "failed to save user token")
+	}
+	return nil
+}
+
+// DeleteUserToken deletes the PagerDuty user API token of a user
+func (kv Client) DeleteUserToken(userID string) error {
+	if err := kv.client.KV.Delete(keyUserToken + userID); err != nil {
+		return errors.Wrap(err, "failed to delete user token")
+	}
+	return nil
+}
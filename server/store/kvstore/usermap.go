@@ -0,0 +1,136 @@
+package kvstore
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+const (
+	keyUserMapMattermost       = "user_map_mm-"
+	keyUserMapPagerDuty        = "user_map_pd-"
+	keyManualUserMapMattermost = "user_map_manual_mm-"
+	keyManualUserMapPagerDuty  = "user_map_manual_pd-"
+
+	// User mappings are matched by email again after this long, in case an email changed
+	userMappingExpiry = 24 * time.Hour
+)
+
+// UserMapping links a Mattermost user to a PagerDuty user, either matched by email or set
+// manually by an admin
+type UserMapping struct {
+	MattermostUserID string         `json:"mattermost_user_id"`
+	PagerDutyUser    pagerduty.User `json:"pagerduty_user"`
+
+	// Manual mappings are set by an admin and override email matching
+	Manual    bool      `json:"manual,omitempty"`
+	CreatorID string    `json:"creator_id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// GetUserMappingByMattermostID gets the mapping of a Mattermost user, preferring a manual
+// mapping over an email match. It returns nil if the user hasn't been matched.
+func (kv Client) GetUserMappingByMattermostID(userID string) (*UserMapping, error) {
+	return kv.getUserMapping(keyManualUserMapMattermost+userID, keyUserMapMattermost+userID)
+}
+
+// GetUserMappingByPagerDutyID gets the mapping of a PagerDuty user, preferring a manual
+// mapping over an email match. It returns nil if the user hasn't been matched.
+func (kv Client) GetUserMappingByPagerDutyID(pdUserID string) (*UserMapping, error) {
+	return kv.getUserMapping(keyManualUserMapPagerDuty+pdUserID, keyUserMapPagerDuty+pdUserID)
+}
+
+// getUserMapping gets the first mapping found under the given keys
+func (kv Client) getUserMapping(keys ...string) (*UserMapping, error) {
+	for _, key := range keys {
+		var mapping *UserMapping
+		if err := kv.client.KV.Get(key, &mapping); err != nil {
+			return nil, errors.Wrap(err, "failed to get user mapping")
+		}
+		if mapping != nil {
+			return mapping, nil
+		}
+	}
+	return nil, nil
+}
+
+// SaveUserMapping caches an email match under both the Mattermost and the PagerDuty user ID
+func (kv Client) SaveUserMapping(mapping *UserMapping) error {
+	if _, err := kv.client.KV.Set(keyUserMapMattermost+mapping.MattermostUserID, mapping, pluginapi.SetExpiry(userMappingExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save user mapping")
+	}
+	if _, err := kv.client.KV.Set(keyUserMapPagerDuty+mapping.PagerDutyUser.ID, mapping, pluginapi.SetExpiry(userMappingExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save user mapping")
+	}
+	return nil
+}
+
+// GetManualUserMappings gets all manual user mappings
+func (kv Client) GetManualUserMappings() ([]*UserMapping, error) {
+	keys, err := kv.listKeysWithPrefix(keyManualUserMapMattermost)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list user mappings")
+	}
+
+	mappings := make([]*UserMapping, 0, len(keys))
+	for _, key := range keys {
+		var mapping *UserMapping
+		if err := kv.client.KV.Get(key, &mapping); err != nil {
+			return nil, errors.Wrap(err, "failed to get user mapping")
+		}
+		if mapping != nil {
+			mappings = append(mappings, mapping)
+		}
+	}
+
+	return mappings, nil
+}
+
+// SaveManualUserMapping saves a manual user mapping, replacing any manual mapping of either user
+func (kv Client) SaveManualUserMapping(mapping *UserMapping) error {
+	mapping.Manual = true
+
+	// Each user can only be mapped once, so drop the other side of earlier mappings
+	if err := kv.DeleteManualUserMapping(mapping.MattermostUserID); err != nil {
+		return err
+	}
+	var previous *UserMapping
+	if err := kv.client.KV.Get(keyManualUserMapPagerDuty+mapping.PagerDutyUser.ID, &previous); err != nil {
+		return errors.Wrap(err, "failed to get user mapping")
+	}
+	if previous != nil {
+		if err := kv.DeleteManualUserMapping(previous.MattermostUserID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := kv.client.KV.Set(keyManualUserMapMattermost+mapping.MattermostUserID, mapping); err != nil {
+		return errors.Wrap(err, "failed to save user mapping")
+	}
+	if _, err := kv.client.KV.Set(keyManualUserMapPagerDuty+mapping.PagerDutyUser.ID, mapping); err != nil {
+		return errors.Wrap(err, "failed to save user mapping")
+	}
+	return nil
+}
+
+// DeleteManualUserMapping deletes the manual mapping of a Mattermost user, if any
+func (kv Client) DeleteManualUserMapping(userID string) error {
+	var mapping *UserMapping
+	if err := kv.client.KV.Get(keyManualUserMapMattermost+userID, &mapping); err != nil {
+		return errors.Wrap(err, "failed to get user mapping")
+	}
+	if mapping == nil {
+		return nil
+	}
+
+	if err := kv.client.KV.Delete(keyManualUserMapPagerDuty + mapping.PagerDutyUser.ID); err != nil {
+		return errors.Wrap(err, "failed to delete user mapping")
+	}
+	if err := kv.client.KV.Delete(keyManualUserMapMattermost + userID); err != nil {
+		return errors.Wrap(err, "failed to delete user mapping")
+	}
+	return nil
+}
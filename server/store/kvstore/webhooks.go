@@ -0,0 +1,114 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyWebhookEvent      = "webhook_event-"
+	keyWebhookDeadLetter = "webhook_dead_letter-"
+	keyWebhookStats      = "webhook_stats-"
+
+	// Webhook event IDs are remembered this long to deduplicate redeliveries
+	webhookEventExpiry = 24 * time.Hour
+
+	// Dead-lettered webhook payloads are kept this long for inspection
+	webhookDeadLetterExpiry = 7 * 24 * time.Hour
+
+	// Hourly webhook statistics are kept this long
+	webhookStatsExpiry = 8 * 24 * time.Hour
+
+	// Number of attempts to increment a webhook statistics counter
+	webhookStatsRetries = 5
+)
+
+// Webhook statistics counters
+const (
+	WebhookStatReceived     = "received"
+	WebhookStatProcessed    = "processed"
+	WebhookStatDeduplicated = "deduplicated"
+	WebhookStatFailed       = "failed"
+	WebhookStatDeadLettered = "dead_lettered"
+)
+
+// WebhookStats counts webhook events by counter
+type WebhookStats map[string]int
+
+// MarkWebhookEventSeen records a webhook event as seen. It returns false if the event was already seen.
+func (kv Client) MarkWebhookEventSeen(eventID string) (bool, error) {
+	saved, err := kv.client.KV.Set(keyWebhookEvent+eventID, true, pluginapi.SetAtomic(nil), pluginapi.SetExpiry(webhookEventExpiry))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark webhook event as seen")
+	}
+	return saved, nil
+}
+
+// UnmarkWebhookEventSeen forgets a webhook event so a redelivery is processed again
+func (kv Client) UnmarkWebhookEventSeen(eventID string) error {
+	if err := kv.client.KV.Delete(keyWebhookEvent + eventID); err != nil {
+		return errors.Wrap(err, "failed to unmark webhook event as seen")
+	}
+	return nil
+}
+
+// SaveWebhookDeadLetter keeps the payload of a webhook event that failed to process
+func (kv Client) SaveWebhookDeadLetter(eventID string, payload []byte) error {
+	if _, err := kv.client.KV.Set(keyWebhookDeadLetter+eventID, payload, pluginapi.SetExpiry(webhookDeadLetterExpiry)); err != nil {
+		return errors.Wrap(err, "failed to save webhook dead letter")
+	}
+	return nil
+}
+
+// IncrementWebhookStat increments a webhook statistics counter in the hourly bucket of the given time
+func (kv Client) IncrementWebhookStat(at time.Time, counter string) error {
+	key := webhookStatsKey(at)
+	for i := 0; i < webhookStatsRetries; i++ {
+		var oldValue []byte
+		if err := kv.client.KV.Get(key, &oldValue); err != nil {
+			return errors.Wrap(err, "failed to get webhook stats")
+		}
+
+		stats := WebhookStats{}
+		if len(oldValue) > 0 {
+			if err := json.Unmarshal(oldValue, &stats); err != nil {
+				return errors.Wrap(err, "failed to unmarshal webhook stats")
+			}
+		}
+		stats[counter]++
+
+		saved, err := kv.client.KV.Set(key, stats, pluginapi.SetAtomic(oldValue), pluginapi.SetExpiry(webhookStatsExpiry))
+		if err != nil {
+			return errors.Wrap(err, "failed to save webhook stats")
+		}
+		if saved {
+			return nil
+		}
+	}
+
+	return errors.New("failed to increment webhook stats: too many concurrent updates")
+}
+
+// GetWebhookStats sums the webhook statistics of the hourly buckets from since until now
+func (kv Client) GetWebhookStats(since time.Time) (WebhookStats, error) {
+	total := WebhookStats{}
+	for hour := since.UTC().Truncate(time.Hour); !hour.After(time.Now()); hour = hour.Add(time.Hour) {
+		var stats WebhookStats
+		if err := kv.client.KV.Get(webhookStatsKey(hour), &stats); err != nil {
+			return nil, errors.Wrap(err, "failed to get webhook stats")
+		}
+		for counter, count := range stats {
+			total[counter] += count
+		}
+	}
+
+	return total, nil
+}
+
+// webhookStatsKey returns the key of the hourly statistics bucket of a time
+func webhookStatsKey(at time.Time) string {
+	return keyWebhookStats + at.UTC().Format("2006010215")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// getIncidentChannelID gets the channel ID for posting an incident. A channel subscribed to the
+// incident's service comes first, then the service's route, then the default channel.
+func (p *Plugin) getIncidentChannelID(incident pagerduty.Incident) (string, error) {
+	subscription, err := p.kvstore.GetSubscription(incident.Service.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to get subscription, using default channel", "service_id", incident.Service.ID, "error", err.Error())
+	} else if subscription != nil {
+		return subscription.ChannelID, nil
+	}
+
+	if channelID, ok := p.getRoutedChannelID(incident.Service.ID); ok {
+		return channelID, nil
+	}
+
+	return p.getChannelID()
+}
+
+// newIncidentPostOptions gets the options of an incident post that depend on the subscription to
+// the incident's service, fetching the extra information the subscription's fields need
+func (p *Plugin) newIncidentPostOptions(incident pagerduty.Incident) incidentPostOptions {
+	var options incidentPostOptions
+
+	subscription, err := p.kvstore.GetSubscription(incident.Service.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to get subscription", "service_id", incident.Service.ID, "error", err.Error())
+	} else if subscription != nil {
+		options.Label = subscription.Label
+		options.Fields = subscription.Fields
+	}
+
+	if options.showField(kvstore.IncidentFieldAbout) {
+		options.ServiceInfo = p.getServiceInfo(incident.Service.ID)
+	}
+
+	if options.showField(kvstore.IncidentFieldDetails) {
+		options.AlertDetails = p.getAlertDetails(incident.ID)
+	}
+
+	return options
+}
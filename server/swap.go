@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/timezone"
+)
+
+const (
+	// Shift swap actions
+	SwapActionAccept  = "accept"
+	SwapActionDecline = "decline"
+)
+
+// handleSwapAction handles the Accept/Decline buttons of a shift swap proposal
+func (p *Plugin) handleSwapAction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	swapID := vars["swap_id"]
+	action := vars["action"]
+
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	t := p.translate(userID)
+
+	swap, err := p.kvstore.GetShiftSwap(swapID)
+	if err != nil {
+		p.API.LogError("Failed to get shift swap", "error", err.Error())
+		http.Error(w, "Failed to get shift swap", http.StatusInternalServerError)
+		return
+	}
+
+	if swap == nil {
+		p.writeSwapResponse(w, t("swap.error.expired"))
+		return
+	}
+
+	if swap.TargetUserID != userID {
+		http.Error(w, "Not authorized", http.StatusForbidden)
+		return
+	}
+
+	var result string
+	switch action {
+	case SwapActionAccept:
+		// The teammate covers the requester's shift and vice versa. The first override is
+		// recorded, so accepting again after the second failed only retries the second.
+		if swap.RequesterOverrideID == "" {
+			override, err := p.getPagerDutyClient().CreateOverride(swap.ScheduleID, swap.TargetPDUserID, swap.RequesterShiftStart, swap.RequesterShiftEnd)
+			if err != nil {
+				p.API.LogError("Failed to create override", "error", err.Error())
+				p.writeSwapErrorResponse(w, t("swap.error.create_override", map[string]interface{}{"Error": err.Error()}))
+				return
+			}
+
+			swap.RequesterOverrideID = override.ID
+			if err := p.kvstore.SaveShiftSwap(swap); err != nil {
+				p.API.LogWarn("Failed to save shift swap", "swap_id", swap.ID, "error", err.Error())
+			}
+		}
+
+		if _, err := p.getPagerDutyClient().CreateOverride(swap.ScheduleID, swap.RequesterPDUserID, swap.TargetShiftStart, swap.TargetShiftEnd); err != nil {
+			p.API.LogError("Failed to create override", "error", err.Error())
+			p.writeSwapErrorResponse(w, t("swap.error.create_second_override", map[string]interface{}{"Error": err.Error()}))
+			return
+		}
+
+		result = "accepted"
+
+	case SwapActionDecline:
+		// Undo the half of an accepted swap whose second override failed
+		if swap.RequesterOverrideID != "" {
+			if err := p.getPagerDutyClient().DeleteOverride(swap.ScheduleID, swap.RequesterOverrideID); err != nil {
+				p.API.LogError("Failed to delete override", "error", err.Error())
+				p.writeSwapErrorResponse(w, t("swap.error.undo_override", map[string]interface{}{"Error": err.Error()}))
+				return
+			}
+		}
+
+		result = "declined"
+
+	default:
+		http.Error(w, "Invalid action", http.StatusBadRequest)
+		return
+	}
+
+	if err := p.kvstore.DeleteShiftSwap(swap.ID); err != nil {
+		p.API.LogWarn("Failed to delete shift swap", "swap_id", swap.ID, "error", err.Error())
+	}
+
+	p.notifySwapRequester(swap, result)
+	p.writeSwapResponse(w, t("swap."+result))
+}
+
+// notifySwapRequester lets the requester know how their swap proposal was answered
+func (p *Plugin) notifySwapRequester(swap *kvstore.ShiftSwap, result string) {
+	target, appErr := p.API.GetUser(swap.TargetUserID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to get user", "user_id", swap.TargetUserID, "error", appErr.Error())
+		return
+	}
+
+	loc := time.UTC
+	t := i18n.Load(i18n.DefaultLocale)
+	if requester, appErr := p.API.GetUser(swap.RequesterUserID); appErr == nil {
+		loc = timezone.ForUser(requester)
+		t = i18n.ForUser(requester)
+	}
+
+	message := t("swap.requester."+result, map[string]interface{}{"Username": target.Username})
+	if result == "accepted" {
+		message += " " + t("swap.requester.coverage", map[string]interface{}{
+			"TheirStart": timezone.Format(swap.RequesterShiftStart, loc),
+			"TheirEnd":   timezone.Format(swap.RequesterShiftEnd, loc),
+			"YourStart":  timezone.Format(swap.TargetShiftStart, loc),
+			"YourEnd":    timezone.Format(swap.TargetShiftEnd, loc),
+		})
+	}
+
+	channel, appErr := p.API.GetDirectChannel(p.botUserID, swap.RequesterUserID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to get direct channel", "user_id", swap.RequesterUserID, "error", appErr.Error())
+		return
+	}
+
+	if _, appErr := p.API.CreatePost(&model.Post{
+		UserId:    p.botUserID,
+		ChannelId: channel.Id,
+		Message:   message,
+	}); appErr != nil {
+		p.API.LogWarn("Failed to notify swap requester", "user_id", swap.RequesterUserID, "error", appErr.Error())
+	}
+}
+
+// writeSwapErrorResponse shows an error to the teammate, keeping the swap proposal post and its
+// buttons so the answer can be retried
+func (p *Plugin) writeSwapErrorResponse(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&model.PostActionIntegrationResponse{EphemeralText: message}); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
+
+// writeSwapResponse replaces the swap proposal post with a result message
+func (p *Plugin) writeSwapResponse(w http.ResponseWriter, message string) {
+	response := &model.PostActionIntegrationResponse{
+		Update: &model.Post{
+			Message: message,
+			Props:   model.StringInterface{},
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client/mocks"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// swapStore keeps shift swaps in memory for the tests
+type swapStore struct {
+	kvstore.KVStore
+	swaps map[string]*kvstore.ShiftSwap
+}
+
+func (s *swapStore) GetShiftSwap(swapID string) (*kvstore.ShiftSwap, error) {
+	return s.swaps[swapID], nil
+}
+
+func (s *swapStore) SaveShiftSwap(swap *kvstore.ShiftSwap) error {
+	s.swaps[swap.ID] = swap
+	return nil
+}
+
+func (s *swapStore) DeleteShiftSwap(swapID string) error {
+	delete(s.swaps, swapID)
+	return nil
+}
+
+func TestHandleSwapAction(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	proposed := kvstore.ShiftSwap{
+		ID:                  "swap1",
+		ScheduleID:          "SCHED1",
+		RequesterUserID:     "requester",
+		RequesterPDUserID:   "P1",
+		RequesterShiftStart: start,
+		RequesterShiftEnd:   start.Add(24 * time.Hour),
+		TargetUserID:        "target",
+		TargetPDUserID:      "P2",
+		TargetShiftStart:    start.Add(7 * 24 * time.Hour),
+		TargetShiftEnd:      start.Add(8 * 24 * time.Hour),
+	}
+
+	// setup returns a plugin with the given swap, whose requester is notified of the answer
+	setup := func(t *testing.T, swap kvstore.ShiftSwap) (*Plugin, *plugintest.API, *mocks.MockPDClient, *swapStore) {
+		pdClient := mocks.NewMockPDClient(gomock.NewController(t))
+
+		api := &plugintest.API{}
+		for _, userID := range []string{"requester", "target", "other"} {
+			api.On("GetUser", userID).Return(&model.User{Id: userID, Username: userID, Locale: "en"}, nil).Maybe()
+		}
+		api.On("GetDirectChannel", "bot", "requester").Return(&model.Channel{Id: "dm1"}, nil).Maybe()
+		for _, args := range [][]interface{}{{mock.Anything}, {mock.Anything, mock.Anything, mock.Anything}, {mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}} {
+			api.On("LogWarn", args...).Maybe()
+			api.On("LogError", args...).Maybe()
+		}
+
+		store := &swapStore{swaps: map[string]*kvstore.ShiftSwap{swap.ID: &swap}}
+
+		p := &Plugin{pdClient: pdClient, kvstore: store, botUserID: "bot"}
+		p.SetAPI(api)
+		return p, api, pdClient, store
+	}
+
+	// answer clicks a button of the swap proposal as a user
+	answer := func(p *Plugin, userID, action string) (*httptest.ResponseRecorder, *model.PostActionIntegrationResponse) {
+		r := httptest.NewRequest(http.MethodPost, "/swap/swap1/"+action, nil)
+		r.Header.Set("Mattermost-User-ID", userID)
+		r = mux.SetURLVars(r, map[string]string{"swap_id": "swap1", "action": action})
+		w := httptest.NewRecorder()
+		p.handleSwapAction(w, r)
+
+		var response model.PostActionIntegrationResponse
+		_ = json.NewDecoder(w.Body).Decode(&response)
+		return w, &response
+	}
+
+	t.Run("creates both overrides when accepted", func(t *testing.T) {
+		p, api, pdClient, store := setup(t, proposed)
+		gomock.InOrder(
+			pdClient.EXPECT().CreateOverride("SCHED1", "P2", proposed.RequesterShiftStart, proposed.RequesterShiftEnd).Return(&pagerduty.Override{ID: "O1"}, nil),
+			pdClient.EXPECT().CreateOverride("SCHED1", "P1", proposed.TargetShiftStart, proposed.TargetShiftEnd).Return(&pagerduty.Override{ID: "O2"}, nil),
+		)
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.ChannelId == "dm1" && assert.Contains(t, post.Message, "@target accepted")
+		})).Return(&model.Post{}, nil).Once()
+
+		w, response := answer(p, "target", SwapActionAccept)
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, response.Update)
+		assert.Equal(t, "You accepted the shift swap.", response.Update.Message)
+		assert.Empty(t, store.swaps)
+		api.AssertExpectations(t)
+	})
+
+	t.Run("only retries the second override after a partial accept", func(t *testing.T) {
+		p, api, pdClient, store := setup(t, proposed)
+		pdClient.EXPECT().CreateOverride("SCHED1", "P2", gomock.Any(), gomock.Any()).Return(&pagerduty.Override{ID: "O1"}, nil)
+		pdClient.EXPECT().CreateOverride("SCHED1", "P1", gomock.Any(), gomock.Any()).Return(nil, errors.New("unavailable"))
+
+		_, response := answer(p, "target", SwapActionAccept)
+		assert.Nil(t, response.Update, "the proposal keeps its buttons")
+		assert.Contains(t, response.EphemeralText, "unavailable")
+		require.Contains(t, store.swaps, "swap1")
+		assert.Equal(t, "O1", store.swaps["swap1"].RequesterOverrideID)
+
+		pdClient.EXPECT().CreateOverride("SCHED1", "P1", gomock.Any(), gomock.Any()).Return(&pagerduty.Override{ID: "O2"}, nil)
+		api.On("CreatePost", mock.Anything).Return(&model.Post{}, nil).Once()
+
+		_, response = answer(p, "target", SwapActionAccept)
+		require.NotNil(t, response.Update)
+		assert.Equal(t, "You accepted the shift swap.", response.Update.Message)
+		assert.Empty(t, store.swaps)
+	})
+
+	t.Run("undoes half of a swap when declined", func(t *testing.T) {
+		halfDone := proposed
+		halfDone.RequesterOverrideID = "O1"
+		p, api, pdClient, store := setup(t, halfDone)
+		pdClient.EXPECT().DeleteOverride("SCHED1", "O1").Return(nil)
+		api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return assert.Contains(t, post.Message, "@target declined")
+		})).Return(&model.Post{}, nil).Once()
+
+		_, response := answer(p, "target", SwapActionDecline)
+		require.NotNil(t, response.Update)
+		assert.Equal(t, "You declined the shift swap.", response.Update.Message)
+		assert.Empty(t, store.swaps)
+		api.AssertExpectations(t)
+	})
+
+	t.Run("keeps the swap when undoing fails", func(t *testing.T) {
+		halfDone := proposed
+		halfDone.RequesterOverrideID = "O1"
+		p, _, pdClient, store := setup(t, halfDone)
+		pdClient.EXPECT().DeleteOverride("SCHED1", "O1").Return(errors.New("unavailable"))
+
+		_, response := answer(p, "target", SwapActionDecline)
+		assert.Nil(t, response.Update)
+		assert.Contains(t, response.EphemeralText, "unavailable")
+		assert.Contains(t, store.swaps, "swap1")
+	})
+
+	t.Run("declines without overrides to undo", func(t *testing.T) {
+		p, api, _, store := setup(t, proposed)
+		api.On("CreatePost", mock.Anything).Return(&model.Post{}, nil).Once()
+
+		_, response := answer(p, "target", SwapActionDecline)
+		require.NotNil(t, response.Update)
+		assert.Equal(t, "You declined the shift swap.", response.Update.Message)
+		assert.Empty(t, store.swaps)
+	})
+
+	t.Run("only lets the teammate answer", func(t *testing.T) {
+		for _, userID := range []string{"requester", "other"} {
+			p, api, _, store := setup(t, proposed)
+
+			w, _ := answer(p, userID, SwapActionAccept)
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, store.swaps, "swap1")
+			api.AssertNotCalled(t, "CreatePost", mock.Anything)
+		}
+	})
+
+	t.Run("reports answered swaps", func(t *testing.T) {
+		p, _, _, store := setup(t, proposed)
+		delete(store.swaps, "swap1")
+
+		_, response := answer(p, "target", SwapActionAccept)
+		require.NotNil(t, response.Update)
+		assert.Contains(t, response.Update.Message, "expired or was already answered")
+	})
+}
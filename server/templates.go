@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// Rendered field values up to this length are shown side by side
+const shortFieldLength = 40
+
+// incidentTemplateFuncs are the functions available to incident post templates, besides the
+// builtin ones
+var incidentTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// incidentFieldTemplate is an admin-defined field of incident posts
+type incidentFieldTemplate struct {
+	title    string
+	template *template.Template
+}
+
+// incidentTemplates are the parsed incident post templates of the configuration. A nil
+// template keeps the default layout.
+type incidentTemplates struct {
+	title  *template.Template
+	body   *template.Template
+	fields []incidentFieldTemplate
+
+	// problems describes the templates that could not be parsed
+	problems []string
+}
+
+// parseIncidentTemplates parses the incident post templates of the configuration. The field
+// templates are given as one Title=template field per line. Blank lines and lines starting with
+// # are skipped.
+func parseIncidentTemplates(c *configuration) incidentTemplates {
+	var templates incidentTemplates
+
+	parse := func(name, text string) *template.Template {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		tmpl, err := template.New(name).Funcs(incidentTemplateFuncs).Parse(text)
+		if err != nil {
+			templates.problems = append(templates.problems, err.Error())
+			return nil
+		}
+		return tmpl
+	}
+
+	templates.title = parse("title", c.IncidentTitleTemplate)
+	templates.body = parse("body", c.IncidentBodyTemplate)
+
+	for i, line := range strings.Split(c.IncidentFieldTemplates, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		title, text, ok := strings.Cut(line, "=")
+		title = strings.TrimSpace(title)
+		if !ok || title == "" || strings.TrimSpace(text) == "" {
+			templates.problems = append(templates.problems, fmt.Sprintf("line %d: expected Title=template, got %q", i+1, line))
+			continue
+		}
+
+		if tmpl := parse(fmt.Sprintf("field %q", title), text); tmpl != nil {
+			templates.fields = append(templates.fields, incidentFieldTemplate{title: title, template: tmpl})
+		}
+	}
+
+	return templates
+}
+
+// renderIncidentTemplate renders a template with an incident, trimming surrounding whitespace
+func renderIncidentTemplate(tmpl *template.Template, incident pagerduty.Incident) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, incident); err != nil {
+		return "", errors.Wrapf(err, "failed to render %s template", tmpl.Name())
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+func TestParseIncidentTemplates(t *testing.T) {
+	incident := pagerduty.Incident{
+		IncidentNumber: 42,
+		Title:          "Disk full",
+		Urgency:        "high",
+		Service:        pagerduty.Service{Name: "Payments"},
+	}
+
+	t.Run("valid templates", func(t *testing.T) {
+		templates := parseIncidentTemplates(&configuration{
+			IncidentTitleTemplate:  "{{upper .Urgency}} #{{.IncidentNumber}}: {{.Title}}",
+			IncidentBodyTemplate:   "",
+			IncidentFieldTemplates: "# team fields\nOwner = {{.Service.Name}} team\n\nEscalation Policy={{.EscalationPolicy.Name}}",
+		})
+		require.Empty(t, templates.problems)
+		assert.Nil(t, templates.body)
+
+		title, err := renderIncidentTemplate(templates.title, incident)
+		require.NoError(t, err)
+		assert.Equal(t, "HIGH #42: Disk full", title)
+
+		require.Len(t, templates.fields, 2)
+		assert.Equal(t, "Owner", templates.fields[0].title)
+		value, err := renderIncidentTemplate(templates.fields[0].template, incident)
+		require.NoError(t, err)
+		assert.Equal(t, "Payments team", value)
+
+		value, err = renderIncidentTemplate(templates.fields[1].template, incident)
+		require.NoError(t, err)
+		assert.Empty(t, value)
+	})
+
+	t.Run("invalid templates", func(t *testing.T) {
+		templates := parseIncidentTemplates(&configuration{
+			IncidentTitleTemplate:  "{{.Title",
+			IncidentFieldTemplates: "Owner\n=x\nTeam={{.Nope}\nService={{.Service.Name}}",
+		})
+		assert.Len(t, templates.problems, 4)
+		assert.Nil(t, templates.title)
+		require.Len(t, templates.fields, 1)
+		assert.Equal(t, "Service", templates.fields[0].title)
+	})
+
+	t.Run("rendering errors", func(t *testing.T) {
+		templates := parseIncidentTemplates(&configuration{IncidentTitleTemplate: "{{.Missing}}"})
+		require.Empty(t, templates.problems)
+
+		_, err := renderIncidentTemplate(templates.title, incident)
+		assert.Error(t, err)
+	})
+}
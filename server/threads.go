@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// postIncidentUpdateReply posts a status change of an incident as a reply in the thread of its post
+func (p *Plugin) postIncidentUpdateReply(message pagerduty.WebhookMessage, attachment *pagerduty.PostAttachment) {
+	if attachment.Suppressed || attachment.PostID == "" {
+		return
+	}
+
+	// Status updates are posted with their message when it can be fetched
+	if message.Event == EventIncidentStatusUpdated && p.postLatestStatusUpdate(message.Incident.ID, attachment) {
+		return
+	}
+
+	text := p.formatIncidentUpdate(message)
+	if text == "" {
+		return
+	}
+
+	if _, appErr := p.API.CreatePost(&model.Post{
+		UserId:    p.botUserID,
+		ChannelId: attachment.ChannelID,
+		RootId:    attachment.PostID,
+		Message:   text,
+	}); appErr != nil {
+		p.API.LogWarn("Failed to post incident update reply", "incident_id", message.Incident.ID, "error", appErr.Error())
+	}
+}
+
+// formatIncidentUpdate describes a status change of an incident, naming who made it if known
+func (p *Plugin) formatIncidentUpdate(message pagerduty.WebhookMessage) string {
+	t := p.displayLanguage()
+	var text string
+	switch message.Event {
+	case EventIncidentAcknowledged:
+		text = t("thread.update.acknowledged")
+	case EventIncidentResolved:
+		text = t("thread.update.resolved")
+	case EventIncidentReopened:
+		text = t("thread.update.reopened")
+	case EventIncidentReassigned, EventIncidentEscalated:
+		var names []string
+		for _, assignment := range message.Incident.Assignments {
+			names = append(names, p.formatPagerDutyUser(assignment.Assignee))
+		}
+		text = t("thread.update.reassigned")
+		if message.Event == EventIncidentEscalated {
+			text = t("thread.update.escalated")
+		}
+		if len(names) > 0 {
+			text += " " + t("thread.to", map[string]interface{}{"Target": strings.Join(names, ", ")})
+		}
+	case EventIncidentDelegated:
+		text = t("thread.update.delegated")
+		if message.Incident.EscalationPolicy.Name != "" {
+			text += " " + t("thread.to", map[string]interface{}{"Target": formatEscalationPolicy(message.Incident.EscalationPolicy)})
+		}
+	case EventIncidentStatusUpdated:
+		text = t("thread.update.status_update")
+	default:
+		return ""
+	}
+
+	if message.Agent != nil && message.Agent.Summary != "" {
+		text += " " + t("thread.by", map[string]interface{}{"Agent": message.Agent.Summary})
+	}
+
+	return text
+}
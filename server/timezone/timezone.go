@@ -0,0 +1,39 @@
+// Package timezone renders timestamps in a user's or the configured display timezone.
+package timezone
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// displayFormat is the format of timestamps shown to users
+const displayFormat = "Mon Jan 2, 2006 15:04 MST"
+
+// Format formats a timestamp for display in a timezone
+func Format(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(displayFormat)
+}
+
+// Load loads a timezone by IANA name, falling back to UTC if the name is empty or unknown
+func Load(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// ForUser returns a user's preferred timezone, falling back to UTC
+func ForUser(user *model.User) *time.Location {
+	if user == nil {
+		return time.UTC
+	}
+
+	return Load(user.GetPreferredTimezone())
+}
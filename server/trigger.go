@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/command"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+)
+
+// handleTriggerDialog handles the dialog of the trigger command, creating the incident and
+// posting its card to the channel the command was run in
+func (p *Plugin) handleTriggerDialog(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if request.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	serviceID, _ := request.Submission[command.TriggerDialogService].(string)
+	title, _ := request.Submission[command.TriggerDialogTitle].(string)
+	urgency, _ := request.Submission[command.TriggerDialogUrgency].(string)
+	description, _ := request.Submission[command.TriggerDialogDescription].(string)
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+	t := i18n.ForUser(user)
+
+	if serviceID == "" || title == "" {
+		p.writeDialogError(w, t("dialog.trigger.error.missing"))
+		return
+	}
+
+	pdClient, err := p.pdClientForUser(userID)
+	if err != nil {
+		p.writeDialogError(w, t("action.error.user_token"))
+		return
+	}
+
+	incident, err := pdClient.CreateIncident(serviceID, title, description, urgency, nil, user.Email)
+	if err != nil {
+		p.API.LogError("Failed to create incident", "error", err.Error(), "service_id", serviceID)
+		p.writeDialogError(w, t("dialog.trigger.error.create", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	p.API.LogInfo("Incident triggered from Mattermost", "incident_id", incident.ID, "user_id", userID)
+
+	// The incident's triggered webhook may arrive while it is being posted here
+	unlock, err := p.lockIncident(incident.ID)
+	if err != nil {
+		p.API.LogError("Failed to lock incident", "incident_id", incident.ID, "error", err.Error())
+		p.writeDialogError(w, t("dialog.trigger.error.post", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL}))
+		return
+	}
+	defer unlock()
+
+	if attachment, err := p.getIncidentAttachment(incident.ID); err == nil && attachment != nil {
+		// Already posted from the webhook
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if _, err := p.postIncident(*incident, request.ChannelId, ""); err != nil {
+		p.API.LogError("Failed to post triggered incident", "incident_id", incident.ID, "error", err.Error())
+		p.API.SendEphemeralPost(userID, &model.Post{
+			UserId:    p.botUserID,
+			ChannelId: request.ChannelId,
+			Message:   t("dialog.trigger.error.post", map[string]interface{}{"Number": incident.IncidentNumber, "URL": incident.HTMLURL}),
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeDialogError responds to a dialog submission with an error shown in the dialog
+func (p *Plugin) writeDialogError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&model.SubmitDialogResponse{Error: message}); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
+
+// writeDialogErrors responds to a dialog submission with errors shown next to its fields
+func (p *Plugin) writeDialogErrors(w http.ResponseWriter, fieldErrors map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&model.SubmitDialogResponse{Errors: fieldErrors}); err != nil {
+		p.API.LogError("Failed to encode JSON response", "error", err.Error())
+	}
+}
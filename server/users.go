@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// getMattermostUser finds the Mattermost account for a PagerDuty user, using the mapping set
+// by an admin or else matching by email and caching the match. It returns nil without an
+// error if no matching account exists.
+func (p *Plugin) getMattermostUser(pdUser pagerduty.User) (*model.User, error) {
+	mapping, err := p.kvstore.GetUserMappingByPagerDutyID(pdUser.ID)
+	if err != nil {
+		p.API.LogWarn("Failed to get user mapping", "pd_user_id", pdUser.ID, "error", err.Error())
+	}
+	if mapping != nil {
+		user, appErr := p.API.GetUser(mapping.MattermostUserID)
+		switch {
+		case appErr == nil && mapping.Manual:
+			return user, nil
+		case appErr == nil && strings.EqualFold(user.Email, mapping.PagerDutyUser.Email):
+			return p.unlessMappedElsewhere(user, pdUser.ID), nil
+		}
+		// The user may have been deleted or changed their email, so match by email again
+	}
+
+	// User references, such as incident assignees, don't include the email
+	if pdUser.Email == "" {
+		fullUser, err := p.getPagerDutyUserByID(pdUser.ID)
+		if err != nil {
+			return nil, err
+		}
+		pdUser = *fullUser
+	}
+	if pdUser.Email == "" {
+		return nil, errors.Errorf("PagerDuty user %s has no email", pdUser.ID)
+	}
+
+	user, appErr := p.API.GetUserByEmail(pdUser.Email)
+	if appErr != nil {
+		if appErr.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.New("failed to get user by email: " + appErr.Error())
+	}
+
+	if err := p.kvstore.SaveUserMapping(&kvstore.UserMapping{MattermostUserID: user.Id, PagerDutyUser: pdUser}); err != nil {
+		p.API.LogWarn("Failed to save user mapping", "pd_user_id", pdUser.ID, "error", err.Error())
+	}
+
+	return p.unlessMappedElsewhere(user, pdUser.ID), nil
+}
+
+// unlessMappedElsewhere returns the user matched to a PagerDuty user by email, or nil if an
+// admin has mapped the user to a different PagerDuty user
+func (p *Plugin) unlessMappedElsewhere(user *model.User, pdUserID string) *model.User {
+	mapping, err := p.kvstore.GetUserMappingByMattermostID(user.Id)
+	if err != nil {
+		p.API.LogWarn("Failed to get user mapping", "user_id", user.Id, "error", err.Error())
+		return user
+	}
+	if mapping != nil && mapping.Manual && mapping.PagerDutyUser.ID != pdUserID {
+		return nil
+	}
+	return user
+}
+
+// formatPagerDutyUser returns an @mention of the Mattermost account matching a PagerDuty
+// user, falling back to the PagerDuty name if there is no match
+func (p *Plugin) formatPagerDutyUser(pdUser pagerduty.User) string {
+	user, err := p.getMattermostUser(pdUser)
+	if err != nil {
+		p.API.LogDebug("Failed to match PagerDuty user", "pd_user_id", pdUser.ID, "error", err.Error())
+	}
+	if user == nil {
+		return pdUser.DisplayName()
+	}
+	return "@" + user.Username
+}
+
+// getPagerDutyUser finds the PagerDuty account of a Mattermost user, using the mapping set by an
+// admin or else matching by email. It returns nil without an error if no matching account exists.
+func (p *Plugin) getPagerDutyUser(user *model.User) (*pagerduty.User, error) {
+	mapping, err := p.kvstore.GetUserMappingByMattermostID(user.Id)
+	if err != nil {
+		p.API.LogWarn("Failed to get user mapping", "user_id", user.Id, "error", err.Error())
+	}
+	if mapping != nil && (mapping.Manual || strings.EqualFold(mapping.PagerDutyUser.Email, user.Email)) {
+		pdUser := mapping.PagerDutyUser
+		return &pdUser, nil
+	}
+
+	pdUser, err := p.getPagerDutyClient().GetUserByEmail(user.Email)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find PagerDuty user")
+	}
+
+	return pdUser, nil
+}
+
+// getPagerDutyUserByID gets a PagerDuty user with their contact methods and notification rules,
+// from the cache if possible
+func (p *Plugin) getPagerDutyUserByID(pdUserID string) (*pagerduty.User, error) {
+	user, err := p.kvstore.GetCachedPagerDutyUser(pdUserID)
+	if err != nil {
+		p.API.LogWarn("Failed to get cached PagerDuty user", "pd_user_id", pdUserID, "error", err.Error())
+	}
+	if user != nil {
+		return user, nil
+	}
+
+	user, err = p.getPagerDutyClient().GetUser(pdUserID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get PagerDuty user %s", pdUserID)
+	}
+
+	if err := p.kvstore.SaveCachedPagerDutyUser(user); err != nil {
+		p.API.LogWarn("Failed to cache PagerDuty user", "pd_user_id", pdUserID, "error", err.Error())
+	}
+
+	return user, nil
+}
+
+// translate returns the translate function of a user's language, falling back to English if the
+// user can't be found
+func (p *Plugin) translate(userID string) i18n.TranslateFunc {
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to get user", "user_id", userID, "error", appErr.Error())
+		return i18n.Load(i18n.DefaultLocale)
+	}
+
+	return i18n.ForUser(user)
+}
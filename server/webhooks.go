@@ -0,0 +1,33 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/store/kvstore"
+)
+
+// recordWebhookStat increments a webhook delivery statistics counter
+func (p *Plugin) recordWebhookStat(counter string) {
+	if err := p.kvstore.IncrementWebhookStat(time.Now(), counter); err != nil {
+		p.API.LogWarn("Failed to record webhook statistics", "counter", counter, "error", err.Error())
+	}
+}
+
+// deadLetterWebhookEvent keeps the payload of a webhook event that failed to process, and
+// forgets the event so a redelivery by PagerDuty is processed again
+func (p *Plugin) deadLetterWebhookEvent(eventID string, payload []byte) {
+	if eventID == "" {
+		return
+	}
+
+	if err := p.kvstore.UnmarkWebhookEventSeen(eventID); err != nil {
+		p.API.LogWarn("Failed to unmark webhook event", "event_id", eventID, "error", err.Error())
+	}
+
+	if err := p.kvstore.SaveWebhookDeadLetter(eventID, payload); err != nil {
+		p.API.LogWarn("Failed to dead-letter webhook event", "event_id", eventID, "error", err.Error())
+		return
+	}
+
+	p.recordWebhookStat(kvstore.WebhookStatDeadLettered)
+}
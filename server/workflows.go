@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/client"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/i18n"
+	"github.com/mnzsyu/mattermost-pagerduty-plugin/server/pagerduty"
+)
+
+// getIncidentWorkflows gets the incident workflows of the PagerDuty account, cached for an hour
+func (p *Plugin) getIncidentWorkflows() ([]pagerduty.IncidentWorkflow, error) {
+	workflows, err := p.kvstore.GetCachedIncidentWorkflows()
+	if err != nil {
+		p.API.LogWarn("Failed to get cached incident workflows", "error", err.Error())
+	}
+	if workflows != nil {
+		return workflows, nil
+	}
+
+	workflows, err = p.getPagerDutyClient().ListIncidentWorkflows(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list incident workflows")
+	}
+
+	if err := p.kvstore.SaveCachedIncidentWorkflows(workflows); err != nil {
+		p.API.LogWarn("Failed to cache incident workflows", "error", err.Error())
+	}
+
+	return workflows, nil
+}
+
+// getWorkflowOptions gets the incident workflows that can be run on an incident, which are none
+// if the account has no workflows or can't list them
+func (p *Plugin) getWorkflowOptions(incident pagerduty.Incident) []*model.PostActionOptions {
+	workflows, err := p.getIncidentWorkflows()
+	if err != nil {
+		p.API.LogDebug("Failed to get incident workflows for workflow options", "incident_id", incident.ID, "error", err.Error())
+		return nil
+	}
+
+	sort.Slice(workflows, func(i, j int) bool {
+		return strings.ToLower(workflows[i].Name) < strings.ToLower(workflows[j].Name)
+	})
+
+	var options []*model.PostActionOptions
+	for _, workflow := range workflows {
+		options = append(options, &model.PostActionOptions{Text: workflow.Name, Value: workflow.ID})
+	}
+	return options
+}
+
+// performRunWorkflow runs an incident workflow on an incident
+func (p *Plugin) performRunWorkflow(w http.ResponseWriter, pdClient client.PDClient, incidentID, workflowID string, user *model.User) {
+	if workflowID == "" {
+		http.Error(w, "Missing workflow", http.StatusBadRequest)
+		return
+	}
+
+	name := workflowID
+	if workflows, err := p.getIncidentWorkflows(); err == nil {
+		for _, workflow := range workflows {
+			if workflow.ID == workflowID {
+				name = workflow.Name
+			}
+		}
+	}
+
+	t := i18n.ForUser(user)
+	text := t("action.incident.workflow_started", map[string]interface{}{"Workflow": name})
+	if _, err := pdClient.StartIncidentWorkflow(workflowID, incidentID, user.Email); err != nil {
+		p.API.LogError("Failed to start incident workflow", "incident_id", incidentID, "workflow_id", workflowID, "error", err.Error())
+		text = t("action.error.start_workflow", map[string]interface{}{"Error": err.Error()})
+	} else {
+		p.API.LogInfo("Incident workflow started from Mattermost", "incident_id", incidentID, "workflow_id", workflowID)
+	}
+
+	p.writeActionResponse(w, &model.PostActionIntegrationResponse{
+		EphemeralText: text,
+	})
+}